@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
 	"github.com/idena-network/idena-go/blockchain"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
 	"github.com/idena-network/idena-go/consensus"
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/core/mempool"
@@ -13,6 +15,7 @@ import (
 	"github.com/idena-network/idena-go/keystore"
 	"github.com/idena-network/idena-go/log"
 	"github.com/idena-network/idena-go/secstore"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -29,6 +32,43 @@ type BaseTxArgs struct {
 	Epoch uint16 `json:"epoch"`
 }
 
+// StateProof is a proof of a state tree value against the state root of the block
+type StateProof struct {
+	Height    uint64        `json:"height"`
+	BlockHash common.Hash   `json:"blockHash"`
+	Root      common.Hash   `json:"root"`
+	Proof     hexutil.Bytes `json:"proof"`
+}
+
+func newStateProof(header *types.Header, proof []byte) StateProof {
+	return StateProof{
+		Height:    header.Height(),
+		BlockHash: header.Hash(),
+		Root:      header.Root(),
+		Proof:     proof,
+	}
+}
+
+// BlockRef refers to a block either by height or by hash
+type BlockRef struct {
+	Height *uint64
+	Hash   *common.Hash
+}
+
+func (r *BlockRef) UnmarshalJSON(data []byte) error {
+	var height uint64
+	if err := json.Unmarshal(data, &height); err == nil {
+		r.Height = &height
+		return nil
+	}
+	var hash common.Hash
+	if err := json.Unmarshal(data, &hash); err != nil {
+		return errors.New("block height or hash is expected")
+	}
+	r.Hash = &hash
+	return nil
+}
+
 func NewBaseApi(engine *consensus.Engine, txpool *mempool.TxPool, ks *keystore.KeyStore, secStore *secstore.SecStore, ipfs ipfs.Proxy) *BaseApi {
 	return &BaseApi{engine, txpool, ks, secStore, ipfs}
 }
@@ -41,6 +81,72 @@ func (api *BaseApi) getReadonlyAppState() *appstate.AppState {
 	return state
 }
 
+// isEmpty returns true if the reference doesn't point to any block, the head block is used then
+func (r *BlockRef) isEmpty() bool {
+	return r == nil || r.Hash == nil && r.Height == nil
+}
+
+// getReadonlyAppStateAt returns the state after applying the referenced block or the head state if block is nil
+func (api *BaseApi) getReadonlyAppStateAt(bc *blockchain.Blockchain, block *BlockRef) (*appstate.AppState, error) {
+	if block.isEmpty() {
+		return api.getReadonlyAppState(), nil
+	}
+	height, err := blockRefHeight(bc, block)
+	if err != nil {
+		return nil, err
+	}
+	return api.readonlyAppStateAt(height)
+}
+
+// getReadonlyAppStateWithHeaderAt returns the state after applying the referenced block and the header of the block,
+// the head block is used if block is nil
+func (api *BaseApi) getReadonlyAppStateWithHeaderAt(bc *blockchain.Blockchain, block *BlockRef) (*appstate.AppState, *types.Header, error) {
+	height := bc.Head.Height()
+	if !block.isEmpty() {
+		var err error
+		if height, err = blockRefHeight(bc, block); err != nil {
+			return nil, nil, err
+		}
+	}
+	header := bc.GetBlockHeaderByHeight(height)
+	if header == nil {
+		return nil, nil, errors.New("block is not found")
+	}
+	appState, err := api.readonlyAppStateAt(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	return appState, header, nil
+}
+
+func (api *BaseApi) readonlyAppStateAt(height uint64) (*appstate.AppState, error) {
+	appState, err := api.engine.ReadonlyAppStateAt(height)
+	if err != nil {
+		return nil, errors.Errorf("state at height %v is not available", height)
+	}
+	return appState, nil
+}
+
+func blockRefHeight(bc *blockchain.Blockchain, block *BlockRef) (uint64, error) {
+	var height uint64
+	if block.Hash != nil {
+		header := bc.GetBlockHeader(*block.Hash)
+		if header == nil {
+			return 0, errors.New("block is not found")
+		}
+		if canonical := bc.GetBlockHeaderByHeight(header.Height()); canonical == nil || canonical.Hash() != *block.Hash {
+			return 0, errors.New("block is not canonical")
+		}
+		height = header.Height()
+	} else {
+		height = *block.Height
+	}
+	if height > bc.Head.Height() {
+		return 0, errors.New("block is not found")
+	}
+	return height, nil
+}
+
 func (api *BaseApi) getAppStateForCheck() *appstate.AppState {
 	state, err := api.engine.AppStateForCheck()
 	if err != nil {
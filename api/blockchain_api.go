@@ -1,24 +1,32 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"github.com/idena-network/idena-go/blockchain"
 	"github.com/idena-network/idena-go/blockchain/fee"
 	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/blockchain/validation"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/core/mempool"
+	"github.com/idena-network/idena-go/core/state"
 	"github.com/idena-network/idena-go/ipfs"
 	"github.com/idena-network/idena-go/keywords"
 	"github.com/idena-network/idena-go/protocol"
 	"github.com/idena-network/idena-go/rlp"
 	"github.com/idena-network/idena-go/vm"
 	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"math/big"
 	"sort"
 )
 
+const maxSimulatedTxs = 20
+
 var (
 	txTypeMap = map[types.TxType]string{
 		types.SendTx:               "send",
@@ -196,6 +204,11 @@ type Syncing struct {
 	HighestBlock uint64 `json:"highestBlock"`
 	WrongTime    bool   `json:"wrongTime"`
 	GenesisBlock uint64 `json:"genesisBlock"`
+	Phase        string `json:"phase,omitempty"`
+	BytesLoaded  int64  `json:"bytesLoaded,omitempty"`
+	BytesTotal   int64  `json:"bytesTotal,omitempty"`
+	// estimated seconds to complete the current phase
+	Eta int64 `json:"eta,omitempty"`
 }
 
 func (api *BlockchainApi) Syncing() Syncing {
@@ -208,12 +221,17 @@ func (api *BlockchainApi) Syncing() Syncing {
 	if !isSyncing {
 		highest = current
 	}
+	status := api.d.SyncStatus()
 	return Syncing{
 		Syncing:      isSyncing,
 		GenesisBlock: api.bc.GenesisInfo().Genesis.Height(),
 		CurrentBlock: current,
 		HighestBlock: highest,
 		WrongTime:    api.pm.WrongTime(),
+		Phase:        status.Phase,
+		BytesLoaded:  status.BytesLoaded,
+		BytesTotal:   status.BytesTotal,
+		Eta:          int64(status.Eta.Seconds()),
 	}
 }
 
@@ -299,7 +317,7 @@ func (api *BlockchainApi) GetRawTx(args SendTxArgs) (hexutil.Bytes, error) {
 	return data, nil
 }
 
-func (api *BlockchainApi) EstimateRawTx(bytesTx hexutil.Bytes) (*EstimateRawTxResponse, error) {
+func (api *BlockchainApi) EstimateRawTx(ctx context.Context, bytesTx hexutil.Bytes) (*EstimateRawTxResponse, error) {
 	tx := new(types.Transaction)
 	if err := tx.FromBytes(bytesTx); err != nil {
 		return nil, err
@@ -316,13 +334,13 @@ func (api *BlockchainApi) EstimateRawTx(bytesTx hexutil.Bytes) (*EstimateRawTxRe
 
 	if tx.Type == types.CallContractTx || tx.Type == types.DeployContractTx || tx.Type == types.TerminateContractTx {
 		appState := api.baseApi.getAppStateForCheck()
-		vm := vm.NewVmImpl(appState, api.bc.Head, nil, api.bc.Config())
+		contractVm := vm.NewInterruptibleVmImpl(ctx, appState, api.bc.Head, api.bc.Config())
 		if tx.Type == types.CallContractTx && !common.ZeroOrNil(tx.Amount) {
 			sender, _ := types.Sender(tx)
 			appState.State.SubBalance(sender, tx.Amount)
 			appState.State.AddBalance(*tx.To, tx.Amount)
 		}
-		r := vm.Run(tx, -1)
+		r := contractVm.Run(tx, vm.ReadGasLimit)
 		r.GasCost = api.bc.GetGasCost(appState, r.GasUsed)
 		receipt := convertReceipt(tx, r, appState.State.FeePerGas())
 		response.Receipt = receipt
@@ -353,6 +371,214 @@ func (api *BlockchainApi) EstimateTx(args SendTxArgs) (*EstimateTxResponse, erro
 	}, nil
 }
 
+// SimulateArgs is a single tx or a list of txs to simulate
+type SimulateArgs []SendTxArgs
+
+func (args *SimulateArgs) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(data, (*[]SendTxArgs)(args))
+	}
+	var tx SendTxArgs
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return err
+	}
+	*args = SimulateArgs{tx}
+	return nil
+}
+
+type SimulatedTx struct {
+	TxHash  common.Hash     `json:"txHash"`
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error,omitempty"`
+	TxFee   decimal.Decimal `json:"txFee"`
+	Receipt *TxReceipt      `json:"receipt,omitempty"`
+	Events  []*Event        `json:"events,omitempty"`
+	Deltas  []*StateDelta   `json:"deltas"`
+}
+
+// StateDelta is the change of an address state caused by a simulated tx
+type StateDelta struct {
+	Address common.Address  `json:"address"`
+	Balance decimal.Decimal `json:"balance"`
+	Stake   decimal.Decimal `json:"stake"`
+	// PrevState and State are set if the identity state is changed
+	PrevState string `json:"prevState,omitempty"`
+	State     string `json:"state,omitempty"`
+	// PendingDelegatee is set if the tx toggles delegation, the empty address means undelegation
+	PendingDelegatee *common.Address `json:"pendingDelegatee,omitempty"`
+}
+
+// Simulate applies unsigned txs in order to the pending state (the head state with mempool txs of the senders) and
+// returns their receipts, state deltas and events. Nothing is broadcast, the simulation stops at the first tx which can't be applied.
+func (api *BlockchainApi) Simulate(ctx context.Context, args SimulateArgs) ([]*SimulatedTx, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no txs to simulate")
+	}
+	if len(args) > maxSimulatedTxs {
+		return nil, errors.Errorf("too many txs, max %v", maxSimulatedTxs)
+	}
+	// only txs of the senders affect their nonces and balances, so the rest of the mempool isn't applied
+	var mempoolTxs []*types.Transaction
+	senders := make(map[common.Address]struct{})
+	for _, txArgs := range args {
+		if _, ok := senders[txArgs.From]; ok {
+			continue
+		}
+		senders[txArgs.From] = struct{}{}
+		txs := api.pool.GetPendingByAddress(txArgs.From)
+		sort.SliceStable(txs, func(i, j int) bool {
+			return txs[i].AccountNonce < txs[j].AccountNonce
+		})
+		mempoolTxs = append(mempoolTxs, txs...)
+	}
+	return simulateTxs(ctx, api.bc, api.baseApi.getAppStateForCheck(), api.baseApi.getAppStateForCheck(), mempoolTxs, args), nil
+}
+
+// simulateTxs applies mempool txs and then the simulated ones to appState, head is the untouched head state
+// which provides the previous values of the addresses changed for the first time, contract code is interrupted once ctx is done
+func simulateTxs(ctx context.Context, bc *blockchain.Blockchain, head, appState *appstate.AppState, mempoolTxs []*types.Transaction, args SimulateArgs) []*SimulatedTx {
+	for _, tx := range mempoolTxs {
+		bc.ApplyTxOnState(appState, vm.NewInterruptibleVmImpl(ctx, appState, bc.Head, bc.Config()), tx)
+	}
+	snapshot := takeStateSnapshot(head, appState)
+
+	var result []*SimulatedTx
+	for _, txArgs := range args {
+		tx := buildSimulatedTx(appState, txArgs)
+		simulated := &SimulatedTx{
+			TxHash: tx.Hash(),
+			Type:   txTypeMap[tx.Type],
+			From:   txArgs.From,
+		}
+		result = append(result, simulated)
+
+		minFeePerGas := fee.GetFeePerGasForNetwork(appState.ValidatorsCache.NetworkSize())
+		if err := validation.ValidateTx(appState, tx, minFeePerGas, validation.InBlockTx); err != nil {
+			simulated.Error = err.Error()
+			break
+		}
+		txFee, receipt, err := bc.ApplyTxOnState(appState, vm.NewInterruptibleVmImpl(ctx, appState, bc.Head, bc.Config()), tx)
+		if err != nil {
+			simulated.Error = err.Error()
+			break
+		}
+		simulated.Success = true
+		simulated.TxFee = blockchain.ConvertToFloat(txFee)
+		if receipt != nil {
+			simulated.Receipt = convertReceipt(tx, receipt, appState.State.FeePerGas())
+			simulated.Success = receipt.Success
+			for _, e := range receipt.Events {
+				event := &Event{Contract: e.Contract, Event: e.EventName}
+				for _, data := range e.Data {
+					event.Args = append(event.Args, data)
+				}
+				simulated.Events = append(simulated.Events, event)
+			}
+		}
+		next := takeStateSnapshot(head, appState)
+		simulated.Deltas = stateDeltas(snapshot, next, txArgs.From)
+		snapshot = next
+	}
+	return result
+}
+
+func buildSimulatedTx(appState *appstate.AppState, args SendTxArgs) *types.Transaction {
+	var payload []byte
+	if args.Payload != nil {
+		payload = *args.Payload
+	}
+	// the nonce cache doesn't know about previously simulated txs
+	nonce := args.Nonce
+	if nonce == 0 {
+		nonce = appState.State.GetNonce(args.From)
+		if appState.State.GetEpoch(args.From) < appState.State.Epoch() {
+			nonce = 0
+		}
+		nonce++
+	}
+	tx := blockchain.BuildTxWithFeeEstimating(appState, args.From, args.To, args.Type, args.Amount, args.MaxFee, args.Tips, nonce, args.Epoch, payload)
+	return types.NewSimulatedTx(tx, args.From)
+}
+
+// addressSnapshot is the part of the address state reported by state deltas
+type addressSnapshot struct {
+	balance    *big.Int
+	stake      *big.Int
+	state      state.IdentityState
+	delegation *state.Delegation
+}
+
+// newAddressSnapshot copies the values since state objects may be changed in place by the next txs
+func newAddressSnapshot(appState *appstate.AppState, addr common.Address) *addressSnapshot {
+	snapshot := &addressSnapshot{
+		balance: new(big.Int).Set(appState.State.GetBalance(addr)),
+		stake:   new(big.Int).Set(appState.State.GetStakeBalance(addr)),
+		state:   appState.State.GetIdentityState(addr),
+	}
+	if delegation := appState.State.DelegationSwitch(addr); delegation != nil {
+		value := *delegation
+		snapshot.delegation = &value
+	}
+	return snapshot
+}
+
+// stateSnapshot keeps values of the addresses changed since the head, the rest of the addresses have their head values
+type stateSnapshot struct {
+	head      *appstate.AppState
+	addresses map[common.Address]*addressSnapshot
+}
+
+func takeStateSnapshot(head, appState *appstate.AppState) *stateSnapshot {
+	snapshot := &stateSnapshot{head: head, addresses: make(map[common.Address]*addressSnapshot)}
+	for _, addr := range appState.State.DirtyAddresses() {
+		snapshot.addresses[addr] = newAddressSnapshot(appState, addr)
+	}
+	return snapshot
+}
+
+func (s *stateSnapshot) get(addr common.Address) *addressSnapshot {
+	if value, ok := s.addresses[addr]; ok {
+		return value
+	}
+	return newAddressSnapshot(s.head, addr)
+}
+
+func stateDeltas(prev, next *stateSnapshot, sender common.Address) []*StateDelta {
+	result := make([]*StateDelta, 0)
+	addresses := make([]common.Address, 0, len(next.addresses))
+	for addr := range next.addresses {
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+	for _, addr := range addresses {
+		prevValue, value := prev.get(addr), next.addresses[addr]
+		delta := &StateDelta{
+			Address: addr,
+			Balance: blockchain.ConvertToFloat(new(big.Int).Sub(value.balance, prevValue.balance)),
+			Stake:   blockchain.ConvertToFloat(new(big.Int).Sub(value.stake, prevValue.stake)),
+		}
+		if prevValue.state != value.state {
+			delta.PrevState, delta.State = convertIdentityState(prevValue.state), convertIdentityState(value.state)
+		}
+		if addr == sender {
+			delegation, prevDelegation := value.delegation, prevValue.delegation
+			if delegation != nil && (prevDelegation == nil || *delegation != *prevDelegation) {
+				delegatee := delegation.Delegatee
+				delta.PendingDelegatee = &delegatee
+			}
+		}
+		if delta.Balance.IsZero() && delta.Stake.IsZero() && delta.State == "" && delta.PendingDelegatee == nil {
+			continue
+		}
+		result = append(result, delta)
+	}
+	return result
+}
+
 func (api *BlockchainApi) Transactions(args TransactionsArgs) Transactions {
 
 	txs, nextToken := api.bc.ReadTxs(args.Address, args.Count, args.Token)
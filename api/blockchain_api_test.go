@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestSimulateTxs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	balance := new(big.Int).Mul(common.DnaBase, big.NewInt(1000))
+	chain, appState, _, _ := blockchain.NewTestBlockchain(true, map[common.Address]config.GenesisAllocation{
+		sender: {Balance: balance},
+	})
+
+	mempoolRecipient, recipient := common.Address{0x1}, common.Address{0x2}
+	mempoolTx, _ := types.SignTx(&types.Transaction{
+		Type:         types.SendTx,
+		AccountNonce: 1,
+		To:           &mempoolRecipient,
+		Amount:       common.DnaBase,
+		MaxFee:       new(big.Int).Mul(common.DnaBase, big.NewInt(50)),
+	}, key)
+
+	head, err := appState.ForCheck(chain.Head.Height())
+	require.NoError(t, err)
+	pending, err := appState.ForCheck(chain.Head.Height())
+	require.NoError(t, err)
+
+	result := simulateTxs(context.Background(), chain.Blockchain, head, pending, []*types.Transaction{mempoolTx}, SimulateArgs{
+		{Type: types.SendTx, From: sender, To: &recipient, Amount: decimal.NewFromInt(2), MaxFee: decimal.NewFromInt(50)},
+		{Type: types.SendTx, From: sender, To: &recipient, Amount: decimal.NewFromInt(3), MaxFee: decimal.NewFromInt(50)},
+		{Type: types.SendTx, From: sender, To: &recipient, Amount: decimal.NewFromInt(100000), MaxFee: decimal.NewFromInt(50)},
+	})
+	require.Len(t, result, 3)
+
+	// the mempool tx is applied once, so the simulated txs get the next nonces
+	require.Equal(t, uint32(3), pending.State.GetNonce(sender))
+	require.Equal(t, new(big.Int).Mul(common.DnaBase, big.NewInt(5)), pending.State.GetBalance(recipient))
+	require.Equal(t, common.DnaBase, pending.State.GetBalance(mempoolRecipient))
+
+	for i, amount := range []int64{2, 3} {
+		simulated := result[i]
+		require.True(t, simulated.Success, simulated.Error)
+		require.Empty(t, simulated.Error)
+		require.Len(t, simulated.Deltas, 2)
+
+		// changes of the mempool tx and the previous simulated tx aren't reported
+		deltas := make(map[common.Address]*StateDelta)
+		for _, delta := range simulated.Deltas {
+			deltas[delta.Address] = delta
+		}
+		require.NotContains(t, deltas, mempoolRecipient)
+		require.True(t, decimal.NewFromInt(amount).Equal(deltas[recipient].Balance))
+		require.True(t, decimal.NewFromInt(-amount).Sub(simulated.TxFee).Equal(deltas[sender].Balance))
+	}
+
+	require.False(t, result[2].Success)
+	require.NotEmpty(t, result[2].Error)
+	require.Empty(t, result[2].Deltas)
+}
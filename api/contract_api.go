@@ -5,19 +5,26 @@ import (
 	"github.com/idena-network/idena-go/blockchain"
 	"github.com/idena-network/idena-go/blockchain/attachments"
 	"github.com/idena-network/idena-go/blockchain/fee"
+	"github.com/idena-network/idena-go/blockchain/pst"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/blockchain/validation"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/database"
 	"github.com/idena-network/idena-go/deferredtx"
 	"github.com/idena-network/idena-go/subscriptions"
 	"github.com/idena-network/idena-go/vm"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/idena-network/idena-go/vm/embedded"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
+	"github.com/idena-network/idena-go/vm/wasm"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"math/big"
-	"strconv"
 )
 
 type ContractApi struct {
@@ -32,12 +39,20 @@ func NewContractApi(baseApi *BaseApi, bc *blockchain.Blockchain, deferredTxs *de
 	return &ContractApi{baseApi: baseApi, bc: bc, deferredTxs: deferredTxs, subManager: subManager}
 }
 
+// multisigInitialized is the state of the multisig contract when all the voters are added
+const multisigInitialized = byte(2)
+
+// maxEventsPageSize is the max number of events returned by contract_events at once
+const maxEventsPageSize = 100
+
 type DeployArgs struct {
-	From     common.Address  `json:"from"`
-	CodeHash hexutil.Bytes   `json:"codeHash"`
-	Amount   decimal.Decimal `json:"amount"`
-	Args     DynamicArgs     `json:"args"`
-	MaxFee   decimal.Decimal `json:"maxFee"`
+	From      common.Address  `json:"from"`
+	CodeHash  hexutil.Bytes   `json:"codeHash"`
+	Code      hexutil.Bytes   `json:"code"`
+	Amount    decimal.Decimal `json:"amount"`
+	Args      DynamicArgs     `json:"args"`
+	NamedArgs NamedArgs       `json:"namedArgs"`
+	MaxFee    decimal.Decimal `json:"maxFee"`
 }
 
 type CallArgs struct {
@@ -46,17 +61,22 @@ type CallArgs struct {
 	Method         string          `json:"method"`
 	Amount         decimal.Decimal `json:"amount"`
 	Args           DynamicArgs     `json:"args"`
+	NamedArgs      NamedArgs       `json:"namedArgs"`
 	MaxFee         decimal.Decimal `json:"maxFee"`
 	BroadcastBlock uint64          `json:"broadcastBlock"`
 }
 
 type TerminateArgs struct {
-	From     common.Address  `json:"from"`
-	Contract common.Address  `json:"contract"`
-	Args     DynamicArgs     `json:"args"`
-	MaxFee   decimal.Decimal `json:"maxFee"`
+	From      common.Address  `json:"from"`
+	Contract  common.Address  `json:"contract"`
+	Args      DynamicArgs     `json:"args"`
+	NamedArgs NamedArgs       `json:"namedArgs"`
+	MaxFee    decimal.Decimal `json:"maxFee"`
 }
 
+// NamedArgs are arg values by their names in the contract ABI, values have the format of the ABI arg type
+type NamedArgs map[string]string
+
 type DynamicArgs []*DynamicArg
 
 type DynamicArg struct {
@@ -66,70 +86,36 @@ type DynamicArg struct {
 }
 
 type ReadonlyCallArgs struct {
-	Contract common.Address `json:"contract"`
-	Method   string         `json:"method"`
-	Format   string         `json:"format"`
-	Args     DynamicArgs    `json:"args"`
+	Contract  common.Address `json:"contract"`
+	Method    string         `json:"method"`
+	Format    string         `json:"format"`
+	Args      DynamicArgs    `json:"args"`
+	NamedArgs NamedArgs      `json:"namedArgs"`
+}
+
+type AbiArgs struct {
+	Contract *common.Address `json:"contract"`
+	CodeHash hexutil.Bytes   `json:"codeHash"`
 }
 
 type EventsArgs struct {
-	Contract common.Address `json:"contract"`
+	Contract  common.Address `json:"contract"`
+	Event     string         `json:"event"`
+	FromBlock uint64         `json:"fromBlock"`
+	ToBlock   uint64         `json:"toBlock"`
+	// Args are hex values of the first database.MaxIndexedEventArgs event args by their positions, null matches any arg
+	Args  []*hexutil.Bytes `json:"args"`
+	Count int              `json:"count"`
+	Token hexutil.Bytes    `json:"token"`
+}
+
+type Events struct {
+	Events []*Event       `json:"events"`
+	Token  *hexutil.Bytes `json:"token"`
 }
 
 func (a DynamicArg) ToBytes() ([]byte, error) {
-	switch a.Format {
-	case "byte":
-		i, err := strconv.ParseUint(a.Value, 10, 8)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse byte: \"%v\"", a.Value)
-		}
-		return []byte{byte(i)}, nil
-	case "int8":
-		i, err := strconv.ParseInt(a.Value, 10, 8)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse int8: \"%v\"", a.Value)
-		}
-		return common.ToBytes(i), nil
-	case "uint64":
-		i, err := strconv.ParseUint(a.Value, 10, 64)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse uint64: \"%v\"", a.Value)
-		}
-		return common.ToBytes(i), nil
-	case "int64":
-		i, err := strconv.ParseInt(a.Value, 10, 64)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse int64: \"%v\"", a.Value)
-		}
-		return common.ToBytes(i), nil
-	case "string":
-		return []byte(a.Value), nil
-	case "bigint":
-		v := new(big.Int)
-		_, ok := v.SetString(a.Value, 10)
-		if !ok {
-			return nil, errors.Errorf("cannot parse bigint: \"%v\"", a.Value)
-		}
-		return v.Bytes(), nil
-	case "hex":
-		data, err := hexutil.Decode(a.Value)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse hex: \"%v\"", a.Value)
-		}
-		return data, nil
-	case "dna":
-		d, err := decimal.NewFromString(a.Value)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse dna: \"%v\"", a.Value)
-		}
-		return blockchain.ConvertToInt(d).Bytes(), nil
-	default:
-		data, err := hexutil.Decode(a.Value)
-		if err != nil {
-			return nil, errors.Errorf("cannot parse hex: \"%v\"", a.Value)
-		}
-		return data, nil
-	}
+	return abi.Encode(abi.ArgType(a.Format), a.Value)
 }
 
 func (d DynamicArgs) ToSlice() ([][]byte, error) {
@@ -158,6 +144,29 @@ func (d DynamicArgs) ToSlice() ([][]byte, error) {
 	return data, nil
 }
 
+// convertArgs returns positional args, named args are converted using the args of the contract ABI
+func convertArgs(args DynamicArgs, named NamedArgs, abiArgs func() ([]abi.Arg, error)) ([][]byte, error) {
+	if named == nil {
+		return args.ToSlice()
+	}
+	if len(args) > 0 {
+		return nil, errors.New("args and namedArgs can not be used together")
+	}
+	list, err := abiArgs()
+	if err != nil {
+		return nil, err
+	}
+	positions, err := abi.Positions(list, named)
+	if err != nil {
+		return nil, err
+	}
+	var dynamicArgs DynamicArgs
+	for idx, value := range positions {
+		dynamicArgs = append(dynamicArgs, &DynamicArg{Index: idx, Format: string(list[idx].Type), Value: value})
+	}
+	return dynamicArgs.ToSlice()
+}
+
 type TxReceipt struct {
 	Contract common.Address  `json:"contract"`
 	Method   string          `json:"method"`
@@ -173,6 +182,30 @@ type Event struct {
 	Contract common.Address  `json:"contract"`
 	Event    string          `json:"event"`
 	Args     []hexutil.Bytes `json:"args"`
+	TxHash   *common.Hash    `json:"txHash,omitempty"`
+	Height   uint64          `json:"height,omitempty"`
+}
+
+type CreateMultisigPstArgs struct {
+	Contract common.Address  `json:"contract"`
+	Dest     common.Address  `json:"dest"`
+	Amount   decimal.Decimal `json:"amount"`
+	MaxFee   decimal.Decimal `json:"maxFee"`
+	// Signers are the voters expected to sign the envelope, all the voters of the contract are used if not specified
+	Signers []common.Address `json:"signers"`
+}
+
+type SignMultisigPstArgs struct {
+	Envelope hexutil.Bytes  `json:"envelope"`
+	From     common.Address `json:"from"`
+	Nonce    uint32         `json:"nonce"`
+}
+
+type MultisigPstTxs struct {
+	// Votes are raw signed txs which should be sent first
+	Votes []hexutil.Bytes `json:"votes"`
+	// Push is a raw signed tx which should be sent after all the votes are mined
+	Push hexutil.Bytes `json:"push"`
 }
 
 type MapItem struct {
@@ -185,6 +218,11 @@ type IterateMapResponse struct {
 	ContinuationToken *hexutil.Bytes `json:"continuationToken"`
 }
 
+type GasSchedule struct {
+	ConsensusVersion config.ConsensusVerson `json:"consensusVersion"`
+	Table            config.GasTable        `json:"table"`
+}
+
 func (api *ContractApi) buildDeployContractTx(args DeployArgs) (*types.Transaction, error) {
 	var codeHash common.Hash
 	codeHash.SetBytes(args.CodeHash)
@@ -193,10 +231,23 @@ func (api *ContractApi) buildDeployContractTx(args DeployArgs) (*types.Transacti
 	if from == (common.Address{}) {
 		from = api.baseApi.getCurrentCoinbase()
 	}
-	convertedArgs, err := args.Args.ToSlice()
+	if len(args.Code) > 0 {
+		codeHash = crypto.Hash(args.Code)
+	}
+	convertedArgs, err := convertArgs(args.Args, args.NamedArgs, func() ([]abi.Arg, error) {
+		contractAbi, err := api.deployAbi(codeHash, args.Code)
+		if err != nil {
+			return nil, err
+		}
+		return contractAbi.Deploy, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	// the code of a wasm contract is passed as the first arg
+	if len(args.Code) > 0 {
+		convertedArgs = append([][]byte{args.Code}, convertedArgs...)
+	}
 	payload, _ := attachments.CreateDeployContractAttachment(codeHash, convertedArgs...).ToBytes()
 	return api.baseApi.getSignedTx(from, nil, types.DeployContractTx, args.Amount,
 		args.MaxFee, decimal.Zero,
@@ -210,7 +261,9 @@ func (api *ContractApi) buildCallContractTx(args CallArgs) (*types.Transaction,
 	if from == (common.Address{}) {
 		from = api.baseApi.getCurrentCoinbase()
 	}
-	convertedArgs, err := args.Args.ToSlice()
+	convertedArgs, err := convertArgs(args.Args, args.NamedArgs, func() ([]abi.Arg, error) {
+		return api.methodArgs(args.Contract, args.Method, false)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +280,13 @@ func (api *ContractApi) buildTerminateContractTx(args TerminateArgs) (*types.Tra
 	if from == (common.Address{}) {
 		from = api.baseApi.getCurrentCoinbase()
 	}
-	convertedArgs, err := args.Args.ToSlice()
+	convertedArgs, err := convertArgs(args.Args, args.NamedArgs, func() ([]abi.Arg, error) {
+		contractAbi, err := api.contractAbi(args.Contract)
+		if err != nil {
+			return nil, err
+		}
+		return contractAbi.Terminate, nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -238,9 +297,9 @@ func (api *ContractApi) buildTerminateContractTx(args TerminateArgs) (*types.Tra
 		payload, nil)
 }
 
-func (api *ContractApi) EstimateDeploy(args DeployArgs) (*TxReceipt, error) {
+func (api *ContractApi) EstimateDeploy(ctx context.Context, args DeployArgs) (*TxReceipt, error) {
 	appState := api.baseApi.getAppStateForCheck()
-	vm := vm.NewVmImpl(appState, api.bc.Head, nil, api.bc.Config())
+	contractVm := vm.NewInterruptibleVmImpl(ctx, appState, api.bc.Head, api.bc.Config())
 	tx, err := api.buildDeployContractTx(args)
 	if err != nil {
 		return nil, err
@@ -248,14 +307,14 @@ func (api *ContractApi) EstimateDeploy(args DeployArgs) (*TxReceipt, error) {
 	if err := validation.ValidateTx(appState, tx, appState.State.FeePerGas(), validation.MempoolTx); err != nil {
 		return nil, err
 	}
-	r := vm.Run(tx, -1)
+	r := contractVm.Run(tx, vm.ReadGasLimit)
 	r.GasCost = api.bc.GetGasCost(appState, r.GasUsed)
 	return convertReceipt(tx, r, appState.State.FeePerGas()), nil
 }
 
-func (api *ContractApi) EstimateCall(args CallArgs) (*TxReceipt, error) {
+func (api *ContractApi) EstimateCall(ctx context.Context, args CallArgs) (*TxReceipt, error) {
 	appState := api.baseApi.getAppStateForCheck()
-	vm := vm.NewVmImpl(appState, api.bc.Head, nil, api.bc.Config())
+	contractVm := vm.NewInterruptibleVmImpl(ctx, appState, api.bc.Head, api.bc.Config())
 	tx, err := api.buildCallContractTx(args)
 	if err != nil {
 		return nil, err
@@ -269,14 +328,14 @@ func (api *ContractApi) EstimateCall(args CallArgs) (*TxReceipt, error) {
 		appState.State.AddBalance(*tx.To, tx.Amount)
 	}
 
-	r := vm.Run(tx, -1)
+	r := contractVm.Run(tx, vm.ReadGasLimit)
 	r.GasCost = api.bc.GetGasCost(appState, r.GasUsed)
 	return convertReceipt(tx, r, appState.State.FeePerGas()), nil
 }
 
-func (api *ContractApi) EstimateTerminate(args TerminateArgs) (*TxReceipt, error) {
+func (api *ContractApi) EstimateTerminate(ctx context.Context, args TerminateArgs) (*TxReceipt, error) {
 	appState := api.baseApi.getAppStateForCheck()
-	vm := vm.NewVmImpl(appState, api.bc.Head, nil, api.bc.Config())
+	contractVm := vm.NewInterruptibleVmImpl(ctx, appState, api.bc.Head, api.bc.Config())
 	tx, err := api.buildTerminateContractTx(args)
 	if err != nil {
 		return nil, err
@@ -284,7 +343,7 @@ func (api *ContractApi) EstimateTerminate(args TerminateArgs) (*TxReceipt, error
 	if err := validation.ValidateTx(appState, tx, appState.State.FeePerGas(), validation.MempoolTx); err != nil {
 		return nil, err
 	}
-	r := vm.Run(tx, -1)
+	r := contractVm.Run(tx, vm.ReadGasLimit)
 	r.GasCost = api.bc.GetGasCost(appState, r.GasUsed)
 	return convertReceipt(tx, r, appState.State.FeePerGas()), nil
 }
@@ -339,17 +398,45 @@ func (api *ContractApi) Terminate(ctx context.Context, args TerminateArgs) (comm
 	return api.baseApi.sendInternalTx(ctx, tx)
 }
 
-func (api *ContractApi) ReadData(contract common.Address, key string, format string) (interface{}, error) {
-	data := api.baseApi.getReadonlyAppState().State.GetContractValue(contract, []byte(key))
+func (api *ContractApi) ReadData(contract common.Address, key string, format string, block *BlockRef) (interface{}, error) {
+	appState, err := api.baseApi.getReadonlyAppStateAt(api.bc, block)
+	if err != nil {
+		return nil, err
+	}
+	data := appState.State.GetContractValue(contract, []byte(key))
 	if data == nil {
 		return nil, errors.New("data is nil")
 	}
 	return conversion(format, data)
 }
 
-func (api *ContractApi) ReadonlyCall(args ReadonlyCallArgs) (interface{}, error) {
-	vm := vm.NewVmImpl(api.baseApi.getReadonlyAppState(), api.bc.Head, nil, api.bc.Config())
-	convertedArgs, err := args.Args.ToSlice()
+type DataWithProof struct {
+	Value hexutil.Bytes `json:"value"`
+	StateProof
+}
+
+// ReadDataWithProof returns the raw contract value and the proof of its inclusion or absence against the state root of
+// the block, stateproof.VerifyContractValue checks the result
+func (api *ContractApi) ReadDataWithProof(contract common.Address, key string, block *BlockRef) (*DataWithProof, error) {
+	appState, header, err := api.baseApi.getReadonlyAppStateWithHeaderAt(api.bc, block)
+	if err != nil {
+		return nil, err
+	}
+	value, proof, err := appState.State.GetContractValueWithProof(contract, []byte(key))
+	if err != nil {
+		return nil, err
+	}
+	return &DataWithProof{
+		Value:      value,
+		StateProof: newStateProof(header, proof),
+	}, nil
+}
+
+func (api *ContractApi) ReadonlyCall(ctx context.Context, args ReadonlyCallArgs) (interface{}, error) {
+	vm := vm.NewInterruptibleVmImpl(ctx, api.baseApi.getReadonlyAppState(), api.bc.Head, api.bc.Config())
+	convertedArgs, err := convertArgs(args.Args, args.NamedArgs, func() ([]abi.Arg, error) {
+		return api.methodArgs(args.Contract, args.Method, true)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -360,6 +447,66 @@ func (api *ContractApi) ReadonlyCall(args ReadonlyCallArgs) (interface{}, error)
 	return conversion(args.Format, data)
 }
 
+// Abi returns the ABI of the deployed contract or of the contract code
+func (api *ContractApi) Abi(args AbiArgs) (*abi.Abi, error) {
+	if args.Contract != nil {
+		return api.contractAbi(*args.Contract)
+	}
+	var codeHash common.Hash
+	codeHash.SetBytes(args.CodeHash)
+	return api.codeAbi(codeHash)
+}
+
+func (api *ContractApi) contractAbi(contract common.Address) (*abi.Abi, error) {
+	codeHash := api.baseApi.getReadonlyAppState().State.GetCodeHash(contract)
+	if codeHash == nil {
+		return nil, errors.New("destination is not a contract")
+	}
+	return api.codeAbi(*codeHash)
+}
+
+func (api *ContractApi) codeAbi(codeHash common.Hash) (*abi.Abi, error) {
+	vm := vm.NewVmImpl(api.baseApi.getReadonlyAppState(), api.bc.Head, nil, api.bc.Config())
+	contractAbi, err := vm.Abi(codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if contractAbi == nil {
+		return nil, errors.New("contract has no abi")
+	}
+	return contractAbi, nil
+}
+
+// deployAbi returns the ABI of the code being deployed, the code of a wasm contract is not in the state yet
+func (api *ContractApi) deployAbi(codeHash common.Hash, code []byte) (*abi.Abi, error) {
+	if len(code) == 0 {
+		return api.codeAbi(codeHash)
+	}
+	module, err := wasm.Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	if module.Abi() == nil {
+		return nil, errors.New("contract has no abi")
+	}
+	return module.Abi(), nil
+}
+
+func (api *ContractApi) methodArgs(contract common.Address, method string, read bool) ([]abi.Arg, error) {
+	contractAbi, err := api.contractAbi(contract)
+	if err != nil {
+		return nil, err
+	}
+	m := contractAbi.Method(method)
+	if read {
+		m = contractAbi.ReadMethod(method)
+	}
+	if m == nil {
+		return nil, errors.New("unknown method")
+	}
+	return m.Args, nil
+}
+
 func (api *ContractApi) GetStake(contract common.Address) interface{} {
 	hash := api.baseApi.getReadonlyAppState().State.GetCodeHash(contract)
 	stake := api.baseApi.getReadonlyAppState().State.GetContractStake(contract)
@@ -372,6 +519,15 @@ func (api *ContractApi) GetStake(contract common.Address) interface{} {
 	}
 }
 
+// GasSchedule returns the gas costs of the contract operations of the active consensus version
+func (api *ContractApi) GasSchedule() *GasSchedule {
+	consensus := api.bc.Config().Consensus
+	return &GasSchedule{
+		ConsensusVersion: consensus.Version,
+		Table:            consensus.GasTable,
+	}
+}
+
 func (api *ContractApi) SubscribeToEvent(contract common.Address, event string) error {
 	return api.subManager.Subscribe(contract, event)
 }
@@ -380,22 +536,53 @@ func (api *ContractApi) UnsubscribeFromEvent(contract common.Address, event stri
 	return api.subManager.Unsubscribe(contract, event)
 }
 
-func (api *ContractApi) Events(args EventsArgs) interface{} {
-
-	events := api.bc.ReadEvents(args.Contract)
+// Events returns a page of indexed events of the contract matching the filter, the page size is limited by maxEventsPageSize
+func (api *ContractApi) Events(args EventsArgs) (Events, error) {
+	filter := &database.EventFilter{
+		Contract:  args.Contract,
+		Event:     args.Event,
+		FromBlock: args.FromBlock,
+		ToBlock:   args.ToBlock,
+	}
+	for _, arg := range args.Args {
+		if arg == nil {
+			filter.Args = append(filter.Args, nil)
+		} else {
+			filter.Args = append(filter.Args, *arg)
+		}
+	}
+	count := args.Count
+	if count <= 0 || count > maxEventsPageSize {
+		count = maxEventsPageSize
+	}
+	events, nextToken, err := api.bc.ReadEvents(filter, count, args.Token)
+	if err != nil {
+		return Events{}, err
+	}
 
-	var list []*Event
+	list := make([]*Event, 0, len(events))
 	for idx := range events {
+		txHash := events[idx].TxHash
 		e := &Event{
 			Contract: events[idx].Contract,
 			Event:    events[idx].Event,
+			TxHash:   &txHash,
+			Height:   events[idx].Height,
 		}
 		list = append(list, e)
 		for i := range events[idx].Args {
 			e.Args = append(e.Args, events[idx].Args[i])
 		}
 	}
-	return list
+	var token *hexutil.Bytes
+	if nextToken != nil {
+		t := hexutil.Bytes(nextToken)
+		token = &t
+	}
+	return Events{
+		Events: list,
+		Token:  token,
+	}, nil
 }
 
 func (api *ContractApi) ReadMap(contract common.Address, mapName string, key hexutil.Bytes, format string) (interface{}, error) {
@@ -451,6 +638,141 @@ func (api *ContractApi) IterateMap(contract common.Address, mapName string, cont
 	}, nil
 }
 
+// CreateMultisigPst creates a partially signed envelope to transfer coins from the multisig contract
+func (api *ContractApi) CreateMultisigPst(args CreateMultisigPstArgs) (hexutil.Bytes, error) {
+	appState := api.baseApi.getReadonlyAppState()
+	voters, minVotes, err := api.multisigVoters(appState, args.Contract)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := pst.SelectSigners(voters, args.Signers)
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := pst.NewMultisigEnvelope(args.Contract, args.Dest, blockchain.ConvertToInt(args.Amount),
+		blockchain.ConvertToInt(args.MaxFee), appState.State.Epoch(), signers, minVotes)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Tx.MaxFee.Sign() == 0 {
+		_, push := envelope.UnsignedTxs(0)
+		txFee := fee.CalculateFee(appState.ValidatorsCache.NetworkSize(), appState.State.FeePerGas(), push)
+		envelope.Tx.MaxFee = new(big.Int).Mul(txFee, big.NewInt(2))
+	}
+	return envelope.ToBytes()
+}
+
+// multisigVoters reads the voters and the min votes threshold of the initialized multisig contract
+func (api *ContractApi) multisigVoters(appState *appstate.AppState, contract common.Address) ([]common.Address, byte, error) {
+	codeHash := appState.State.GetCodeHash(contract)
+	if codeHash == nil || *codeHash != embedded.MultisigContract {
+		return nil, 0, errors.New("contract is not a multisig")
+	}
+	vm := vm.NewVmImpl(appState, api.bc.Head, nil, api.bc.Config())
+	state, err := vm.Read(contract, "state")
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(state) != 1 || state[0] != multisigInitialized {
+		return nil, 0, errors.New("multisig contract is not initialized")
+	}
+	minVotes, err := vm.Read(contract, "minVotes")
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(minVotes) != 1 {
+		return nil, 0, errors.New("invalid min votes")
+	}
+	data, err := vm.Read(contract, "voters")
+	if err != nil {
+		return nil, 0, err
+	}
+	voters, err := pst.ParseVoters(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return voters, minVotes[0], nil
+}
+
+// SignMultisigPst adds signatures of the from address to the envelope, the next nonce of the address is used if nonce is not specified
+func (api *ContractApi) SignMultisigPst(args SignMultisigPstArgs) (hexutil.Bytes, error) {
+	envelope := &pst.Envelope{}
+	if err := envelope.FromBytes(args.Envelope); err != nil {
+		return nil, err
+	}
+	from := args.From
+	if from == (common.Address{}) {
+		from = api.baseApi.getCurrentCoinbase()
+	}
+	nonce := args.Nonce
+	if nonce == 0 {
+		nonce = api.baseApi.getReadonlyAppState().NonceCache.GetNonce(from, envelope.Tx.Epoch) + 1
+	}
+	vote, push := envelope.UnsignedTxs(nonce)
+	signedVote, err := api.baseApi.signTransaction(from, vote, nil)
+	if err != nil {
+		return nil, err
+	}
+	signedPush, err := api.baseApi.signTransaction(from, push, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := envelope.AddSignature(&pst.Signature{
+		Signer:        from,
+		Nonce:         nonce,
+		VoteSignature: signedVote.Signature,
+		PushSignature: signedPush.Signature,
+	}); err != nil {
+		return nil, err
+	}
+	return envelope.ToBytes()
+}
+
+// MergeMultisigPst combines signatures of the envelopes of the same transaction
+func (api *ContractApi) MergeMultisigPst(envelopes []hexutil.Bytes) (hexutil.Bytes, error) {
+	if len(envelopes) == 0 {
+		return nil, errors.New("envelopes are not specified")
+	}
+	result := &pst.Envelope{}
+	if err := result.FromBytes(envelopes[0]); err != nil {
+		return nil, err
+	}
+	for _, data := range envelopes[1:] {
+		envelope := &pst.Envelope{}
+		if err := envelope.FromBytes(data); err != nil {
+			return nil, err
+		}
+		if err := result.Merge(envelope); err != nil {
+			return nil, err
+		}
+	}
+	return result.ToBytes()
+}
+
+// FinalizeMultisigPst returns raw txs of the envelope which can be sent with bcn_sendRawTx
+func (api *ContractApi) FinalizeMultisigPst(envelope hexutil.Bytes) (*MultisigPstTxs, error) {
+	e := &pst.Envelope{}
+	if err := e.FromBytes(envelope); err != nil {
+		return nil, err
+	}
+	votes, push, err := e.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	result := &MultisigPstTxs{}
+	for _, vote := range votes {
+		data, err := vote.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		result.Votes = append(result.Votes, data)
+	}
+	if result.Push, err = push.ToBytes(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func conversion(convertTo string, data []byte) (interface{}, error) {
 	switch convertTo {
 	case "byte":
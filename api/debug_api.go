@@ -0,0 +1,144 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/shopspring/decimal"
+	"math/big"
+)
+
+// DebugApi offers methods to inspect execution of contracts
+type DebugApi struct {
+	bc *blockchain.Blockchain
+}
+
+// NewDebugApi creates a new DebugApi instance
+func NewDebugApi(bc *blockchain.Blockchain) *DebugApi {
+	return &DebugApi{bc: bc}
+}
+
+type TxTrace struct {
+	Receipt   *TxReceipt   `json:"receipt"`
+	Steps     []*TraceStep `json:"steps"`
+	StateDiff *StateDiff   `json:"stateDiff"`
+}
+
+type TraceStep struct {
+	Method   string          `json:"method"`
+	Contract *common.Address `json:"contract,omitempty"`
+	Args     []interface{}   `json:"args"`
+	Result   interface{}     `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Gas      int             `json:"gas"`
+	UsedGas  int             `json:"usedGas"`
+	Depth    int             `json:"depth"`
+}
+
+type StateDiff struct {
+	Balances            []*ValueChange   `json:"balances"`
+	ContractStakes      []*ValueChange   `json:"contractStakes"`
+	Storage             []*StorageChange `json:"storage"`
+	DeployedContracts   []common.Address `json:"deployedContracts"`
+	TerminatedContracts []common.Address `json:"terminatedContracts"`
+}
+
+type ValueChange struct {
+	Address common.Address  `json:"address"`
+	Before  decimal.Decimal `json:"before"`
+	After   decimal.Decimal `json:"after"`
+}
+
+type StorageChange struct {
+	Contract common.Address `json:"contract"`
+	Key      hexutil.Bytes  `json:"key"`
+	Before   hexutil.Bytes  `json:"before"`
+	After    hexutil.Bytes  `json:"after"`
+	Removed  bool           `json:"removed"`
+}
+
+// TraceTx re-executes the mined contract transaction and returns all the calls the contract made to the environment,
+// gas charged by each call and the resulting state changes
+func (api *DebugApi) TraceTx(hash common.Hash) (*TxTrace, error) {
+	tx, receipt, tracer, err := api.bc.TraceTx(hash)
+	if err != nil {
+		return nil, err
+	}
+	var feePerGas *big.Int
+	if idx := api.bc.GetTxIndex(hash); idx != nil {
+		if block := api.bc.GetBlock(idx.BlockHash); block != nil {
+			feePerGas = block.Header.FeePerGas()
+		}
+	}
+	trace := &TxTrace{
+		Receipt:   convertReceipt(tx, receipt, feePerGas),
+		Steps:     make([]*TraceStep, 0, len(tracer.Steps)),
+		StateDiff: convertStateDiff(tracer.StateDiff),
+	}
+	for _, step := range tracer.Steps {
+		trace.Steps = append(trace.Steps, convertTraceStep(step))
+	}
+	return trace, nil
+}
+
+func convertTraceStep(step *env.TraceStep) *TraceStep {
+	result := &TraceStep{
+		Method:   step.Method,
+		Contract: step.Contract,
+		Args:     make([]interface{}, 0, len(step.Args)),
+		Result:   convertTraceValue(step.Result),
+		Error:    step.Error,
+		Gas:      step.Gas,
+		UsedGas:  step.UsedGas,
+		Depth:    step.Depth,
+	}
+	for _, arg := range step.Args {
+		result.Args = append(result.Args, convertTraceValue(arg))
+	}
+	return result
+}
+
+func convertTraceValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Bytes(v)
+	case *big.Int:
+		return blockchain.ConvertToFloat(v)
+	default:
+		return v
+	}
+}
+
+func convertStateDiff(diff *env.StateDiff) *StateDiff {
+	if diff == nil {
+		return nil
+	}
+	convertChanges := func(changes []*env.ValueChange) []*ValueChange {
+		var result []*ValueChange
+		for _, c := range changes {
+			result = append(result, &ValueChange{
+				Address: c.Address,
+				Before:  blockchain.ConvertToFloat(c.Before),
+				After:   blockchain.ConvertToFloat(c.After),
+			})
+		}
+		return result
+	}
+	result := &StateDiff{
+		Balances:            convertChanges(diff.Balances),
+		ContractStakes:      convertChanges(diff.ContractStakes),
+		DeployedContracts:   diff.DeployedContracts,
+		TerminatedContracts: diff.TerminatedContracts,
+	}
+	for _, c := range diff.Storage {
+		result.Storage = append(result.Storage, &StorageChange{
+			Contract: c.Contract,
+			Key:      c.Key,
+			Before:   c.Before,
+			After:    c.After,
+			Removed:  c.Removed,
+		})
+	}
+	return result
+}
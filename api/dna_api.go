@@ -58,20 +58,66 @@ type Balance struct {
 	MempoolNonce uint32          `json:"mempoolNonce"`
 }
 
-func (api *DnaApi) GetBalance(address common.Address) Balance {
-	state := api.baseApi.getReadonlyAppState()
+func (api *DnaApi) GetBalance(address common.Address, block *BlockRef) (Balance, error) {
+	state, err := api.baseApi.getReadonlyAppStateAt(api.bc, block)
+	if err != nil {
+		return Balance{}, err
+	}
 	currentEpoch := state.State.Epoch()
 	nonce, epoch := state.State.GetNonce(address), state.State.GetEpoch(address)
 	if epoch < currentEpoch {
 		nonce = 0
 	}
+	// the mempool is not related to historical states
+	mempoolNonce := nonce
+	if block.isEmpty() {
+		mempoolNonce = state.NonceCache.GetNonce(address, currentEpoch)
+	}
 
 	return Balance{
 		Stake:        blockchain.ConvertToFloat(state.State.GetStakeBalance(address)),
 		Balance:      blockchain.ConvertToFloat(state.State.GetBalance(address)),
 		Nonce:        nonce,
-		MempoolNonce: state.NonceCache.GetNonce(address, currentEpoch),
+		MempoolNonce: mempoolNonce,
+	}, nil
+}
+
+// BalanceWithProof is the balance of the address with the proof of its account
+type BalanceWithProof struct {
+	Balance decimal.Decimal `json:"balance"`
+	Nonce   uint32          `json:"nonce"`
+	Epoch   uint16          `json:"epoch"`
+	// Encoded account, empty if the address has no account
+	Account hexutil.Bytes `json:"account"`
+	StateProof
+}
+
+// GetBalanceWithProof returns the balance of the address and the proof of its account against the state root of
+// the block, stateproof.VerifyAccount checks the result
+func (api *DnaApi) GetBalanceWithProof(address common.Address, block *BlockRef) (*BalanceWithProof, error) {
+	appState, header, err := api.baseApi.getReadonlyAppStateWithHeaderAt(api.bc, block)
+	if err != nil {
+		return nil, err
 	}
+	account, proof, err := appState.State.GetAccountWithProof(address)
+	if err != nil {
+		return nil, err
+	}
+	result := &BalanceWithProof{
+		Balance:    decimal.Zero,
+		Account:    account,
+		StateProof: newStateProof(header, proof),
+	}
+	if account != nil {
+		var data state.Account
+		if err := data.FromBytes(account); err != nil {
+			return nil, err
+		}
+		result.Balance = blockchain.ConvertToFloat(data.Balance)
+		result.Nonce = data.Nonce
+		result.Epoch = data.Epoch
+	}
+	return result, nil
 }
 
 // SendTxArgs represents the arguments to submit a new transaction into the transaction pool.
@@ -340,40 +386,48 @@ func (api *DnaApi) Identities() []Identity {
 	return identities
 }
 
-func (api *DnaApi) Identity(address *common.Address) Identity {
+func (api *DnaApi) Identity(address *common.Address, block *BlockRef) (Identity, error) {
 	var flipKeyWordPairs []int
 	coinbase := api.GetCoinbaseAddr()
 	if address == nil || *address == coinbase {
 		address = &coinbase
-		flipKeyWordPairs = api.ceremony.FlipKeyWordPairs()
+		if block == nil {
+			flipKeyWordPairs = api.ceremony.FlipKeyWordPairs()
+		}
 	}
 
-	appState := api.baseApi.getReadonlyAppState()
-	return convertIdentity(appState.State.Epoch(), *address, appState.State.GetIdentity(*address), flipKeyWordPairs, appState)
+	appState, err := api.baseApi.getReadonlyAppStateAt(api.bc, block)
+	if err != nil {
+		return Identity{}, err
+	}
+	return convertIdentity(appState.State.Epoch(), *address, appState.State.GetIdentity(*address), flipKeyWordPairs, appState), nil
 }
 
-func convertIdentity(currentEpoch uint16, address common.Address, data state.Identity, flipKeyWordPairs []int, appState *appstate.AppState) Identity {
-	var s string
-	switch data.State {
+func convertIdentityState(identityState state.IdentityState) string {
+	switch identityState {
 	case state.Invite:
-		s = "Invite"
+		return "Invite"
 	case state.Candidate:
-		s = "Candidate"
+		return "Candidate"
 	case state.Newbie:
-		s = "Newbie"
+		return "Newbie"
 	case state.Verified:
-		s = "Verified"
+		return "Verified"
 	case state.Suspended:
-		s = "Suspended"
+		return "Suspended"
 	case state.Zombie:
-		s = "Zombie"
+		return "Zombie"
 	case state.Killed:
-		s = "Killed"
+		return "Killed"
 	case state.Human:
-		s = "Human"
+		return "Human"
 	default:
-		s = "Undefined"
+		return "Undefined"
 	}
+}
+
+func convertIdentity(currentEpoch uint16, address common.Address, data state.Identity, flipKeyWordPairs []int, appState *appstate.AppState) Identity {
+	s := convertIdentityState(data.State)
 
 	var flags []string
 	if data.LastValidationStatus.HasFlag(state.AllFlipsNotQualified) {
@@ -489,28 +543,31 @@ type Epoch struct {
 
 func (api *DnaApi) Epoch() Epoch {
 	s := api.baseApi.getReadonlyAppState()
-	var res string
-	switch s.State.ValidationPeriod() {
+	return Epoch{
+		Epoch:          s.State.Epoch(),
+		StartBlock:     s.State.EpochBlock(),
+		NextValidation: s.State.NextValidationTime(),
+		CurrentPeriod:  validationPeriodName(s.State.ValidationPeriod(), api.ceremony),
+	}
+}
+
+func validationPeriodName(period state.ValidationPeriod, ceremony *ceremony.ValidationCeremony) string {
+	switch period {
 	case state.NonePeriod:
-		res = "None"
+		return "None"
 	case state.FlipLotteryPeriod:
-		res = "FlipLottery"
-		if api.ceremony.ShortSessionStarted() {
-			res = "ShortSession"
+		if ceremony.ShortSessionStarted() {
+			return "ShortSession"
 		}
+		return "FlipLottery"
 	case state.ShortSessionPeriod:
-		res = "ShortSession"
+		return "ShortSession"
 	case state.LongSessionPeriod:
-		res = "LongSession"
+		return "LongSession"
 	case state.AfterLongSessionPeriod:
-		res = "AfterLongSession"
-	}
-
-	return Epoch{
-		Epoch:          s.State.Epoch(),
-		StartBlock:     s.State.EpochBlock(),
-		NextValidation: s.State.NextValidationTime(),
-		CurrentPeriod:  res,
+		return "AfterLongSession"
+	default:
+		return ""
 	}
 }
 
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/consensus"
+	"github.com/idena-network/idena-go/core/ceremony"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/idena-network/idena-go/protocol"
+	"time"
+)
+
+const (
+	// maxHeadAge is the duration without new blocks after which the node is considered stuck
+	maxHeadAge = 30 * time.Minute
+	// maxSyncLag is the max number of blocks the node may lag behind its peers to be ready
+	maxSyncLag = 2
+)
+
+// HealthChecker provides the node status to the /health and /ready HTTP endpoints
+type HealthChecker struct {
+	engine   *consensus.Engine
+	bc       *blockchain.Blockchain
+	d        *protocol.Downloader
+	pm       *protocol.IdenaGossipHandler
+	ipfs     ipfs.Proxy
+	ceremony *ceremony.ValidationCeremony
+}
+
+// NewHealthChecker creates a new HealthChecker instance
+func NewHealthChecker(engine *consensus.Engine, bc *blockchain.Blockchain, d *protocol.Downloader, pm *protocol.IdenaGossipHandler,
+	ipfs ipfs.Proxy, ceremony *ceremony.ValidationCeremony) *HealthChecker {
+	return &HealthChecker{
+		engine:   engine,
+		bc:       bc,
+		d:        d,
+		pm:       pm,
+		ipfs:     ipfs,
+		ceremony: ceremony,
+	}
+}
+
+type HealthReport struct {
+	Syncing          bool     `json:"syncing"`
+	CurrentBlock     uint64   `json:"currentBlock"`
+	HighestBlock     uint64   `json:"highestBlock"`
+	HeadAge          float64  `json:"headAge"` // seconds since the head block timestamp
+	Peers            int      `json:"peers"`
+	IpfsPeers        int      `json:"ipfsPeers"`
+	WrongTime        bool     `json:"wrongTime"`
+	Ceremony         bool     `json:"ceremony"`
+	ValidationPeriod string   `json:"validationPeriod"`
+	Errors           []string `json:"errors"`
+}
+
+func (h *HealthChecker) report() *HealthReport {
+	current, highest := h.d.SyncProgress()
+	if highest < current {
+		highest = current
+	}
+	var period state.ValidationPeriod
+	if appState, err := h.engine.ReadonlyAppState(); err == nil {
+		period = appState.State.ValidationPeriod()
+	}
+	return &HealthReport{
+		Syncing:          h.d.IsSyncing(),
+		CurrentBlock:     current,
+		HighestBlock:     highest,
+		HeadAge:          time.Since(time.Unix(h.bc.Head.Time(), 0)).Seconds(),
+		Peers:            h.pm.PeersCount(),
+		IpfsPeers:        len(h.ipfs.Host().Network().Peers()),
+		WrongTime:        h.pm.WrongTime(),
+		Ceremony:         period != state.NonePeriod,
+		ValidationPeriod: validationPeriodName(period, h.ceremony),
+		Errors:           []string{},
+	}
+}
+
+// Health fails if the node doesn't get new blocks for a long time
+func (h *HealthChecker) Health() (interface{}, bool) {
+	report := h.report()
+	if !h.bc.Config().Consensus.Automine && report.HeadAge > maxHeadAge.Seconds() {
+		report.Errors = append(report.Errors, "no new blocks")
+	}
+	return report, len(report.Errors) == 0
+}
+
+// Ready fails if the node is syncing, lags behind the network or has no connectivity
+func (h *HealthChecker) Ready() (interface{}, bool) {
+	report := h.report()
+	if !h.bc.Config().Consensus.Automine {
+		if report.Syncing || !h.engine.Synced() || report.HighestBlock-report.CurrentBlock > maxSyncLag {
+			report.Errors = append(report.Errors, "node is syncing")
+		}
+		if report.Peers == 0 {
+			report.Errors = append(report.Errors, "no peers")
+		}
+		if report.IpfsPeers == 0 {
+			report.Errors = append(report.Errors, "no ipfs peers")
+		}
+	}
+	if report.WrongTime {
+		report.Errors = append(report.Errors, "wrong time")
+	}
+	return report, len(report.Errors) == 0
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/protocol"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// LightApi serves the light mode, accounts and contract values are requested from full peers and verified against
+// the state root of the light head
+type LightApi struct {
+	pm         *protocol.IdenaGossipHandler
+	downloader *protocol.Downloader
+}
+
+// NewLightApi creates a new LightApi instance
+func NewLightApi(pm *protocol.IdenaGossipHandler, downloader *protocol.Downloader) *LightApi {
+	return &LightApi{pm, downloader}
+}
+
+type LightHead struct {
+	Height       uint64      `json:"height"`
+	Hash         common.Hash `json:"hash"`
+	Root         common.Hash `json:"root"`
+	IdentityRoot common.Hash `json:"identityRoot"`
+	Timestamp    int64       `json:"timestamp"`
+}
+
+func (api *LightApi) Head() LightHead {
+	head := api.downloader.LightHead()
+	return LightHead{
+		Height:       head.Height(),
+		Hash:         head.Hash(),
+		Root:         head.Root(),
+		IdentityRoot: head.IdentityRoot(),
+		Timestamp:    head.Time(),
+	}
+}
+
+type LightBalance struct {
+	Height  uint64          `json:"height"`
+	Balance decimal.Decimal `json:"balance"`
+	Nonce   uint32          `json:"nonce"`
+	Epoch   uint16          `json:"epoch"`
+}
+
+func (api *LightApi) GetBalance(address common.Address) (*LightBalance, error) {
+	height := api.downloader.LightHead().Height()
+	data, err := api.pm.GetStateProof(height, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &LightBalance{
+		Height:  height,
+		Balance: decimal.Zero,
+	}
+	if data != nil {
+		var account state.Account
+		if err := account.FromBytes(data); err != nil {
+			return nil, errors.Wrap(err, "failed to decode account")
+		}
+		result.Balance = blockchain.ConvertToFloat(account.Balance)
+		result.Nonce = account.Nonce
+		result.Epoch = account.Epoch
+	}
+	return result, nil
+}
+
+func (api *LightApi) ReadData(contract common.Address, key string) (hexutil.Bytes, error) {
+	if len(key) == 0 {
+		return nil, errors.New("key is empty")
+	}
+	return api.pm.GetStateProof(api.downloader.LightHead().Height(), contract, []byte(key))
+}
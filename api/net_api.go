@@ -23,6 +23,7 @@ func (api *NetApi) PeersCount() int {
 type Peer struct {
 	ID         string `json:"id"`
 	RemoteAddr string `json:"addr"`
+	Static     bool   `json:"static"`
 }
 
 func (api *NetApi) Peers() []Peer {
@@ -31,6 +32,7 @@ func (api *NetApi) Peers() []Peer {
 		peers = append(peers, Peer{
 			ID:         p.ID(),
 			RemoteAddr: p.RemoteAddr(),
+			Static:     api.pm.IsStaticPeer(p),
 		})
 	}
 	return peers
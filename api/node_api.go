@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/rpc"
+	"time"
+)
+
+// NodeApi offers information about the node itself
+type NodeApi struct {
+	rpcStats *rpc.Stats
+}
+
+// NewNodeApi creates a new NodeApi instance
+func NewNodeApi(rpcStats *rpc.Stats) *NodeApi {
+	return &NodeApi{rpcStats: rpcStats}
+}
+
+type RpcMethodStats struct {
+	Calls    uint64 `json:"calls"`
+	Errors   uint64 `json:"errors"`
+	Timeouts uint64 `json:"timeouts"`
+	// AvgLatency and MaxLatency are in milliseconds
+	AvgLatency float64 `json:"avgLatency"`
+	MaxLatency float64 `json:"maxLatency"`
+}
+
+// RpcStats returns number of calls, errors and latency of each RPC method called since the node start
+func (api *NodeApi) RpcStats() map[string]*RpcMethodStats {
+	result := make(map[string]*RpcMethodStats)
+	for method, stats := range api.rpcStats.Methods() {
+		s := &RpcMethodStats{
+			Calls:      stats.Calls,
+			Errors:     stats.Errors,
+			Timeouts:   stats.Timeouts,
+			MaxLatency: float64(stats.MaxLatency) / float64(time.Millisecond),
+		}
+		if stats.Calls > 0 {
+			s.AvgLatency = float64(stats.TotalLatency) / float64(stats.Calls) / float64(time.Millisecond)
+		}
+		result[method] = s
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/rpc"
+	"sync"
+)
+
+const notificationsBufferSize = 100
+
+// SubscriptionError is the last notification of a subscription closed by the node
+type SubscriptionError struct {
+	Error string `json:"error"`
+}
+
+const errNotificationsOverflow = "notifications queue overflow, subscription is closed"
+
+type SubscriptionApi struct {
+	bus eventbus.Bus
+}
+
+// NewSubscriptionApi creates a new SubscriptionApi instance
+func NewSubscriptionApi(bus eventbus.Bus) *SubscriptionApi {
+	return &SubscriptionApi{bus: bus}
+}
+
+type ContractEventsArgs struct {
+	Contracts []common.Address `json:"contracts"`
+	Events    []string         `json:"events"`
+}
+
+type ContractEvent struct {
+	Contract    common.Address  `json:"contract"`
+	Event       string          `json:"event"`
+	Args        []hexutil.Bytes `json:"args"`
+	TxHash      common.Hash     `json:"txHash"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	BlockHeight uint64          `json:"blockHeight"`
+}
+
+// NewBlocks sends a notification each time a new block is appended to the chain
+func (api *SubscriptionApi) NewBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, events.AddBlockEventID, func(e eventbus.Event) []interface{} {
+		return []interface{}{convertToBlock(e.(*events.NewBlockEvent).Block)}
+	})
+}
+
+// NewTxs sends a notification each time a new transaction is added to the mempool
+func (api *SubscriptionApi) NewTxs(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, events.NewTxEventID, func(e eventbus.Event) []interface{} {
+		return []interface{}{convertToTransaction(e.(*events.NewTxEvent).Tx, common.Hash{}, nil, 0)}
+	})
+}
+
+// ContractEvents sends a notification for each contract event emitted by the transactions of a new block.
+// Empty contracts or events lists in args match any contract or event respectively.
+func (api *SubscriptionApi) ContractEvents(ctx context.Context, args ContractEventsArgs) (*rpc.Subscription, error) {
+	contracts := make(map[common.Address]struct{}, len(args.Contracts))
+	for _, c := range args.Contracts {
+		contracts[c] = struct{}{}
+	}
+	eventNames := make(map[string]struct{}, len(args.Events))
+	for _, name := range args.Events {
+		eventNames[name] = struct{}{}
+	}
+	return api.subscribe(ctx, events.AddBlockEventID, func(e eventbus.Event) []interface{} {
+		newBlockEvent := e.(*events.NewBlockEvent)
+		var result []interface{}
+		for _, r := range newBlockEvent.Receipts {
+			for _, event := range r.Events {
+				if _, ok := contracts[event.Contract]; !ok && len(contracts) > 0 {
+					continue
+				}
+				if _, ok := eventNames[event.EventName]; !ok && len(eventNames) > 0 {
+					continue
+				}
+				result = append(result, convertToContractEvent(newBlockEvent.Block.Header, r, event))
+			}
+		}
+		return result
+	})
+}
+
+func (api *SubscriptionApi) subscribe(ctx context.Context, eventID eventbus.EventID, convert func(e eventbus.Event) []interface{}) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	// the bus calls handlers synchronously, so slow clients must not block the publisher,
+	// the subscription of a client which can't keep up is closed instead of dropping notifications silently
+	queue := make(chan eventbus.Event, notificationsBufferSize)
+	overflow := make(chan struct{})
+	overflowOnce := sync.Once{}
+	busSub := api.bus.Subscribe(eventID, func(e eventbus.Event) {
+		select {
+		case queue <- e:
+		default:
+			overflowOnce.Do(func() {
+				close(overflow)
+			})
+		}
+	})
+
+	go func() {
+		defer api.bus.Unsubscribe(busSub)
+		for {
+			select {
+			case <-overflow:
+				notifier.Notify(rpcSub.ID, &SubscriptionError{Error: errNotificationsOverflow})
+				notifier.Unsubscribe(rpcSub.ID)
+				return
+			default:
+			}
+			select {
+			case e := <-queue:
+				for _, data := range convert(e) {
+					notifier.Notify(rpcSub.ID, data)
+				}
+			case <-overflow:
+				continue
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func convertToContractEvent(header *types.Header, receipt *types.TxReceipt, event *types.TxEvent) *ContractEvent {
+	e := &ContractEvent{
+		Contract:    event.Contract,
+		Event:       event.EventName,
+		TxHash:      receipt.TxHash,
+		BlockHash:   header.Hash(),
+		BlockHeight: header.Height(),
+	}
+	for i := range event.Data {
+		e.Args = append(e.Args, event.Data[i])
+	}
+	return e
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/rpc"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type BlockingSubscriptionApi struct {
+	*SubscriptionApi
+	release chan struct{}
+}
+
+// Blocking converts events slowly to fill the notifications queue
+func (api *BlockingSubscriptionApi) Blocking(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, events.NewTxEventID, func(e eventbus.Event) []interface{} {
+		<-api.release
+		return []interface{}{"tx"}
+	})
+}
+
+func newSubscriptionClient(t *testing.T, service interface{}) *rpc.Client {
+	server := rpc.NewServer("")
+	require.NoError(t, server.RegisterName("bcn", service))
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return client
+}
+
+func TestSubscriptionApi_ContractEvents(t *testing.T) {
+	bus := eventbus.New()
+	client := newSubscriptionClient(t, NewSubscriptionApi(bus))
+
+	contract := common.Address{0x1}
+	notifications := make(chan *ContractEvent, 10)
+	sub, err := client.Subscribe(context.Background(), "bcn", notifications, "contractEvents", ContractEventsArgs{
+		Contracts: []common.Address{contract},
+		Events:    []string{"transfer"},
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	block := &types.Block{Header: &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2}}}
+	bus.Publish(&events.NewBlockEvent{
+		Block: block,
+		Receipts: types.TxReceipts{
+			{
+				TxHash: common.Hash{0x2},
+				Events: []*types.TxEvent{
+					{Contract: contract, EventName: "transfer", Data: [][]byte{{0x3}}},
+					{Contract: contract, EventName: "other"},
+					{Contract: common.Address{0x4}, EventName: "transfer"},
+				},
+			},
+		},
+	})
+
+	select {
+	case e := <-notifications:
+		require.Equal(t, contract, e.Contract)
+		require.Equal(t, "transfer", e.Event)
+		require.Equal(t, common.Hash{0x2}, e.TxHash)
+		require.Equal(t, block.Hash(), e.BlockHash)
+		require.Equal(t, uint64(2), e.BlockHeight)
+		require.Len(t, e.Args, 1)
+	case <-time.After(time.Second):
+		require.Fail(t, "contract event is not received")
+	}
+	select {
+	case e := <-notifications:
+		require.Failf(t, "unexpected contract event", "%v", e.Event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscriptionApi_Overflow(t *testing.T) {
+	bus := eventbus.New()
+	api := &BlockingSubscriptionApi{
+		SubscriptionApi: NewSubscriptionApi(bus),
+		release:         make(chan struct{}),
+	}
+	client := newSubscriptionClient(t, api)
+
+	notifications := make(chan json.RawMessage, notificationsBufferSize*2)
+	_, err := client.Subscribe(context.Background(), "bcn", notifications, "blocking")
+	require.NoError(t, err)
+
+	for i := 0; i < notificationsBufferSize*2; i++ {
+		bus.Publish(&events.NewTxEvent{Tx: &types.Transaction{}})
+	}
+	close(api.release)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case data := <-notifications:
+			subErr := &SubscriptionError{}
+			if json.Unmarshal(data, subErr) != nil || subErr.Error == "" {
+				continue
+			}
+			require.Equal(t, errNotificationsOverflow, subErr.Error)
+			return
+		case <-timeout:
+			require.Fail(t, "overflow error is not received")
+			return
+		}
+	}
+}
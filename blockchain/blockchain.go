@@ -34,6 +34,7 @@ import (
 	"github.com/idena-network/idena-go/stats/collector"
 	"github.com/idena-network/idena-go/subscriptions"
 	"github.com/idena-network/idena-go/vm"
+	"github.com/idena-network/idena-go/vm/env"
 	cid2 "github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -171,6 +172,7 @@ func (chain *Blockchain) InitializeChain() error {
 
 	chain.coinBaseAddress = chain.secStore.GetAddress()
 	chain.pubKey = chain.secStore.GetPubKey()
+	chain.repo.DeleteLegacyEvents()
 	head := chain.GetHead()
 	if head != nil {
 		chain.setCurrentHead(head)
@@ -213,6 +215,9 @@ func (chain *Blockchain) InitializeChain() error {
 		}
 	}
 	chain.indexer.initialize(chain.coinBaseAddress)
+	if chain.config.Blockchain.IndexAllTxs {
+		go chain.indexer.backfillTxs(chain.Head.Height(), chain.GetBlockByHeight)
+	}
 	chain.PreliminaryHead = chain.repo.ReadPreliminaryHead()
 	go chain.ipfsLoad()
 	log.Info("Chain initialized", "block", chain.Head.Hash().Hex(), "height", chain.Head.Height())
@@ -445,7 +450,8 @@ func (chain *Blockchain) AddBlock(block *types.Block, checkState *appstate.AppSt
 			chain.genesisInfo.Genesis = block.Header
 		}
 		chain.bus.Publish(&events.NewBlockEvent{
-			Block: block,
+			Block:    block,
+			Receipts: blockInsertionResult.txReceipts,
 		})
 		if block.Header.Flags().HasFlag(types.ValidationFinished) {
 			shardId, _ := chain.CoinbaseShard()
@@ -1119,6 +1125,13 @@ func (chain *Blockchain) processTxs(txs []*types.Transaction, context *txsExecut
 
 type task = func()
 
+// ApplyTxOnState applies the tx to the given state as if it was included into the next block
+func (chain *Blockchain) ApplyTxOnState(appState *appstate.AppState, vm vm.VM, tx *types.Transaction) (*big.Int, *types.TxReceipt, error) {
+	context := &txExecutionContext{appState: appState, vm: vm, height: chain.Head.Height() + 1}
+	fee, receipt, _, err := chain.applyTxOnState(tx, context)
+	return fee, receipt, err
+}
+
 func (chain *Blockchain) applyTxOnState(tx *types.Transaction, context *txExecutionContext) (*big.Int, *types.TxReceipt, task, error) {
 
 	statsCollector := context.statsCollector
@@ -1645,7 +1658,7 @@ func calculateTxBloom(block *types.Block, receipts types.TxReceipts) []byte {
 
 	for _, r := range receipts {
 		for _, e := range r.Events {
-			values[string(append(r.ContractAddress.Bytes(), []byte(e.EventName)...))] = struct{}{}
+			values[string(append(e.Contract.Bytes(), []byte(e.EventName)...))] = struct{}{}
 		}
 	}
 
@@ -1768,9 +1781,9 @@ func (chain *Blockchain) insertBlock(block *types.Block, diff *state.IdentitySta
 	chain.WriteIdentityStateDiff(block.Height(), diff)
 	chain.WriteTxIndex(block.Hash(), block.Body.Transactions)
 	if receipts != nil {
-		chain.WriteTxReceipts(block.Header.ProposedHeader.TxReceiptsCid, receipts)
+		chain.WriteTxReceipts(block.Height(), block.Header.ProposedHeader.TxReceiptsCid, receipts)
 	}
-	chain.indexer.HandleBlockTransactions(block.Header, block.Body.Transactions)
+	chain.indexer.HandleBlockTransactions(block.Header, block.Body.Transactions, receipts)
 	chain.setCurrentHead(block.Header)
 	return nil
 }
@@ -1785,7 +1798,7 @@ func (chain *Blockchain) WriteTxIndex(hash common.Hash, txs types.Transactions)
 	}
 }
 
-func (chain *Blockchain) WriteTxReceipts(cid []byte, receipts types.TxReceipts) {
+func (chain *Blockchain) WriteTxReceipts(height uint64, cid []byte, receipts types.TxReceipts) {
 	m := make(map[common.Address]map[string]struct{})
 	for _, s := range chain.subManager.Subscriptions() {
 		eventMap, ok := m[s.Contract]
@@ -1795,6 +1808,7 @@ func (chain *Blockchain) WriteTxReceipts(cid []byte, receipts types.TxReceipts)
 		}
 		eventMap[s.Event] = struct{}{}
 	}
+	indexAll := chain.config.Blockchain.IndexAllEvents
 
 	for i, r := range receipts {
 		idx := &types.TxReceiptIndex{
@@ -1802,12 +1816,13 @@ func (chain *Blockchain) WriteTxReceipts(cid []byte, receipts types.TxReceipts)
 			ReceiptCid: cid,
 		}
 		chain.repo.WriteReceiptIndex(r.TxHash, idx)
-		if eventMap, ok := m[r.ContractAddress]; ok {
-			for idx, event := range r.Events {
-				if _, ok := eventMap[event.EventName]; ok {
-					chain.repo.WriteEvent(r.ContractAddress, r.TxHash, uint32(idx), event)
+		for idx, event := range r.Events {
+			if !indexAll {
+				if _, ok := m[event.Contract][event.EventName]; !ok {
+					continue
 				}
 			}
+			chain.repo.WriteIndexedEvent(height, uint32(i), uint32(idx), r.TxHash, event)
 		}
 	}
 }
@@ -2108,6 +2123,10 @@ func (chain *Blockchain) GetBlockByHeight(height uint64) *types.Block {
 	return chain.GetBlock(hash)
 }
 
+func (chain *Blockchain) GetBlockHeader(hash common.Hash) *types.Header {
+	return chain.repo.ReadBlockHeader(hash)
+}
+
 func (chain *Blockchain) GetBlockHeaderByHeight(height uint64) *types.Header {
 	hash := chain.repo.ReadCanonicalHash(height)
 	if hash == (common.Hash{}) {
@@ -2165,6 +2184,84 @@ func (chain *Blockchain) GetTx(hash common.Hash) (*types.Transaction, *types.Tra
 	return tx, idx
 }
 
+// TraceTx re-executes the mined contract tx against the state of the parent block with preceding txs of the block applied
+func (chain *Blockchain) TraceTx(hash common.Hash) (*types.Transaction, *types.TxReceipt, *env.Tracer, error) {
+	tx, idx := chain.GetTx(hash)
+	if tx == nil {
+		return nil, nil, nil, errors.New("transaction is not found")
+	}
+	if tx.Type != types.CallContractTx && tx.Type != types.DeployContractTx && tx.Type != types.TerminateContractTx {
+		return nil, nil, nil, errors.New("transaction is not a contract one")
+	}
+	block := chain.GetBlock(idx.BlockHash)
+	if block == nil {
+		return nil, nil, nil, errors.New("block is not found")
+	}
+	appState, err := chain.appState.ForCheck(block.Height() - 1)
+	if err != nil {
+		return nil, nil, nil, errors.Errorf("state at height %v is not available", block.Height()-1)
+	}
+
+	// the tx is executed by the rules of the consensus version which was active at the block
+	cfg := *chain.config
+	cfg.Consensus = chain.consensusConfigAt(block.Header)
+
+	blockVm := vm.NewVmImpl(appState, block.Header, nil, &cfg)
+	for _, blockTx := range block.Body.Transactions[:idx.Idx] {
+		context := &txExecutionContext{appState: appState, vm: blockVm, height: block.Height()}
+		if _, _, _, err := chain.applyTxOnState(blockTx, context); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "failed to apply tx %v", blockTx.Hash().Hex())
+		}
+	}
+
+	tracer := &env.Tracer{}
+	context := &txExecutionContext{
+		appState: appState,
+		vm:       vm.NewTracingVmImpl(appState, block.Header, nil, &cfg, tracer),
+		height:   block.Height(),
+	}
+	_, receipt, _, err := chain.applyTxOnState(tx, context)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tx, receipt, tracer, nil
+}
+
+// consensusConfigAt returns the config of the consensus version which was active when the block was applied,
+// the local config is returned for the current version
+func (chain *Blockchain) consensusConfigAt(header *types.Header) *config.ConsensusConf {
+	cfg := chain.config.Consensus
+	for !chain.upgradedBefore(cfg, header) {
+		prev, ok := config.ConsensusVersions[cfg.Version-1]
+		if !ok {
+			break
+		}
+		cfg = prev
+	}
+	return cfg
+}
+
+// upgradedBefore checks whether the upgrade to the consensus version was applied before the block, upgrade blocks are
+// proposed within the activation period of the version only
+func (chain *Blockchain) upgradedBefore(cfg *config.ConsensusConf, header *types.Header) bool {
+	if header.Time() < cfg.StartActivationDate {
+		return false
+	}
+	if header.Time() > cfg.EndActivationDate {
+		return true
+	}
+	for height := header.Height() - 1; height > 0; height-- {
+		prev := chain.GetBlockHeaderByHeight(height)
+		if prev == nil || prev.Time() < cfg.StartActivationDate {
+			return false
+		}
+		if prev.ProposedHeader != nil && prev.ProposedHeader.Upgrade == uint32(cfg.Version) {
+			return true
+		}
+	}
+	return false
+}
+
 func (chain *Blockchain) GetCommitteeSize(vc *validators.ValidatorsCache, final bool) int {
 	var cnt = vc.OnlineSize()
 	percent := chain.config.Consensus.CommitteePercent
@@ -2542,8 +2639,8 @@ func (chain *Blockchain) AtomicSwitchToPreliminary(manifest *snapshot.Manifest)
 	return nil
 }
 
-func (chain *Blockchain) ReadEvents(contract common.Address) []*types.SavedEvent {
-	return chain.repo.GetSavedEvents(contract)
+func (chain *Blockchain) ReadEvents(filter *database.EventFilter, count int, token []byte) ([]*types.SavedEvent, []byte, error) {
+	return chain.repo.GetIndexedEvents(filter, count, token)
 }
 
 func (chain *Blockchain) WritePreliminaryConsensusVersion(ver uint32) {
@@ -2630,6 +2727,36 @@ func (chain *Blockchain) MinimalShard(appState *appstate.AppState) common.ShardI
 	return minShard
 }
 
+// StateProof returns the encoded account of the address or the value stored by the contract under the key if the key is
+// not empty, together with the proof against the state root of the block at the height. Proofs are provided for the
+// recent blocks whose states are kept by every node and for the first blocks of epochs only. The state is loaded apart
+// from the cache of readonly states, so requests of peers do not evict states used by the node itself.
+func (chain *Blockchain) StateProof(height uint64, addr common.Address, key []byte) (value []byte, proof []byte, err error) {
+	if !chain.isStateProofHeight(height) {
+		return nil, nil, errors.Errorf("state proof for height %v is not provided", height)
+	}
+	stateDb, err := chain.appState.State.Readonly(int64(height))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(key) == 0 {
+		return stateDb.GetAccountWithProof(addr)
+	}
+	return stateDb.GetContractValueWithProof(addr, key)
+}
+
+func (chain *Blockchain) isStateProofHeight(height uint64) bool {
+	head := chain.Head.Height()
+	if height > head {
+		return false
+	}
+	if head-height < state.MaxSavedStatesCount {
+		return true
+	}
+	header := chain.GetBlockHeaderByHeight(height)
+	return header != nil && header.Flags().HasFlag(types.ValidationFinished)
+}
+
 func (chain *Blockchain) ShardsNum() uint32 {
 	stateDb, err := chain.appState.Readonly(chain.Head.Height())
 	if err != nil {
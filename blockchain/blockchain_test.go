@@ -12,6 +12,7 @@ import (
 	"github.com/idena-network/idena-go/config"
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/core/state/stateproof"
 	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/tests"
 	"github.com/shopspring/decimal"
@@ -1176,3 +1177,53 @@ func TestBalance_shards_increasing(t *testing.T) {
 		require.Greater(t, s, common.MinShardSize)
 	}
 }
+
+func TestBlockchain_StateProof(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chain, _, _, _ := NewTestBlockchain(false, map[common.Address]config.GenesisAllocation{
+		addr: {Balance: big.NewInt(100)},
+	})
+	head := chain.Head
+
+	account, proof, err := chain.StateProof(head.Height(), addr, nil)
+	require.NoError(t, err)
+	decoded, err := stateproof.VerifyAccount(head.Root(), addr, account, proof)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), decoded.Balance)
+
+	absent := common.Address{0x1}
+	account, proof, err = chain.StateProof(head.Height(), absent, nil)
+	require.NoError(t, err)
+	require.Nil(t, account)
+	decoded, err = stateproof.VerifyAccount(head.Root(), absent, account, proof)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+
+	_, err = stateproof.VerifyAccount(head.Root(), absent, []byte{0x1}, proof)
+	require.Error(t, err)
+
+	// proofs are provided for recent blocks only
+	_, _, err = chain.StateProof(head.Height()+1, addr, nil)
+	require.Error(t, err)
+	chain.GenerateEmptyBlocks(state.MaxSavedStatesCount)
+	_, _, err = chain.StateProof(head.Height(), addr, nil)
+	require.Error(t, err)
+	_, _, err = chain.StateProof(head.Height()+1, addr, nil)
+	require.NoError(t, err)
+}
+
+func TestBlockchain_consensusConfigAt(t *testing.T) {
+	chain, _, _, _ := NewTestBlockchain(false, nil)
+	v7 := *config.ConsensusVersions[config.ConsensusV7]
+	chain.config.Consensus = &v7
+
+	header := func(time int64) *types.Header {
+		return &types.Header{ProposedHeader: &types.ProposedHeader{Height: chain.Head.Height() + 1, Time: time}}
+	}
+
+	require.Equal(t, config.ConsensusV6, chain.consensusConfigAt(header(v7.StartActivationDate-1)).Version)
+	require.True(t, chain.consensusConfigAt(header(v7.EndActivationDate+1)) == &v7)
+	// the upgrade block is not found within the activation period
+	require.Equal(t, config.ConsensusV6, chain.consensusConfigAt(header(v7.StartActivationDate+1)).Version)
+}
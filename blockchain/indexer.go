@@ -9,17 +9,21 @@ import (
 	"github.com/idena-network/idena-go/database"
 	"github.com/idena-network/idena-go/events"
 	"github.com/idena-network/idena-go/keystore"
+	"github.com/idena-network/idena-go/log"
 	dbm "github.com/tendermint/tm-db"
 	"sync"
 )
 
+const backfillProgressRange = 1000
+
 type indexer struct {
-	coinbase common.Address
-	repo     *database.Repo
-	bus      eventbus.Bus
-	keystore *keystore.KeyStore
-	cfg      *config.Config
-	mutex    sync.Mutex
+	coinbase    common.Address
+	repo        *database.Repo
+	bus         eventbus.Bus
+	keystore    *keystore.KeyStore
+	cfg         *config.Config
+	mutex       sync.Mutex
+	backfilling bool
 }
 
 func newBlockchainIndexer(db dbm.DB, bus eventbus.Bus, cfg *config.Config, keystore *keystore.KeyStore) *indexer {
@@ -35,7 +39,9 @@ func (i *indexer) initialize(coinbase common.Address) {
 	i.coinbase = coinbase
 }
 
-func (i *indexer) HandleBlockTransactions(header *types.Header, txs []*types.Transaction) {
+// HandleBlockTransactions indexes txs of the block, receipts of the executed txs are used to index recipients of coins
+// sent by contracts, they are nil for blocks which are not executed by the node
+func (i *indexer) HandleBlockTransactions(header *types.Header, txs []*types.Transaction, receipts types.TxReceipts) {
 
 	i.repo.DeleteOutdatedBurntCoins(header.Height(), i.cfg.Blockchain.BurnTxRange)
 
@@ -46,12 +52,99 @@ func (i *indexer) HandleBlockTransactions(header *types.Header, txs []*types.Tra
 	}
 	accountsMap[i.coinbase] = struct{}{}
 
+	recipients := make(map[common.Hash][]common.Address)
+	for _, r := range receipts {
+		if len(r.Recipients) > 0 {
+			recipients[r.TxHash] = r.Recipients
+		}
+	}
+
 	for _, tx := range txs {
 		sender, _ := types.Sender(tx)
-		i.handleOwnTx(header, sender, tx, accountsMap)
+		if i.cfg.Blockchain.IndexAllTxs {
+			i.handleTx(header, sender, tx)
+		} else {
+			i.handleOwnTx(header, sender, tx, accountsMap)
+		}
+		if r, ok := recipients[tx.Hash()]; ok {
+			i.handleContractTransfers(header, sender, tx, r, accountsMap)
+		}
 		i.handleBurnTx(header.Height(), sender, tx)
 		i.handleOwnDeleteFlipTx(sender, tx)
 	}
+
+	if i.cfg.Blockchain.IndexAllTxs {
+		i.mutex.Lock()
+		if !i.backfilling {
+			i.repo.WriteFullTxIndexHeight(header.Height())
+		}
+		i.mutex.Unlock()
+	}
+}
+
+func (i *indexer) handleTx(header *types.Header, sender common.Address, tx *types.Transaction) {
+	i.repo.SaveTx(sender, header.Hash(), header.Time(), header.FeePerGas(), tx)
+	if tx.To != nil && *tx.To != sender {
+		i.repo.SaveTx(*tx.To, header.Hash(), header.Time(), header.FeePerGas(), tx)
+	}
+}
+
+// handleContractTransfers saves the tx for recipients of coins sent by contracts during the tx
+func (i *indexer) handleContractTransfers(header *types.Header, sender common.Address, tx *types.Transaction, recipients []common.Address, accountsMap map[common.Address]struct{}) {
+	for _, recipient := range recipients {
+		if recipient == sender || tx.To != nil && recipient == *tx.To {
+			continue
+		}
+		if _, ok := accountsMap[recipient]; ok || i.cfg.Blockchain.IndexAllTxs {
+			i.repo.SaveTx(recipient, header.Hash(), header.Time(), header.FeePerGas(), tx)
+		}
+	}
+}
+
+// backfillTxs saves transactions of all the addresses for the blocks added before the full indexing mode was enabled,
+// recipients of coins sent by contracts are not stored in receipts, so they are indexed for new blocks only. Bodies of
+// blocks before the intermediate genesis are not stored by fast synced nodes, so these blocks are skipped and the
+// backfilling stops at the first block which is not available.
+func (i *indexer) backfillTxs(head uint64, getBlock func(height uint64) *types.Block) {
+	from := i.repo.ReadFullTxIndexHeight() + 1
+	if genesis := i.repo.ReadIntermediateGenesis(); from <= genesis {
+		log.Info("Transactions before the intermediate genesis are not indexed", "genesis", genesis)
+		from = genesis + 1
+	}
+	if from > head {
+		return
+	}
+	i.mutex.Lock()
+	i.backfilling = true
+	i.mutex.Unlock()
+
+	log.Info("Start indexing transactions of all addresses", "from", from, "to", head)
+	indexed := head
+	for height := from; height <= head; height++ {
+		block := getBlock(height)
+		if block == nil {
+			log.Warn("Transactions indexing is stopped, block is not available", "height", height, "head", head)
+			indexed = height - 1
+			break
+		}
+		for _, tx := range block.Body.Transactions {
+			sender, _ := types.Sender(tx)
+			i.handleTx(block.Header, sender, tx)
+		}
+		if height%backfillProgressRange == 0 {
+			i.repo.WriteFullTxIndexHeight(height)
+		}
+	}
+
+	i.mutex.Lock()
+	i.backfilling = false
+	if i.repo.ReadFullTxIndexHeight() < indexed {
+		i.repo.WriteFullTxIndexHeight(indexed)
+	}
+	i.mutex.Unlock()
+	if indexed == head {
+		log.Info("Transactions of all addresses are indexed", "height", head)
+	}
 }
 
 func (i *indexer) handleOwnTx(header *types.Header, sender common.Address, tx *types.Transaction, accountsMap map[common.Address]struct{}) {
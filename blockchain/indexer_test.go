@@ -45,7 +45,7 @@ func TestBlockchain_saveOwnTxs(t *testing.T) {
 				FeePerGas: big.NewInt(1),
 			},
 		}
-		chain.indexer.HandleBlockTransactions(header, []*types.Transaction{item.tx})
+		chain.indexer.HandleBlockTransactions(header, []*types.Transaction{item.tx}, nil)
 	}
 
 	data, token := chain.ReadTxs(addr, 5, nil)
@@ -140,6 +140,142 @@ func Test_handleOwnTxsWithAccounts(t *testing.T) {
 	require.Equal(0, len(data))
 }
 
+func Test_indexAllTxs(t *testing.T) {
+	require := require.New(t)
+
+	chain, _, _, _ := NewTestBlockchain(true, nil)
+	chain.config.Blockchain.IndexAllTxs = true
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	addr1 := crypto.PubkeyToAddress(key1.PublicKey)
+	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
+	contract := common.Address{0x1}
+
+	createHeader := func(height uint64) *types.Header {
+		return &types.Header{
+			ProposedHeader: &types.ProposedHeader{
+				Height:    height,
+				Time:      int64(height),
+				FeePerGas: big.NewInt(1),
+			},
+		}
+	}
+
+	blocks := map[uint64]*types.Block{
+		1: {Header: createHeader(1), Body: &types.Body{Transactions: []*types.Transaction{
+			tests.GetFullTx(1, 1, key1, types.SendTx, nil, &addr2, nil),
+		}}},
+		2: {Header: createHeader(2), Body: &types.Body{Transactions: []*types.Transaction{
+			tests.GetFullTx(1, 1, key2, types.CallContractTx, nil, &contract, nil),
+			tests.GetFullTx(2, 1, key2, types.SendTx, nil, &addr2, nil),
+		}}},
+	}
+	chain.indexer.backfillTxs(2, func(height uint64) *types.Block {
+		return blocks[height]
+	})
+	require.Equal(uint64(2), chain.repo.ReadFullTxIndexHeight())
+
+	chain.indexer.HandleBlockTransactions(createHeader(3), []*types.Transaction{
+		tests.GetFullTx(2, 1, key1, types.ActivationTx, nil, &addr2, nil),
+	}, nil)
+	require.Equal(uint64(3), chain.repo.ReadFullTxIndexHeight())
+
+	data, _ := chain.ReadTxs(addr1, 10, nil)
+	require.Equal(2, len(data))
+
+	data, _ = chain.ReadTxs(addr2, 10, nil)
+	require.Equal(4, len(data))
+	require.Equal(types.ActivationTx, data[0].Tx.Type)
+
+	data, _ = chain.ReadTxs(contract, 10, nil)
+	require.Equal(1, len(data))
+
+	// already indexed blocks are skipped
+	chain.indexer.backfillTxs(3, func(height uint64) *types.Block {
+		require.Fail("unexpected block request")
+		return nil
+	})
+}
+
+func Test_backfillTxsOfFastSyncedNode(t *testing.T) {
+	require := require.New(t)
+
+	chain, _, _, key := NewTestBlockchain(true, nil)
+	chain.config.Blockchain.IndexAllTxs = true
+	chain.repo.WriteIntermediateGenesis(nil, 5)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	var requested []uint64
+	getBlock := func(height uint64) *types.Block {
+		requested = append(requested, height)
+		if height > 7 {
+			return nil
+		}
+		return &types.Block{
+			Header: &types.Header{
+				ProposedHeader: &types.ProposedHeader{
+					Height:    height,
+					Time:      int64(height),
+					FeePerGas: big.NewInt(1),
+				},
+			},
+			Body: &types.Body{Transactions: []*types.Transaction{
+				tests.GetFullTx(uint32(height), 1, key, types.SendTx, nil, &addr, nil),
+			}},
+		}
+	}
+
+	// blocks before the intermediate genesis are skipped, the backfilling stops at the first missing block
+	chain.indexer.backfillTxs(10, getBlock)
+	require.Equal([]uint64{6, 7, 8}, requested)
+	require.Equal(uint64(7), chain.repo.ReadFullTxIndexHeight())
+	data, _ := chain.ReadTxs(addr, 10, nil)
+	require.Equal(2, len(data))
+}
+
+func Test_indexContractTransfers(t *testing.T) {
+	require := require.New(t)
+
+	chain, _, _, key := NewTestBlockchain(true, nil)
+
+	key2, _ := crypto.GenerateKey()
+	key3, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := crypto.PubkeyToAddress(key2.PublicKey)
+	notOwnRecipient := crypto.PubkeyToAddress(key3.PublicKey)
+	contract := common.Address{0x1}
+
+	tx := tests.GetFullTx(1, 1, key, types.CallContractTx, nil, &contract, nil)
+	receipts := types.TxReceipts{
+		{TxHash: tx.Hash(), Recipients: []common.Address{recipient, sender, contract, notOwnRecipient}},
+	}
+	header := &types.Header{
+		ProposedHeader: &types.ProposedHeader{
+			Height:    1,
+			FeePerGas: big.NewInt(1),
+		},
+	}
+
+	chain.indexer.handleContractTransfers(header, sender, tx, receipts[0].Recipients, map[common.Address]struct{}{recipient: {}})
+
+	data, _ := chain.ReadTxs(recipient, 10, nil)
+	require.Equal(1, len(data))
+	require.Equal(tx.Hash(), data[0].Tx.Hash())
+
+	data, _ = chain.ReadTxs(notOwnRecipient, 10, nil)
+	require.Equal(0, len(data))
+
+	chain.config.Blockchain.IndexAllTxs = true
+	chain.indexer.HandleBlockTransactions(header, []*types.Transaction{tx}, receipts)
+
+	data, _ = chain.ReadTxs(notOwnRecipient, 10, nil)
+	require.Equal(1, len(data))
+
+	data, _ = chain.ReadTxs(contract, 10, nil)
+	require.Equal(1, len(data))
+}
+
 func Test_Blockchain_saveBurntCoins(t *testing.T) {
 	require := require.New(t)
 
@@ -167,7 +303,7 @@ func Test_Blockchain_saveBurntCoins(t *testing.T) {
 			attachments.CreateBurnAttachment("1")),
 		tests.GetFullTx(0, 0, key, types.BurnTx, big.NewInt(3), nil,
 			attachments.CreateBurnAttachment("1")),
-	})
+	}, nil)
 
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
@@ -192,7 +328,7 @@ func Test_Blockchain_saveBurntCoins(t *testing.T) {
 		tests.GetFullTx(0, 0, key, types.BurnTx, big.NewInt(1), nil,
 			attachments.CreateBurnAttachment("1")),
 		tests.GetFullTx(0, 0, key, types.SendTx, big.NewInt(2), nil, nil),
-	})
+	}, nil)
 
 	burntCoins = chain.ReadTotalBurntCoins()
 	require.Equal(3, len(burntCoins))
@@ -211,7 +347,7 @@ func Test_Blockchain_saveBurntCoins(t *testing.T) {
 	chain.indexer.HandleBlockTransactions(createHeader(4), []*types.Transaction{
 		tests.GetFullTx(0, 0, key, types.BurnTx, big.NewInt(3), nil,
 			attachments.CreateBurnAttachment("1")),
-	})
+	}, nil)
 
 	burntCoins = chain.ReadTotalBurntCoins()
 	require.Equal(2, len(burntCoins))
@@ -224,7 +360,7 @@ func Test_Blockchain_saveBurntCoins(t *testing.T) {
 	chain.indexer.HandleBlockTransactions(createHeader(7), []*types.Transaction{
 		tests.GetFullTx(0, 0, key, types.BurnTx, big.NewInt(1), nil,
 			attachments.CreateBurnAttachment("1")),
-	})
+	}, nil)
 
 	burntCoins = chain.ReadTotalBurntCoins()
 	require.Equal(1, len(burntCoins))
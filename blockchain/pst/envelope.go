@@ -0,0 +1,244 @@
+// Package pst implements partially signed transaction envelopes used to collect votes of the Multisig contract
+// signers off-chain.
+package pst
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+const (
+	multisigVoteMethod = "send"
+	multisigPushMethod = "push"
+)
+
+// Envelope carries the multisig vote transaction, the signers allowed to vote and signatures collected so far.
+// Each signer signs the vote transaction with its own nonce and the push transaction with the next one,
+// so any of the signers' push transactions can be used to transfer coins once enough votes are sent.
+type Envelope struct {
+	// Tx is the vote transaction template, its nonce and signature are provided by each signer
+	Tx            *types.Transaction
+	Signers       []common.Address
+	MinSignatures byte
+	Signatures    []*Signature
+}
+
+type Signature struct {
+	Signer        common.Address
+	Nonce         uint32
+	VoteSignature []byte
+	PushSignature []byte
+}
+
+// NewMultisigEnvelope creates an envelope to transfer amount from the multisig contract to dest
+func NewMultisigEnvelope(contract common.Address, dest common.Address, amount *big.Int, maxFee *big.Int, epoch uint16,
+	signers []common.Address, minSignatures byte) (*Envelope, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("signers are not specified")
+	}
+	if minSignatures == 0 || int(minSignatures) > len(signers) {
+		return nil, errors.New("min signatures should be in range [1;signers count]")
+	}
+	for i, signer := range signers {
+		for _, other := range signers[:i] {
+			if signer == other {
+				return nil, errors.Errorf("duplicated signer %v", signer.Hex())
+			}
+		}
+	}
+	payload, err := attachments.CreateCallContractAttachment(multisigVoteMethod, dest.Bytes(), amount.Bytes()).ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Tx: &types.Transaction{
+			Type:    types.CallContractTx,
+			To:      &contract,
+			Epoch:   epoch,
+			Amount:  new(big.Int),
+			MaxFee:  maxFee,
+			Tips:    new(big.Int),
+			Payload: payload,
+		},
+		Signers:       signers,
+		MinSignatures: minSignatures,
+	}, nil
+}
+
+// ParseVoters decodes the result of the "voters" read method of the multisig contract
+func ParseVoters(data []byte) ([]common.Address, error) {
+	if len(data)%common.AddressLength != 0 {
+		return nil, errors.New("invalid voters data")
+	}
+	voters := make([]common.Address, 0, len(data)/common.AddressLength)
+	for i := 0; i < len(data); i += common.AddressLength {
+		var voter common.Address
+		voter.SetBytes(data[i : i+common.AddressLength])
+		voters = append(voters, voter)
+	}
+	return voters, nil
+}
+
+// SelectSigners returns the requested signers if all of them are voters of the contract or all the voters if no
+// signers are requested
+func SelectSigners(voters []common.Address, requested []common.Address) ([]common.Address, error) {
+	if len(requested) == 0 {
+		return voters, nil
+	}
+	for _, signer := range requested {
+		found := false
+		for _, voter := range voters {
+			if voter == signer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("%v is not a voter of the contract", signer.Hex())
+		}
+	}
+	return requested, nil
+}
+
+func (e *Envelope) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(e)
+}
+
+func (e *Envelope) FromBytes(data []byte) error {
+	if err := rlp.DecodeBytes(data, e); err != nil {
+		return err
+	}
+	if e.Tx == nil || e.Tx.Type != types.CallContractTx || e.Tx.To == nil {
+		return errors.New("invalid envelope transaction")
+	}
+	attachment := attachments.ParseCallContractAttachment(e.Tx)
+	if attachment == nil || attachment.Method != multisigVoteMethod {
+		return errors.New("invalid envelope transaction payload")
+	}
+	return nil
+}
+
+func (e *Envelope) voteTx(nonce uint32) *types.Transaction {
+	return &types.Transaction{
+		AccountNonce: nonce,
+		Epoch:        e.Tx.Epoch,
+		Type:         e.Tx.Type,
+		To:           e.Tx.To,
+		Amount:       e.Tx.Amount,
+		MaxFee:       e.Tx.MaxFee,
+		Tips:         e.Tx.Tips,
+		Payload:      e.Tx.Payload,
+	}
+}
+
+func (e *Envelope) pushTx(nonce uint32) *types.Transaction {
+	attachment := attachments.ParseCallContractAttachment(e.Tx)
+	payload, _ := attachments.CreateCallContractAttachment(multisigPushMethod, attachment.Args...).ToBytes()
+	tx := e.voteTx(nonce)
+	tx.Payload = payload
+	return tx
+}
+
+// UnsignedTxs returns vote and push transactions which should be signed by the signer with the given nonce
+func (e *Envelope) UnsignedTxs(nonce uint32) (vote *types.Transaction, push *types.Transaction) {
+	return e.voteTx(nonce), e.pushTx(nonce + 1)
+}
+
+// Sign adds signatures of the key owner, nonce should be the next nonce of the signer
+func (e *Envelope) Sign(key *ecdsa.PrivateKey, nonce uint32) error {
+	vote, push := e.UnsignedTxs(nonce)
+	signedVote, err := types.SignTx(vote, key)
+	if err != nil {
+		return err
+	}
+	signedPush, err := types.SignTx(push, key)
+	if err != nil {
+		return err
+	}
+	signer, _ := types.Sender(signedVote)
+	return e.AddSignature(&Signature{
+		Signer:        signer,
+		Nonce:         nonce,
+		VoteSignature: signedVote.Signature,
+		PushSignature: signedPush.Signature,
+	})
+}
+
+// AddSignature verifies the signature and adds it to the envelope replacing the previous one of the same signer
+func (e *Envelope) AddSignature(signature *Signature) error {
+	if !e.isSigner(signature.Signer) {
+		return errors.Errorf("%v is not a signer", signature.Signer.Hex())
+	}
+	vote, push := e.UnsignedTxs(signature.Nonce)
+	vote.Signature = signature.VoteSignature
+	push.Signature = signature.PushSignature
+	for _, tx := range []*types.Transaction{vote, push} {
+		if sender, err := types.Sender(tx); err != nil || sender != signature.Signer {
+			return errors.Errorf("invalid signature of %v", signature.Signer.Hex())
+		}
+	}
+	for i, s := range e.Signatures {
+		if s.Signer == signature.Signer {
+			e.Signatures[i] = signature
+			return nil
+		}
+	}
+	e.Signatures = append(e.Signatures, signature)
+	return nil
+}
+
+// Merge adds signatures of the other envelope of the same transaction
+func (e *Envelope) Merge(other *Envelope) error {
+	if !e.sameTx(other) {
+		return errors.New("envelopes have different transactions or signers")
+	}
+	for _, signature := range other.Signatures {
+		if err := e.AddSignature(signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finalize returns signed vote transactions and a push transaction which should be sent after the votes are mined
+func (e *Envelope) Finalize() (votes []*types.Transaction, push *types.Transaction, err error) {
+	if len(e.Signatures) < int(e.MinSignatures) {
+		return nil, nil, errors.Errorf("not enough signatures, collected: %v, required: %v", len(e.Signatures), e.MinSignatures)
+	}
+	for _, signature := range e.Signatures {
+		vote := e.voteTx(signature.Nonce)
+		vote.Signature = signature.VoteSignature
+		votes = append(votes, vote)
+	}
+	last := e.Signatures[len(e.Signatures)-1]
+	push = e.pushTx(last.Nonce + 1)
+	push.Signature = last.PushSignature
+	return votes, push, nil
+}
+
+func (e *Envelope) isSigner(addr common.Address) bool {
+	for _, signer := range e.Signers {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Envelope) sameTx(other *Envelope) bool {
+	if e.MinSignatures != other.MinSignatures || len(e.Signers) != len(other.Signers) {
+		return false
+	}
+	for i := range e.Signers {
+		if e.Signers[i] != other.Signers[i] {
+			return false
+		}
+	}
+	return e.voteTx(0).Hash() == other.voteTx(0).Hash() && bytes.Equal(e.Tx.Payload, other.Tx.Payload)
+}
@@ -0,0 +1,97 @@
+package pst
+
+import (
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	var signers []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		signers = append(signers, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	contract := common.Address{0x1}
+	dest := common.Address{0x2}
+	envelope, err := NewMultisigEnvelope(contract, dest, big.NewInt(100), big.NewInt(1), 5, signers, 2)
+	require.NoError(t, err)
+
+	data, err := envelope.ToBytes()
+	require.NoError(t, err)
+
+	first, second := &Envelope{}, &Envelope{}
+	require.NoError(t, first.FromBytes(data))
+	require.NoError(t, second.FromBytes(data))
+
+	require.NoError(t, first.Sign(keys[0], 3))
+	require.NoError(t, second.Sign(keys[1], 7))
+
+	_, _, err = first.Finalize()
+	require.Error(t, err)
+
+	outsider, _ := crypto.GenerateKey()
+	require.Error(t, second.Sign(outsider, 1))
+
+	forged := *first.Signatures[0]
+	forged.Nonce = 4
+	require.Error(t, second.AddSignature(&forged))
+
+	data, _ = second.ToBytes()
+	received := &Envelope{}
+	require.NoError(t, received.FromBytes(data))
+	require.NoError(t, first.Merge(received))
+	require.NoError(t, first.Merge(received))
+	require.Len(t, first.Signatures, 2)
+
+	other, _ := NewMultisigEnvelope(contract, dest, big.NewInt(101), big.NewInt(1), 5, signers, 2)
+	require.Error(t, first.Merge(other))
+
+	votes, push, err := first.Finalize()
+	require.NoError(t, err)
+	require.Len(t, votes, 2)
+	for i, vote := range votes {
+		sender, _ := types.Sender(vote)
+		require.Equal(t, signers[i], sender)
+		require.Equal(t, contract, *vote.To)
+		attachment := attachments.ParseCallContractAttachment(vote)
+		require.Equal(t, "send", attachment.Method)
+		require.Equal(t, [][]byte{dest.Bytes(), big.NewInt(100).Bytes()}, attachment.Args)
+	}
+	require.Equal(t, uint32(3), votes[0].AccountNonce)
+	require.Equal(t, uint32(7), votes[1].AccountNonce)
+
+	sender, _ := types.Sender(push)
+	require.Equal(t, signers[1], sender)
+	require.Equal(t, uint32(8), push.AccountNonce)
+	attachment := attachments.ParseCallContractAttachment(push)
+	require.Equal(t, "push", attachment.Method)
+	require.Equal(t, [][]byte{dest.Bytes(), big.NewInt(100).Bytes()}, attachment.Args)
+}
+
+func TestSelectSigners(t *testing.T) {
+	voters, err := ParseVoters(append(common.Address{0x1}.Bytes(), common.Address{0x2}.Bytes()...))
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{{0x1}, {0x2}}, voters)
+
+	_, err = ParseVoters([]byte{0x1})
+	require.Error(t, err)
+
+	signers, err := SelectSigners(voters, nil)
+	require.NoError(t, err)
+	require.Equal(t, voters, signers)
+
+	signers, err = SelectSigners(voters, []common.Address{{0x2}})
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{{0x2}}, signers)
+
+	_, err = SelectSigners(voters, []common.Address{{0x2}, {0x3}})
+	require.Error(t, err)
+}
@@ -51,6 +51,27 @@ func Sender(tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// NewSimulatedTx returns an unsigned copy of the tx which looks signed by the sender. It lets the node simulate txs
+// of accounts whose keys it doesn't have, such txs must never be broadcast.
+func NewSimulatedTx(tx *Transaction, sender common.Address) *Transaction {
+	result := &Transaction{
+		AccountNonce: tx.AccountNonce,
+		Epoch:        tx.Epoch,
+		Amount:       tx.Amount,
+		MaxFee:       tx.MaxFee,
+		Tips:         tx.Tips,
+		Payload:      tx.Payload,
+		To:           tx.To,
+		Type:         tx.Type,
+	}
+	result.setSender(sender)
+	return result
+}
+
+func (tx *Transaction) setSender(sender common.Address) {
+	tx.from.Store(sender)
+}
+
 func IsValidLongSessionAnswers(tx *Transaction) bool {
 	if valid := tx.validLongSessionAnswersProof.Load(); valid != nil {
 		return valid.(bool)
@@ -1449,6 +1449,8 @@ func (i *TransactionIndex) FromBytes(data []byte) error {
 }
 
 type TxEvent struct {
+	// Contract emitted the event, it differs from the receipt contract for events of nested contract calls
+	Contract  common.Address
 	EventName string
 	Data      [][]byte
 }
@@ -1463,6 +1465,8 @@ type TxReceipt struct {
 	Error           error
 	Events          []*TxEvent
 	Method          string
+	// Recipients of coins sent by contracts during the tx, they are used by the indexer only and are not stored
+	Recipients []common.Address
 }
 
 type TxReceipts []*TxReceipt
@@ -1510,10 +1514,15 @@ func (r *TxReceipt) ToProto() *models.ProtoTxReceipts_ProtoTxReceipt {
 	}
 	for idx := range r.Events {
 		e := r.Events[idx]
-		protoObj.Events = append(protoObj.Events, &models.ProtoTxReceipts_ProtoEvent{
+		protoEvent := &models.ProtoTxReceipts_ProtoEvent{
 			Event: e.EventName,
 			Data:  e.Data,
-		})
+		}
+		// the contract is stored only for events of nested calls
+		if e.Contract != r.ContractAddress {
+			protoEvent.Contract = e.Contract.Bytes()
+		}
+		protoObj.Events = append(protoObj.Events, protoEvent)
 	}
 	return protoObj
 }
@@ -1547,10 +1556,15 @@ func (r *TxReceipt) FromProto(protoObj *models.ProtoTxReceipts_ProtoTxReceipt) {
 
 	for idx := range protoObj.Events {
 		e := protoObj.Events[idx]
-		r.Events = append(r.Events, &TxEvent{
+		event := &TxEvent{
+			Contract:  contract,
 			EventName: e.Event,
 			Data:      e.Data,
-		})
+		}
+		if len(e.Contract) > 0 {
+			event.Contract.SetBytes(e.Contract)
+		}
+		r.Events = append(r.Events, event)
 	}
 }
 
@@ -1591,6 +1605,8 @@ type SavedEvent struct {
 	Contract common.Address
 	Event    string
 	Args     [][]byte
+	TxHash   common.Hash
+	Height   uint64
 }
 
 func (i *SavedEvent) ToBytes() ([]byte, error) {
@@ -1598,6 +1614,8 @@ func (i *SavedEvent) ToBytes() ([]byte, error) {
 		Contract: i.Contract.Bytes(),
 		Event:    i.Event,
 		Args:     i.Args,
+		TxHash:   i.TxHash.Bytes(),
+		Height:   i.Height,
 	}
 	return proto.Marshal(protoObj)
 }
@@ -1610,6 +1628,8 @@ func (i *SavedEvent) FromBytes(data []byte) error {
 	i.Contract.SetBytes(protoObj.Contract)
 	i.Event = protoObj.Event
 	i.Args = protoObj.Args
+	i.TxHash.SetBytes(protoObj.TxHash)
+	i.Height = protoObj.Height
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"github.com/idena-network/idena-go/common"
 	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
@@ -72,3 +73,21 @@ func TestBlockCert_Empty(t *testing.T) {
 	var cert *BlockCert
 	require.True(t, cert.Empty())
 }
+
+func TestTxReceipt_EventContract(t *testing.T) {
+	receipt := &TxReceipt{
+		ContractAddress: common.Address{0x1},
+		Events: []*TxEvent{
+			{Contract: common.Address{0x1}, EventName: "a"},
+			{Contract: common.Address{0x2}, EventName: "b"},
+		},
+	}
+	protoObj := receipt.ToProto()
+	require.Empty(t, protoObj.Events[0].Contract)
+	require.Equal(t, common.Address{0x2}.Bytes(), protoObj.Events[1].Contract)
+
+	restored := new(TxReceipt)
+	restored.FromProto(protoObj)
+	require.Equal(t, common.Address{0x1}, restored.Events[0].Contract)
+	require.Equal(t, common.Address{0x2}, restored.Events[1].Contract)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/crypto/vrf/p256"
 	"github.com/idena-network/idena-go/vm/embedded"
+	"github.com/idena-network/idena-go/vm/wasm"
 	"github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
 	"math/big"
@@ -19,6 +20,7 @@ import (
 
 const (
 	MaxPayloadSize           = 3 * 1024
+	MaxWasmPayloadSize       = wasm.MaxCodeSize + MaxPayloadSize
 	GodValidUntilNetworkSize = 10
 )
 
@@ -133,6 +135,13 @@ func checkIfNonNegative(value *big.Int) error {
 	return nil
 }
 
+func maxPayloadSize(tx *types.Transaction) int {
+	if tx.Type == types.DeployContractTx && wasmContractsEnabled() {
+		return MaxWasmPayloadSize
+	}
+	return MaxPayloadSize
+}
+
 func ValidateTx(appState *appstate.AppState, tx *types.Transaction, minFeePerGas *big.Int, txType TxType) error {
 	sender, _ := types.Sender(tx)
 
@@ -140,7 +149,7 @@ func ValidateTx(appState *appstate.AppState, tx *types.Transaction, minFeePerGas
 		return InvalidSignature
 	}
 
-	if len(tx.Payload) > MaxPayloadSize {
+	if len(tx.Payload) > maxPayloadSize(tx) {
 		return InvalidPayload
 	}
 
@@ -704,11 +713,32 @@ func validateDeployContractTx(appState *appstate.AppState, tx *types.Transaction
 		return InvalidPayload
 	}
 	if _, ok := embedded.AvailableContracts[attachment.CodeHash]; !ok {
+		if !wasmContractsEnabled() || !isValidWasmDeployment(attachment) {
+			return InvalidPayload
+		}
+	}
+	if attachment.CodeHash == embedded.PaymentScheduleContract && !paymentSchedulesEnabled() {
 		return InvalidPayload
 	}
 	return nil
 }
 
+func wasmContractsEnabled() bool {
+	return appCfg != nil && appCfg.Consensus.EnableWasmContracts
+}
+
+func paymentSchedulesEnabled() bool {
+	return appCfg != nil && appCfg.Consensus.EnablePaymentSchedules
+}
+
+// isValidWasmDeployment checks that the first arg is the valid module matching the code hash
+func isValidWasmDeployment(attachment *attachments.DeployContractAttachment) bool {
+	if len(attachment.Args) == 0 || crypto.Hash(attachment.Args[0]) != attachment.CodeHash {
+		return false
+	}
+	return wasm.Validate(attachment.Args[0]) == nil
+}
+
 func validateTerminateContractTx(appState *appstate.AppState, tx *types.Transaction, txType TxType) error {
 	if tx.To == nil || *tx.To == (common.Address{}) {
 		return RecipientRequired
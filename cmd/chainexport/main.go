@@ -0,0 +1,199 @@
+package main
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/idena-network/idena-go/log"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"os"
+	"runtime"
+
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/tendermint/tm-db"
+)
+
+var (
+	fromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block height to export",
+		Value: 1,
+	}
+	toFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block height to export (default: head)",
+	}
+	outFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Output directory",
+		Value: "chainexport",
+	}
+	checkpointFlag = cli.Uint64Flag{
+		Name:  "checkpoint",
+		Usage: "Number of blocks between checkpoints",
+		Value: 1000,
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "chainexport"
+	app.Usage = "Export blocks, transactions, receipts and contract events of a stopped node to JSON Lines files"
+
+	app.Flags = []cli.Flag{
+		config.CfgFileFlag,
+		config.DataDirFlag,
+		config.IpfsPortFlag,
+		config.VerbosityFlag,
+		fromFlag,
+		toFlag,
+		outFlag,
+		checkpointFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		logLvl := log.Lvl(context.Int("verbosity"))
+
+		var handler log.Handler
+		if runtime.GOOS == "windows" {
+			handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stdout, log.LogfmtFormat()))
+		} else {
+			handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stderr, log.TerminalFormat(true)))
+		}
+		log.Root().SetHandler(handler)
+
+		cfg, err := config.MakeConfig(context, func(cfg *config.Config) {})
+		if err != nil {
+			return err
+		}
+
+		db, err := OpenDatabase(cfg.DataDir, "idenachain", 16, 16)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		repo := database.NewRepo(db)
+
+		head := repo.ReadHead()
+		if head == nil {
+			return errors.New("head is not found")
+		}
+		from, to := context.Uint64(fromFlag.Name), head.Height()
+		if context.IsSet(toFlag.Name) {
+			to = context.Uint64(toFlag.Name)
+		}
+		if to > head.Height() {
+			return errors.Errorf("last height %v is greater than head height %v", to, head.Height())
+		}
+		if from == 0 || from > to {
+			return errors.Errorf("invalid height range [%v;%v]", from, to)
+		}
+
+		// bodies and receipts are stored in ipfs, missing ones are loaded from the network
+		ipfsProxy, err := ipfs.NewIpfsProxy(cfg.IpfsConf, nil, eventbus.New())
+		if err != nil {
+			return err
+		}
+		return export(repo, ipfsProxy, context.String(outFlag.Name), from, to, context.Uint64(checkpointFlag.Name))
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// export writes blocks of the range [from;to] to dir continuing from the checkpoint of the previous export
+func export(repo *database.Repo, ipfsProxy ipfs.Proxy, dir string, from, to, interval uint64) error {
+	out, cp, err := openOutput(dir)
+	if err != nil {
+		return err
+	}
+	defer out.close()
+	if cp != nil && cp.Height >= from {
+		from = cp.Height + 1
+		log.Info("Resuming export", "height", from)
+	}
+
+	exporter := &exporter{repo: repo, ipfs: ipfsProxy, out: out}
+	for height := from; height <= to; height++ {
+		if err := exporter.exportBlock(height); err != nil {
+			return errors.Wrapf(err, "failed to export block %v", height)
+		}
+		if height == to || interval > 0 && (height-from+1)%interval == 0 {
+			if err := out.checkpoint(height); err != nil {
+				return err
+			}
+			log.Info("Exported blocks", "height", height, "to", to)
+		}
+	}
+	return nil
+}
+
+type exporter struct {
+	repo *database.Repo
+	ipfs ipfs.Proxy
+	out  *output
+}
+
+func (e *exporter) exportBlock(height uint64) error {
+	hash := e.repo.ReadCanonicalHash(height)
+	if hash == (common.Hash{}) {
+		return errors.New("canonical hash is not found")
+	}
+	header := e.repo.ReadBlockHeader(hash)
+	if header == nil {
+		return errors.New("header is not found")
+	}
+	block := &types.Block{Header: header, Body: &types.Body{}}
+	var receipts types.TxReceipts
+	if header.ProposedHeader != nil {
+		data, err := e.ipfs.Get(header.ProposedHeader.IpfsHash, ipfs.Block)
+		if err != nil {
+			return errors.Wrap(err, "failed to load block body")
+		}
+		block.Body.FromBytes(data)
+		if len(header.ProposedHeader.TxReceiptsCid) > 0 {
+			data, err := e.ipfs.Get(header.ProposedHeader.TxReceiptsCid, ipfs.TxReceipt)
+			if err != nil {
+				return errors.Wrap(err, "failed to load receipts")
+			}
+			receipts = receipts.FromBytes(data)
+		}
+	}
+
+	if err := e.out.write(blocksFile, convertBlock(block)); err != nil {
+		return err
+	}
+	for i, tx := range block.Body.Transactions {
+		if err := e.out.write(transactionsFile, convertTransaction(block, i, tx)); err != nil {
+			return err
+		}
+	}
+	for _, receipt := range receipts {
+		if err := e.out.write(receiptsFile, convertReceipt(block, receipt)); err != nil {
+			return err
+		}
+		for i, event := range receipt.Events {
+			if err := e.out.write(eventsFile, convertEvent(block, receipt, i, event)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func OpenDatabase(datadir string, name string, cache int, handles int) (db.DB, error) {
+	return db.NewGoLevelDBWithOpts(name, datadir, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 2 * opt.MiB,
+		WriteBuffer:            cache / 4 * opt.MiB,
+		Filter:                 filter.NewBloomFilter(10),
+	})
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readLines(t *testing.T, path string, record func() interface{}) []interface{} {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var result []interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		r := record()
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), r))
+		result = append(result, r)
+	}
+	require.NoError(t, scanner.Err())
+	return result
+}
+
+func TestExport_Resume(t *testing.T) {
+	repo := database.NewRepo(db.NewMemDB())
+	ipfsProxy := ipfs.NewMemoryIpfsProxy()
+	key, _ := crypto.GenerateKey()
+
+	const blocks = 5
+	bodies := make(map[uint64][]byte)
+	for height := uint64(1); height <= blocks; height++ {
+		payload, _ := attachments.CreateCallContractAttachment("send", []byte{byte(height)}).ToBytes()
+		tx, _ := types.SignTx(&types.Transaction{
+			Type:         types.CallContractTx,
+			AccountNonce: uint32(height),
+			To:           &common.Address{0x1},
+			Payload:      payload,
+		}, key)
+		body := &types.Body{Transactions: []*types.Transaction{tx}}
+		bodies[height] = body.ToBytes()
+		c, _ := ipfsProxy.Cid(bodies[height])
+		header := &types.Header{ProposedHeader: &types.ProposedHeader{
+			Height:    height,
+			IpfsHash:  c.Bytes(),
+			FeePerGas: big.NewInt(1),
+		}}
+		repo.WriteBlockHeader(header)
+		repo.WriteCanonicalHash(height, header.Hash())
+		// the body of the 4th block is not available during the first export
+		if height != 4 {
+			ipfsProxy.Add(bodies[height], false)
+		}
+	}
+
+	dir := t.TempDir()
+	require.Error(t, export(repo, ipfsProxy, dir, 1, blocks, 2))
+
+	cp, err := readCheckpoint(filepath.Join(dir, checkpointFile))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), cp.Height)
+
+	ipfsProxy.Add(bodies[4], false)
+	require.NoError(t, export(repo, ipfsProxy, dir, 1, blocks, 2))
+
+	exportedBlocks := readLines(t, filepath.Join(dir, blocksFile), func() interface{} { return &Block{} })
+	require.Len(t, exportedBlocks, blocks)
+	txs := readLines(t, filepath.Join(dir, transactionsFile), func() interface{} { return &Transaction{} })
+	require.Len(t, txs, blocks)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	for i := 0; i < blocks; i++ {
+		height := uint64(i + 1)
+		require.Equal(t, height, exportedBlocks[i].(*Block).Height)
+
+		tx := txs[i].(*Transaction)
+		require.Equal(t, height, tx.BlockHeight)
+		require.Equal(t, sender, tx.From)
+		require.Equal(t, "callContract", tx.Type)
+		require.NotNil(t, tx.Attachment)
+		require.NotNil(t, tx.Attachment.CallContract)
+		require.Nil(t, tx.Attachment.DeployContract)
+		require.Equal(t, "send", tx.Attachment.CallContract.Method)
+		require.Equal(t, []byte{byte(height)}, []byte(tx.Attachment.CallContract.Args[0]))
+	}
+
+	cp, err = readCheckpoint(filepath.Join(dir, checkpointFile))
+	require.NoError(t, err)
+	require.Equal(t, uint64(blocks), cp.Height)
+}
+
+func TestDecodeAttachment_Schema(t *testing.T) {
+	payload := attachments.CreateOnlineStatusAttachment(true)
+	attachment := decodeAttachment(&types.Transaction{Type: types.OnlineStatusTx, Payload: payload})
+	require.NotNil(t, attachment)
+	require.True(t, attachment.Online.Online)
+
+	data, err := json.Marshal(attachment)
+	require.NoError(t, err)
+	fields := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(data, &fields))
+	// every attachment type has its own column which is null for other transaction types
+	require.Len(t, fields, 11)
+	require.Nil(t, fields["callContract"])
+
+	require.Nil(t, decodeAttachment(&types.Transaction{Type: types.SendTx, Payload: []byte{0x1}}))
+}
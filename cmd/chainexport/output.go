@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	blocksFile       = "blocks.jsonl"
+	transactionsFile = "transactions.jsonl"
+	receiptsFile     = "receipts.jsonl"
+	eventsFile       = "events.jsonl"
+	checkpointFile   = "checkpoint.json"
+)
+
+// checkpoint is the last completely exported block and sizes of the files right after it was written.
+// Records written after the checkpoint are dropped on resume, so the output never contains duplicates.
+type checkpoint struct {
+	Height  uint64           `json:"height"`
+	Offsets map[string]int64 `json:"offsets"`
+}
+
+type jsonlFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	offset int64
+}
+
+func (f *jsonlFile) write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := f.writer.Write(data); err != nil {
+		return err
+	}
+	f.offset += int64(len(data))
+	return nil
+}
+
+type output struct {
+	dir   string
+	files map[string]*jsonlFile
+}
+
+// openOutput opens the export files in dir and returns the checkpoint to resume from, it is nil for a new export
+func openOutput(dir string) (*output, *checkpoint, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+	cp, err := readCheckpoint(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	o := &output{dir: dir, files: make(map[string]*jsonlFile)}
+	for _, name := range []string{blocksFile, transactionsFile, receiptsFile, eventsFile} {
+		file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			o.close()
+			return nil, nil, err
+		}
+		var offset int64
+		if cp != nil {
+			offset = cp.Offsets[name]
+		}
+		if err := file.Truncate(offset); err != nil {
+			file.Close()
+			o.close()
+			return nil, nil, err
+		}
+		if _, err := file.Seek(offset, 0); err != nil {
+			file.Close()
+			o.close()
+			return nil, nil, err
+		}
+		o.files[name] = &jsonlFile{file: file, writer: bufio.NewWriter(file), offset: offset}
+	}
+	return o, cp, nil
+}
+
+func (o *output) write(name string, record interface{}) error {
+	return o.files[name].write(record)
+}
+
+// checkpoint flushes the files and saves the checkpoint at the given height
+func (o *output) checkpoint(height uint64) error {
+	cp := &checkpoint{Height: height, Offsets: make(map[string]int64)}
+	for name, f := range o.files {
+		if err := f.writer.Flush(); err != nil {
+			return err
+		}
+		if err := f.file.Sync(); err != nil {
+			return err
+		}
+		cp.Offsets[name] = f.offset
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(o.dir, checkpointFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (o *output) close() {
+	for _, f := range o.files {
+		f.writer.Flush()
+		f.file.Close()
+	}
+}
+
+func readCheckpoint(path string) (*checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint file")
+	}
+	return cp, nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/fee"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/ipfs/go-cid"
+	"github.com/shopspring/decimal"
+)
+
+// Records have flat structure and don't omit empty fields, so each file has a stable schema
+// and can be loaded by columnar tools as is.
+
+var (
+	txTypeNames = map[types.TxType]string{
+		types.SendTx:               "send",
+		types.ActivationTx:         "activation",
+		types.InviteTx:             "invite",
+		types.KillTx:               "kill",
+		types.KillInviteeTx:        "killInvitee",
+		types.SubmitFlipTx:         "submitFlip",
+		types.SubmitAnswersHashTx:  "submitAnswersHash",
+		types.SubmitShortAnswersTx: "submitShortAnswers",
+		types.SubmitLongAnswersTx:  "submitLongAnswers",
+		types.EvidenceTx:           "evidence",
+		types.OnlineStatusTx:       "online",
+		types.ChangeGodAddressTx:   "changeGodAddress",
+		types.BurnTx:               "burn",
+		types.ChangeProfileTx:      "changeProfile",
+		types.DeleteFlipTx:         "deleteFlip",
+		types.DeployContractTx:     "deployContract",
+		types.CallContractTx:       "callContract",
+		types.TerminateContractTx:  "terminateContract",
+		types.DelegateTx:           "delegate",
+		types.UndelegateTx:         "undelegate",
+		types.KillDelegatorTx:      "killDelegator",
+		types.StoreToIpfsTx:        "storeToIpfs",
+	}
+
+	blockFlagNames = []struct {
+		flag types.BlockFlag
+		name string
+	}{
+		{types.IdentityUpdate, "IdentityUpdate"},
+		{types.FlipLotteryStarted, "FlipLotteryStarted"},
+		{types.ShortSessionStarted, "ShortSessionStarted"},
+		{types.LongSessionStarted, "LongSessionStarted"},
+		{types.AfterLongSessionStarted, "AfterLongSessionStarted"},
+		{types.ValidationFinished, "ValidationFinished"},
+		{types.OfflinePropose, "OfflinePropose"},
+		{types.OfflineCommit, "OfflineCommit"},
+		{types.Snapshot, "Snapshot"},
+	}
+)
+
+type Block struct {
+	Height       uint64          `json:"height"`
+	Hash         common.Hash     `json:"hash"`
+	ParentHash   common.Hash     `json:"parentHash"`
+	Timestamp    int64           `json:"timestamp"`
+	Coinbase     common.Address  `json:"coinbase"`
+	IsEmpty      bool            `json:"isEmpty"`
+	Root         common.Hash     `json:"root"`
+	IdentityRoot common.Hash     `json:"identityRoot"`
+	IpfsCid      string          `json:"ipfsCid"`
+	FeePerGas    decimal.Decimal `json:"feePerGas"`
+	Flags        []string        `json:"flags"`
+	OfflineAddr  *common.Address `json:"offlineAddress"`
+	TxCount      int             `json:"txCount"`
+}
+
+type Transaction struct {
+	Hash        common.Hash     `json:"hash"`
+	BlockHeight uint64          `json:"blockHeight"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Index       int             `json:"index"`
+	Timestamp   int64           `json:"timestamp"`
+	Type        string          `json:"type"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Amount      decimal.Decimal `json:"amount"`
+	Tips        decimal.Decimal `json:"tips"`
+	MaxFee      decimal.Decimal `json:"maxFee"`
+	UsedFee     decimal.Decimal `json:"usedFee"`
+	Nonce       uint32          `json:"nonce"`
+	Epoch       uint16          `json:"epoch"`
+	Payload     hexutil.Bytes   `json:"payload"`
+	Attachment  *Attachment     `json:"attachment"`
+}
+
+type Receipt struct {
+	TxHash      common.Hash     `json:"txHash"`
+	BlockHeight uint64          `json:"blockHeight"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	From        common.Address  `json:"from"`
+	Contract    common.Address  `json:"contract"`
+	Method      string          `json:"method"`
+	Success     bool            `json:"success"`
+	Error       string          `json:"error"`
+	GasUsed     uint64          `json:"gasUsed"`
+	GasCost     decimal.Decimal `json:"gasCost"`
+	EventCount  int             `json:"eventCount"`
+}
+
+type Event struct {
+	TxHash      common.Hash     `json:"txHash"`
+	BlockHeight uint64          `json:"blockHeight"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Contract    common.Address  `json:"contract"`
+	Index       int             `json:"index"`
+	Event       string          `json:"event"`
+	Args        []hexutil.Bytes `json:"args"`
+}
+
+// Attachment is the decoded payload of the transaction, only the field of the transaction type is set
+// and the others are null, so all the rows have the same columns.
+type Attachment struct {
+	SubmitFlip         *FlipAttachment         `json:"submitFlip"`
+	DeleteFlip         *CidAttachment          `json:"deleteFlip"`
+	ChangeProfile      *CidAttachment          `json:"changeProfile"`
+	StoreToIpfs        *StoreToIpfsAttachment  `json:"storeToIpfs"`
+	Online             *OnlineAttachment       `json:"online"`
+	Burn               *BurnAttachment         `json:"burn"`
+	SubmitShortAnswers *ShortAnswersAttachment `json:"submitShortAnswers"`
+	SubmitLongAnswers  *LongAnswersAttachment  `json:"submitLongAnswers"`
+	DeployContract     *DeployAttachment       `json:"deployContract"`
+	CallContract       *CallAttachment         `json:"callContract"`
+	TerminateContract  *TerminateAttachment    `json:"terminateContract"`
+}
+
+type FlipAttachment struct {
+	Cid  string `json:"cid"`
+	Pair uint8  `json:"pair"`
+}
+
+type CidAttachment struct {
+	Cid string `json:"cid"`
+}
+
+type StoreToIpfsAttachment struct {
+	Cid  string `json:"cid"`
+	Size uint32 `json:"size"`
+}
+
+type OnlineAttachment struct {
+	Online bool `json:"online"`
+}
+
+type BurnAttachment struct {
+	Key string `json:"key"`
+}
+
+type ShortAnswersAttachment struct {
+	Answers    hexutil.Bytes `json:"answers"`
+	Rnd        uint64        `json:"rnd"`
+	ClientType byte          `json:"clientType"`
+}
+
+type LongAnswersAttachment struct {
+	Answers hexutil.Bytes `json:"answers"`
+	Proof   hexutil.Bytes `json:"proof"`
+	Key     hexutil.Bytes `json:"key"`
+	Salt    hexutil.Bytes `json:"salt"`
+}
+
+type DeployAttachment struct {
+	CodeHash common.Hash     `json:"codeHash"`
+	Args     []hexutil.Bytes `json:"args"`
+}
+
+type CallAttachment struct {
+	Method string          `json:"method"`
+	Args   []hexutil.Bytes `json:"args"`
+}
+
+type TerminateAttachment struct {
+	Args []hexutil.Bytes `json:"args"`
+}
+
+func convertBlock(block *types.Block) *Block {
+	result := &Block{
+		Height:       block.Height(),
+		Hash:         block.Hash(),
+		ParentHash:   block.Header.ParentHash(),
+		Timestamp:    block.Header.Time(),
+		IsEmpty:      block.IsEmpty(),
+		Root:         block.Root(),
+		IdentityRoot: block.IdentityRoot(),
+		IpfsCid:      formatCid(block.Header.IpfsHash()),
+		FeePerGas:    blockchain.ConvertToFloat(block.Header.FeePerGas()),
+		Flags:        []string{},
+		OfflineAddr:  block.Header.OfflineAddr(),
+		TxCount:      len(block.Body.Transactions),
+	}
+	if !block.IsEmpty() {
+		result.Coinbase = block.Header.Coinbase()
+	}
+	for _, f := range blockFlagNames {
+		if block.Header.Flags().HasFlag(f.flag) {
+			result.Flags = append(result.Flags, f.name)
+		}
+	}
+	return result
+}
+
+func convertTransaction(block *types.Block, index int, tx *types.Transaction) *Transaction {
+	sender, _ := types.Sender(tx)
+	return &Transaction{
+		Hash:        tx.Hash(),
+		BlockHeight: block.Height(),
+		BlockHash:   block.Hash(),
+		Index:       index,
+		Timestamp:   block.Header.Time(),
+		Type:        txTypeNames[tx.Type],
+		From:        sender,
+		To:          tx.To,
+		Amount:      blockchain.ConvertToFloat(tx.Amount),
+		Tips:        blockchain.ConvertToFloat(tx.Tips),
+		MaxFee:      blockchain.ConvertToFloat(tx.MaxFee),
+		UsedFee:     blockchain.ConvertToFloat(fee.CalculateFee(1, block.Header.FeePerGas(), tx)),
+		Nonce:       tx.AccountNonce,
+		Epoch:       tx.Epoch,
+		Payload:     tx.Payload,
+		Attachment:  decodeAttachment(tx),
+	}
+}
+
+func convertReceipt(block *types.Block, receipt *types.TxReceipt) *Receipt {
+	var err string
+	if receipt.Error != nil {
+		err = receipt.Error.Error()
+	}
+	return &Receipt{
+		TxHash:      receipt.TxHash,
+		BlockHeight: block.Height(),
+		BlockHash:   block.Hash(),
+		From:        receipt.From,
+		Contract:    receipt.ContractAddress,
+		Method:      receipt.Method,
+		Success:     receipt.Success,
+		Error:       err,
+		GasUsed:     receipt.GasUsed,
+		GasCost:     blockchain.ConvertToFloat(receipt.GasCost),
+		EventCount:  len(receipt.Events),
+	}
+}
+
+func convertEvent(block *types.Block, receipt *types.TxReceipt, index int, event *types.TxEvent) *Event {
+	return &Event{
+		TxHash:      receipt.TxHash,
+		BlockHeight: block.Height(),
+		BlockHash:   block.Hash(),
+		Contract:    event.Contract,
+		Index:       index,
+		Event:       event.EventName,
+		Args:        convertArgs(event.Data),
+	}
+}
+
+// decodeAttachment returns the parsed payload of the transaction or nil if the type has no known attachment
+func decodeAttachment(tx *types.Transaction) *Attachment {
+	if len(tx.Payload) == 0 {
+		return nil
+	}
+	result := &Attachment{}
+	switch tx.Type {
+	case types.SubmitFlipTx:
+		if a := attachments.ParseFlipSubmitAttachment(tx); a != nil {
+			result.SubmitFlip = &FlipAttachment{Cid: formatCid(a.Cid), Pair: a.Pair}
+			return result
+		}
+	case types.DeleteFlipTx:
+		if a := attachments.ParseDeleteFlipAttachment(tx); a != nil {
+			result.DeleteFlip = &CidAttachment{Cid: formatCid(a.Cid)}
+			return result
+		}
+	case types.ChangeProfileTx:
+		if a := attachments.ParseChangeProfileAttachment(tx); a != nil {
+			result.ChangeProfile = &CidAttachment{Cid: formatCid(a.Hash)}
+			return result
+		}
+	case types.StoreToIpfsTx:
+		if a := attachments.ParseStoreToIpfsAttachment(tx); a != nil {
+			result.StoreToIpfs = &StoreToIpfsAttachment{Cid: formatCid(a.Cid), Size: a.Size}
+			return result
+		}
+	case types.OnlineStatusTx:
+		if a := attachments.ParseOnlineStatusAttachment(tx); a != nil {
+			result.Online = &OnlineAttachment{Online: a.Online}
+			return result
+		}
+	case types.BurnTx:
+		if a := attachments.ParseBurnAttachment(tx); a != nil {
+			result.Burn = &BurnAttachment{Key: a.Key}
+			return result
+		}
+	case types.SubmitShortAnswersTx:
+		if a := attachments.ParseShortAnswerAttachment(tx); a != nil {
+			result.SubmitShortAnswers = &ShortAnswersAttachment{Answers: a.Answers, Rnd: a.Rnd, ClientType: a.ClientType}
+			return result
+		}
+	case types.SubmitLongAnswersTx:
+		if a := attachments.ParseLongAnswerAttachment(tx); a != nil {
+			result.SubmitLongAnswers = &LongAnswersAttachment{Answers: a.Answers, Proof: a.Proof, Key: a.Key, Salt: a.Salt}
+			return result
+		}
+	case types.DeployContractTx:
+		if a := attachments.ParseDeployContractAttachment(tx); a != nil {
+			result.DeployContract = &DeployAttachment{CodeHash: a.CodeHash, Args: convertArgs(a.Args)}
+			return result
+		}
+	case types.CallContractTx:
+		if a := attachments.ParseCallContractAttachment(tx); a != nil {
+			result.CallContract = &CallAttachment{Method: a.Method, Args: convertArgs(a.Args)}
+			return result
+		}
+	case types.TerminateContractTx:
+		if a := attachments.ParseTerminateContractAttachment(tx); a != nil {
+			result.TerminateContract = &TerminateAttachment{Args: convertArgs(a.Args)}
+			return result
+		}
+	}
+	return nil
+}
+
+func convertArgs(args [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, 0, len(args))
+	for _, arg := range args {
+		result = append(result, arg)
+	}
+	return result
+}
+
+func formatCid(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	c, err := cid.Cast(data)
+	if err != nil {
+		return hexutil.Encode(data)
+	}
+	return c.String()
+}
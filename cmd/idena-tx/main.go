@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/common/math"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/crypto/ecies"
+	"github.com/idena-network/idena-go/crypto/sha3"
+	"github.com/idena-network/idena-go/keystore"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// clientTypeDesktop is the client type of short answers submitted by the desktop app with a remote node
+const clientTypeDesktop = 1
+
+var (
+	txTypes = map[string]types.TxType{
+		"send":               types.SendTx,
+		"activation":         types.ActivationTx,
+		"invite":             types.InviteTx,
+		"kill":               types.KillTx,
+		"killInvitee":        types.KillInviteeTx,
+		"submitFlip":         types.SubmitFlipTx,
+		"submitAnswersHash":  types.SubmitAnswersHashTx,
+		"submitShortAnswers": types.SubmitShortAnswersTx,
+		"submitLongAnswers":  types.SubmitLongAnswersTx,
+		"evidence":           types.EvidenceTx,
+		"online":             types.OnlineStatusTx,
+		"changeGodAddress":   types.ChangeGodAddressTx,
+		"burn":               types.BurnTx,
+		"changeProfile":      types.ChangeProfileTx,
+		"deleteFlip":         types.DeleteFlipTx,
+		"deployContract":     types.DeployContractTx,
+		"callContract":       types.CallContractTx,
+		"terminateContract":  types.TerminateContractTx,
+		"delegate":           types.DelegateTx,
+		"undelegate":         types.UndelegateTx,
+		"killDelegator":      types.KillDelegatorTx,
+		"storeToIpfs":        types.StoreToIpfsTx,
+	}
+)
+
+var (
+	typeFlag = cli.StringFlag{
+		Name:  "type",
+		Usage: "Transaction type (send, burn, online, callContract, ...)",
+	}
+	toFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Recipient or contract address",
+	}
+	amountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "Amount in iDNA",
+		Value: "0",
+	}
+	maxFeeFlag = cli.StringFlag{
+		Name:  "maxfee",
+		Usage: "Max fee in iDNA, it can't be estimated offline",
+	}
+	tipsFlag = cli.StringFlag{
+		Name:  "tips",
+		Usage: "Tips in iDNA",
+		Value: "0",
+	}
+	nonceFlag = cli.UintFlag{
+		Name:  "nonce",
+		Usage: "Account nonce, should be the current one plus 1",
+	}
+	epochFlag = cli.UintFlag{
+		Name:  "epoch",
+		Usage: "Current epoch",
+	}
+	payloadFlag = cli.StringFlag{
+		Name:  "payload",
+		Usage: "Raw hex payload for transaction types without a supported attachment",
+	}
+	methodFlag = cli.StringFlag{
+		Name:  "method",
+		Usage: "Contract method for callContract",
+	}
+	codeHashFlag = cli.StringFlag{
+		Name:  "codehash",
+		Usage: "Contract code hash for deployContract",
+	}
+	argFlag = cli.StringSliceFlag{
+		Name:  "arg",
+		Usage: "Contract argument in format:value form (e.g. uint64:5, hex:0x01, dna:1.5), can be repeated",
+	}
+	cidFlag = cli.StringFlag{
+		Name:  "cid",
+		Usage: "Content id for submitFlip, deleteFlip, changeProfile and storeToIpfs",
+	}
+	pairFlag = cli.UintFlag{
+		Name:  "pair",
+		Usage: "Flip words pair for submitFlip",
+	}
+	sizeFlag = cli.UintFlag{
+		Name:  "size",
+		Usage: "Data size for storeToIpfs",
+	}
+	onlineFlag = cli.BoolFlag{
+		Name:  "online",
+		Usage: "Switch to online for online transaction, switch to offline if not set",
+	}
+	answersFlag = cli.StringFlag{
+		Name:  "answers",
+		Usage: "Hex encoded answers bits for submitAnswersHash, submitShortAnswers and submitLongAnswers",
+	}
+	rndFlag = cli.Uint64Flag{
+		Name:  "rnd",
+		Usage: "Flip words random value for submitShortAnswers",
+	}
+	clientTypeFlag = cli.UintFlag{
+		Name:  "clienttype",
+		Usage: "Client type for submitShortAnswers",
+		Value: clientTypeDesktop,
+	}
+	proofFlag = cli.StringFlag{
+		Name:  "proof",
+		Usage: "Hex encoded flip words proof for submitLongAnswers",
+	}
+	candidatesFlag = cli.UintFlag{
+		Name:  "candidates",
+		Usage: "Number of validation candidates for evidence",
+	}
+	approveFlag = cli.IntSliceFlag{
+		Name:  "approve",
+		Usage: "Index of an approved candidate for evidence, can be repeated",
+	}
+	burnKeyFlag = cli.StringFlag{
+		Name:  "burnkey",
+		Usage: "Key for burn transaction",
+	}
+	keyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Keystore file to sign the transaction with",
+	}
+	keyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "Encrypted private key exported by dna_exportKey to sign the transaction with",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "Password of the key, it is read from stdin if not set",
+	}
+)
+
+func main() {
+	if err := newApp().Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newApp() *cli.App {
+	app := cli.NewApp()
+	app.Name = "idena-tx"
+	app.Usage = "Build and sign transactions offline, the output can be sent with bcn_sendRawTx"
+
+	app.Flags = []cli.Flag{
+		typeFlag,
+		toFlag,
+		amountFlag,
+		maxFeeFlag,
+		tipsFlag,
+		nonceFlag,
+		epochFlag,
+		payloadFlag,
+		methodFlag,
+		codeHashFlag,
+		argFlag,
+		cidFlag,
+		pairFlag,
+		sizeFlag,
+		onlineFlag,
+		answersFlag,
+		rndFlag,
+		clientTypeFlag,
+		proofFlag,
+		candidatesFlag,
+		approveFlag,
+		burnKeyFlag,
+		keyFileFlag,
+		keyFlag,
+		passwordFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		key, err := loadKey(context)
+		if err != nil {
+			return err
+		}
+		tx, err := buildTx(context, key)
+		if err != nil {
+			return err
+		}
+		signedTx, err := types.SignTx(tx, key)
+		if err != nil {
+			return err
+		}
+		data, err := signedTx.ToBytes()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(context.App.ErrWriter, "from: %v\nhash: %v\n", crypto.PubkeyToAddress(key.PublicKey).Hex(), signedTx.Hash().Hex())
+		fmt.Fprintln(context.App.Writer, hexutil.Encode(data))
+		return nil
+	}
+	return app
+}
+
+func buildTx(context *cli.Context, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	txType, ok := txTypes[context.String(typeFlag.Name)]
+	if !ok {
+		return nil, errors.Errorf("unknown transaction type: \"%v\"", context.String(typeFlag.Name))
+	}
+	if !context.IsSet(nonceFlag.Name) || !context.IsSet(epochFlag.Name) {
+		return nil, errors.New("nonce and epoch are required")
+	}
+	if !context.IsSet(maxFeeFlag.Name) {
+		return nil, errors.New("maxfee is required")
+	}
+
+	tx := &types.Transaction{
+		Type:         txType,
+		AccountNonce: uint32(context.Uint(nonceFlag.Name)),
+		Epoch:        uint16(context.Uint(epochFlag.Name)),
+	}
+	if context.IsSet(toFlag.Name) {
+		to, err := parseAddress(context.String(toFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		tx.To = &to
+	}
+	switch txType {
+	case types.SendTx, types.KillInviteeTx, types.DelegateTx, types.KillDelegatorTx, types.CallContractTx, types.TerminateContractTx:
+		if tx.To == nil {
+			return nil, errors.New("to is required")
+		}
+	}
+	var err error
+	if tx.Amount, err = parseCoins(context.String(amountFlag.Name)); err != nil {
+		return nil, errors.Wrap(err, "invalid amount")
+	}
+	if tx.MaxFee, err = parseCoins(context.String(maxFeeFlag.Name)); err != nil {
+		return nil, errors.Wrap(err, "invalid maxfee")
+	}
+	if tx.Tips, err = parseCoins(context.String(tipsFlag.Name)); err != nil {
+		return nil, errors.Wrap(err, "invalid tips")
+	}
+	if tx.Payload, err = buildPayload(context, txType, tx.Epoch, key); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func buildPayload(context *cli.Context, txType types.TxType, epoch uint16, key *ecdsa.PrivateKey) ([]byte, error) {
+	if context.IsSet(payloadFlag.Name) {
+		return hexutil.Decode(context.String(payloadFlag.Name))
+	}
+	switch txType {
+	case types.BurnTx:
+		return attachments.CreateBurnAttachment(context.String(burnKeyFlag.Name)), nil
+	case types.OnlineStatusTx:
+		return attachments.CreateOnlineStatusAttachment(context.Bool(onlineFlag.Name)), nil
+	case types.SubmitFlipTx, types.DeleteFlipTx, types.ChangeProfileTx, types.StoreToIpfsTx:
+		c, err := cid.Decode(context.String(cidFlag.Name))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid cid")
+		}
+		switch txType {
+		case types.SubmitFlipTx:
+			return attachments.CreateFlipSubmitAttachment(c.Bytes(), uint8(context.Uint(pairFlag.Name))), nil
+		case types.DeleteFlipTx:
+			return attachments.CreateDeleteFlipAttachment(c.Bytes()), nil
+		case types.ChangeProfileTx:
+			return attachments.CreateChangeProfileAttachment(c.Bytes()), nil
+		default:
+			return attachments.CreateStoreToIpfsAttachment(c.Bytes(), uint32(context.Uint(sizeFlag.Name))), nil
+		}
+	case types.SubmitAnswersHashTx, types.SubmitShortAnswersTx, types.SubmitLongAnswersTx:
+		answers, err := hexutil.Decode(context.String(answersFlag.Name))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid answers")
+		}
+		switch txType {
+		case types.SubmitAnswersHashTx:
+			salt, err := shortAnswersSalt(epoch, key)
+			if err != nil {
+				return nil, err
+			}
+			hash := crypto.Hash(append(answers, salt...))
+			return hash[:], nil
+		case types.SubmitShortAnswersTx:
+			return attachments.CreateShortAnswerAttachment(answers, context.Uint64(rndFlag.Name), byte(context.Uint(clientTypeFlag.Name))), nil
+		default:
+			proof, err := hexutil.Decode(context.String(proofFlag.Name))
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid proof")
+			}
+			salt, err := shortAnswersSalt(epoch, key)
+			if err != nil {
+				return nil, err
+			}
+			flipKey, err := flipEncryptionKey(epoch, key)
+			if err != nil {
+				return nil, err
+			}
+			return attachments.CreateLongAnswerAttachment(answers, proof, salt, flipKey), nil
+		}
+	case types.EvidenceTx:
+		if !context.IsSet(candidatesFlag.Name) {
+			return nil, errors.New("candidates is required")
+		}
+		candidates := uint32(context.Uint(candidatesFlag.Name))
+		bitmap := common.NewBitmap(candidates)
+		for _, idx := range context.IntSlice(approveFlag.Name) {
+			if idx < 0 || uint32(idx) >= candidates {
+				return nil, errors.Errorf("invalid candidate index %v", idx)
+			}
+			bitmap.Add(uint32(idx))
+		}
+		buf := new(bytes.Buffer)
+		bitmap.WriteTo(buf)
+		return buf.Bytes(), nil
+	case types.DeployContractTx, types.CallContractTx, types.TerminateContractTx:
+		args, err := parseContractArgs(context.StringSlice(argFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		switch txType {
+		case types.DeployContractTx:
+			codeHash, err := hexutil.Decode(context.String(codeHashFlag.Name))
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid code hash")
+			}
+			return attachments.CreateDeployContractAttachment(common.BytesToHash(codeHash), args...).ToBytes()
+		case types.CallContractTx:
+			if context.String(methodFlag.Name) == "" {
+				return nil, errors.New("method is required")
+			}
+			return attachments.CreateCallContractAttachment(context.String(methodFlag.Name), args...).ToBytes()
+		default:
+			return attachments.CreateTerminateContractAttachment(args...).ToBytes()
+		}
+	default:
+		return nil, nil
+	}
+}
+
+// shortAnswersSalt repeats the salt of the validation ceremony, it is derived from the key and the epoch
+func shortAnswersSalt(epoch uint16, key *ecdsa.PrivateKey) ([]byte, error) {
+	hash := crypto.Hash([]byte(fmt.Sprintf("short-answers-salt-%v", epoch)))
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return nil, err
+	}
+	sha := sha3.Sum256(sig)
+	return sha[:], nil
+}
+
+// flipEncryptionKey repeats the public flip encryption key of the epoch which is published with long answers
+func flipEncryptionKey(epoch uint16, key *ecdsa.PrivateKey) (*ecies.PrivateKey, error) {
+	hash := crypto.Hash([]byte(fmt.Sprintf("flip-key-for-epoch-%v", epoch)))
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return nil, err
+	}
+	flipKey, err := crypto.GenerateKeyFromSeed(bytes.NewReader(sig))
+	if err != nil {
+		return nil, err
+	}
+	return ecies.ImportECDSA(flipKey), nil
+}
+
+func parseContractArgs(values []string) ([][]byte, error) {
+	var args [][]byte
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid argument \"%v\", format:value is expected", value)
+		}
+		arg, err := abi.Encode(abi.ArgType(parts[0]), parts[1])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func parseAddress(value string) (common.Address, error) {
+	data, err := hexutil.Decode(value)
+	if err != nil || len(data) != common.AddressLength {
+		return common.Address{}, errors.Errorf("invalid address: \"%v\"", value)
+	}
+	return common.BytesToAddress(data), nil
+}
+
+func parseCoins(value string) (*big.Int, error) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, err
+	}
+	return math.ToInt(amount.Mul(decimal.NewFromBigInt(common.DnaBase, 0))), nil
+}
+
+func loadKey(context *cli.Context) (*ecdsa.PrivateKey, error) {
+	if context.IsSet(keyFileFlag.Name) == context.IsSet(keyFlag.Name) {
+		return nil, errors.New("either keyfile or key should be specified")
+	}
+	password, err := readPassword(context)
+	if err != nil {
+		return nil, err
+	}
+	if context.IsSet(keyFileFlag.Name) {
+		data, err := ioutil.ReadFile(context.String(keyFileFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(data, password)
+		if err != nil {
+			return nil, err
+		}
+		return key.PrivateKey, nil
+	}
+	encrypted, err := hex.DecodeString(context.String(keyFlag.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding key")
+	}
+	decrypted, err := crypto.Decrypt(encrypted, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decrypting key")
+	}
+	return crypto.ToECDSA(decrypted)
+}
+
+func readPassword(context *cli.Context) (string, error) {
+	if context.IsSet(passwordFlag.Name) {
+		return context.String(passwordFlag.Name), nil
+	}
+	fmt.Fprint(context.App.ErrWriter, "Password: ")
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(context.App.ErrWriter)
+		return string(password), err
+	}
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return "", err
+	}
+	return strings.TrimRight(password, "\r\n"), nil
+}
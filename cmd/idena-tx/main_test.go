@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testPassword = "pwd"
+
+func runApp(t *testing.T, args ...string) (*types.Transaction, common.Address, error) {
+	key, _ := crypto.GenerateKey()
+	encrypted, err := crypto.Encrypt(crypto.FromECDSA(key), testPassword)
+	require.NoError(t, err)
+
+	app := newApp()
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	app.Writer, app.ErrWriter = out, errOut
+	args = append([]string{"idena-tx", "--key", hex.EncodeToString(encrypted), "--password", testPassword}, args...)
+	if err := app.Run(args); err != nil {
+		return nil, common.Address{}, err
+	}
+	data, err := hexutil.Decode(strings.TrimSpace(out.String()))
+	require.NoError(t, err)
+	tx := new(types.Transaction)
+	require.NoError(t, tx.FromBytes(data))
+	return tx, crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+func TestApp_Send(t *testing.T) {
+	to := common.Address{0x1}
+	tx, sender, err := runApp(t, "--type", "send", "--to", to.Hex(), "--amount", "1.5", "--maxfee", "0.1", "--nonce", "3", "--epoch", "7")
+	require.NoError(t, err)
+
+	require.Equal(t, types.SendTx, tx.Type)
+	require.Equal(t, to, *tx.To)
+	require.Equal(t, uint32(3), tx.AccountNonce)
+	require.Equal(t, uint16(7), tx.Epoch)
+	require.Equal(t, new(big.Int).Div(new(big.Int).Mul(common.DnaBase, big.NewInt(3)), big.NewInt(2)), tx.Amount)
+	require.Equal(t, new(big.Int).Div(common.DnaBase, big.NewInt(10)), tx.MaxFee)
+
+	txSender, err := types.Sender(tx)
+	require.NoError(t, err)
+	require.Equal(t, sender, txSender)
+}
+
+func TestApp_Errors(t *testing.T) {
+	_, _, err := runApp(t, "--type", "send", "--maxfee", "1", "--nonce", "1", "--epoch", "1")
+	require.EqualError(t, err, "to is required")
+
+	_, _, err = runApp(t, "--type", "unknown", "--maxfee", "1", "--nonce", "1", "--epoch", "1")
+	require.Error(t, err)
+
+	_, _, err = runApp(t, "--type", "evidence", "--maxfee", "1", "--nonce", "1", "--epoch", "1")
+	require.EqualError(t, err, "candidates is required")
+
+	_, _, err = runApp(t, "--type", "evidence", "--candidates", "2", "--approve", "2", "--maxfee", "1", "--nonce", "1", "--epoch", "1")
+	require.Error(t, err)
+}
+
+func TestApp_Answers(t *testing.T) {
+	answers := []byte{0x1, 0x2}
+	args := []string{"--answers", hexutil.Encode(answers), "--maxfee", "1", "--nonce", "1", "--epoch", "5"}
+
+	tx, _, err := runApp(t, append([]string{"--type", "submitShortAnswers", "--rnd", "42"}, args...)...)
+	require.NoError(t, err)
+	short := attachments.ParseShortAnswerAttachment(tx)
+	require.NotNil(t, short)
+	require.Equal(t, answers, short.Answers)
+	require.Equal(t, uint64(42), short.Rnd)
+	require.Equal(t, byte(clientTypeDesktop), short.ClientType)
+
+	tx, _, err = runApp(t, append([]string{"--type", "submitLongAnswers", "--proof", "0x0304"}, args...)...)
+	require.NoError(t, err)
+	long := attachments.ParseLongAnswerAttachment(tx)
+	require.NotNil(t, long)
+	require.Equal(t, answers, long.Answers)
+	require.Equal(t, []byte{0x3, 0x4}, long.Proof)
+	require.Len(t, long.Salt, 32)
+	require.NotEmpty(t, long.Key)
+
+	tx, _, err = runApp(t, append([]string{"--type", "submitAnswersHash"}, args...)...)
+	require.NoError(t, err)
+	require.Len(t, tx.Payload, common.HashLength)
+}
+
+func TestShortAnswersSalt(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	salt, err := shortAnswersSalt(5, key)
+	require.NoError(t, err)
+	sameSalt, err := shortAnswersSalt(5, key)
+	require.NoError(t, err)
+	otherSalt, err := shortAnswersSalt(6, key)
+	require.NoError(t, err)
+
+	require.Equal(t, salt, sameSalt)
+	require.NotEqual(t, salt, otherSalt)
+}
+
+func TestApp_Evidence(t *testing.T) {
+	tx, _, err := runApp(t, "--type", "evidence", "--candidates", "10", "--approve", "1", "--approve", "8", "--maxfee", "1", "--nonce", "1", "--epoch", "1")
+	require.NoError(t, err)
+
+	bitmap := common.NewBitmap(10)
+	bitmap.Read(tx.Payload)
+	for i := uint32(0); i < 10; i++ {
+		require.Equal(t, i == 1 || i == 8, bitmap.Contains(i), "candidate %v", i)
+	}
+}
@@ -185,6 +185,14 @@ func main() {
 			return false
 		})
 
+		appState.State.IterateContractCodes(func(key []byte, value []byte) bool {
+			snapshot.ContractValues = append(snapshot.ContractValues, &models.ProtoPredefinedState_ContractKeyValue{
+				Key:   key,
+				Value: value,
+			})
+			return false
+		})
+
 		appState.IdentityState.IterateIdentities(func(key []byte, value []byte) bool {
 			if key == nil {
 				return true
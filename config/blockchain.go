@@ -4,4 +4,12 @@ type BlockchainConfig struct {
 	// distance between blocks with permanent certificates
 	StoreCertRange uint64
 	BurnTxRange    uint64
+	// save transactions of all the addresses instead of the node's own accounts only
+	IndexAllTxs bool
+	// save events of all the contracts instead of the ones with subscriptions only
+	IndexAllEvents bool
+	// keep more state versions for historical queries than it's required by consensus
+	ArchiveMode bool
+	// number of the latest state versions kept in archive mode, 0 means all the versions are kept
+	ArchiveStatesCount uint64
 }
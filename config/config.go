@@ -40,6 +40,8 @@ type Config struct {
 	OfflineDetection *OfflineDetectionConfig
 	Blockchain       *BlockchainConfig
 	Mempool          *Mempool
+
+	file string
 }
 
 func (c *Config) ProvideNodeKey(key string, password string, withBackup bool) error {
@@ -223,10 +225,28 @@ func MakeConfigFromFile(file string) (*Config, error) {
 			log.Error(err.Error())
 			return nil, err
 		}
+		cfg.file = file
 	}
 	return cfg, nil
 }
 
+// File returns the path of the JSON configuration file the config was loaded from
+func (c *Config) File() string {
+	return c.file
+}
+
+// ReadAPIKeys reads the actual list of scoped RPC API keys from the configuration file
+func (c *Config) ReadAPIKeys() ([]*rpc.APIKey, error) {
+	if c.file == "" {
+		return c.RPC.APIKeys, nil
+	}
+	conf := &Config{RPC: &rpc.Config{}}
+	if err := loadConfig(c.file, conf); err != nil {
+		return nil, err
+	}
+	return conf.RPC.APIKeys, nil
+}
+
 func getDefaultConfig(dataDir string) *Config {
 
 	ipfsConfig := GetDefaultIpfsConfig()
@@ -246,7 +266,7 @@ func getDefaultConfig(dataDir string) *Config {
 			DisableMetrics:           false,
 		},
 		Consensus: GetDefaultConsensusConfig(),
-		RPC:       rpc.GetDefaultRPCConfig(DefaultRpcHost, DefaultRpcPort),
+		RPC:       rpc.GetDefaultRPCConfig(DefaultRpcHost, DefaultRpcPort, DefaultWsPort),
 		GenesisConf: &GenesisConf{
 			FirstCeremonyTime: DefaultCeremonyTime,
 			GodAddress:        common.HexToAddress(DefaultGodAddress),
@@ -277,6 +297,10 @@ func applyFlags(ctx *cli.Context, cfg *Config) {
 	applyIpfsFlags(ctx, cfg)
 	applyValidationFlags(ctx, cfg)
 	applySyncFlags(ctx, cfg)
+	// the sentry mode overrides ipfs bootstrap nodes and routing of the profile and the flags
+	if cfg.P2P.SentryMode() {
+		applySentryMode(cfg)
+	}
 }
 
 func applyCommonFlags(ctx *cli.Context, cfg *Config) {
@@ -289,6 +313,9 @@ func applySyncFlags(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet(FastSyncFlag.Name) {
 		cfg.Sync.FastSync = ctx.Bool(FastSyncFlag.Name)
 	}
+	if ctx.IsSet(LightModeFlag.Name) {
+		cfg.Sync.LightMode = ctx.Bool(LightModeFlag.Name)
+	}
 	if ctx.IsSet(ForceFullSyncFlag.Name) {
 		cfg.Sync.ForceFullSync = ctx.Uint64(ForceFullSyncFlag.Name)
 	}
@@ -313,6 +340,12 @@ func applyRpcFlags(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet(RpcPortFlag.Name) {
 		cfg.RPC.HTTPPort = ctx.Int(RpcPortFlag.Name)
 	}
+	if ctx.IsSet(WsHostFlag.Name) {
+		cfg.RPC.WSHost = ctx.String(WsHostFlag.Name)
+	}
+	if ctx.IsSet(WsPortFlag.Name) {
+		cfg.RPC.WSPort = ctx.Int(WsPortFlag.Name)
+	}
 	if ctx.IsSet(ApiKeyFlag.Name) {
 		cfg.RPC.APIKey = ctx.String(ApiKeyFlag.Name)
 	}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/idena-network/idena-go/common"
 	"math/big"
 	"time"
 )
@@ -50,6 +51,16 @@ type ConsensusConf struct {
 	ReductionOneDelay                 time.Duration
 	NewKeyWordsEpoch                  uint16
 	EnableUpgrade7                    bool
+	// Enables deployment of user WebAssembly contracts
+	EnableWasmContracts bool
+	// Enables deployment of the payment schedule contract
+	EnablePaymentSchedules bool
+	// Gas costs of the contract environment operations
+	GasTable GasTable
+	// Enables upgrades of deployed embedded contracts to newer implementations by their owners
+	EnableContractUpgrades bool
+	// Code hashes of embedded contracts which are upgraded on their next call or termination regardless of the owners
+	ForcedContractUpgrades []common.Hash
 }
 
 type ConsensusVerson uint16
@@ -59,11 +70,14 @@ const (
 	ConsensusV6 ConsensusVerson = 6
 
 	ConsensusV7 ConsensusVerson = 7
+	// Enables WebAssembly contracts
+	ConsensusV8 ConsensusVerson = 8
 )
 
 var (
 	v6                ConsensusConf
 	v7                ConsensusConf
+	v8                ConsensusConf
 	ConsensusVersions map[ConsensusVerson]*ConsensusConf
 )
 
@@ -107,12 +121,39 @@ func init() {
 		MinProposerThreshold:              0.5,
 		UpgradeIntervalBeforeValidation:   time.Hour * 48,
 		NewKeyWordsEpoch:                  76,
+		GasTable: GasTable{
+			StorageReadByte:   10,
+			StorageWriteByte:  20,
+			StorageRemove:     5,
+			IterateItem:       0,
+			CodeReadByte:      1,
+			CodeWriteByte:     2,
+			Send:              30,
+			BurnAll:           10,
+			Deploy:            200,
+			Call:              100,
+			Event:             100,
+			EventByte:         10,
+			ChainRead:         5,
+			BalanceRead:       5,
+			IdentityStateRead: 1,
+			IdentityRead:      10,
+			EpochRead:         10,
+			ContractStakeRead: 10,
+			WasmInstruction:   1,
+			WasmCall:          10,
+			WasmMemoryPage:    1000,
+		},
 	}
 	ConsensusVersions[ConsensusV6] = &v6
 
 	v7 = v6
 	ApplyConsensusVersion(ConsensusV7, &v7)
 	ConsensusVersions[ConsensusV7] = &v7
+
+	v8 = v7
+	ApplyConsensusVersion(ConsensusV8, &v8)
+	ConsensusVersions[ConsensusV8] = &v8
 }
 
 func ApplyConsensusVersion(ver ConsensusVerson, cfg *ConsensusConf) {
@@ -123,6 +164,14 @@ func ApplyConsensusVersion(ver ConsensusVerson, cfg *ConsensusConf) {
 		cfg.StartActivationDate = time.Date(2021, 11, 15, 8, 0, 0, 0, time.UTC).Unix()
 		cfg.EndActivationDate = time.Date(2021, 11, 18, 0, 0, 0, 0, time.UTC).Unix()
 		cfg.MigrationTimeout = 0
+	case ConsensusV8:
+		cfg.EnableWasmContracts = true
+		cfg.EnablePaymentSchedules = true
+		cfg.EnableContractUpgrades = true
+		cfg.Version = ConsensusV8
+		cfg.StartActivationDate = time.Date(2022, 3, 14, 8, 0, 0, 0, time.UTC).Unix()
+		cfg.EndActivationDate = time.Date(2022, 3, 17, 0, 0, 0, 0, time.UTC).Unix()
+		cfg.MigrationTimeout = 0
 	}
 }
 
@@ -0,0 +1,35 @@
+package config
+
+// GasTable contains the gas costs of the contract environment operations
+type GasTable struct {
+	// Per byte of a contract value read from the storage
+	StorageReadByte int `json:"storageReadByte"`
+	// Per byte of a key and a value written to the storage
+	StorageWriteByte int `json:"storageWriteByte"`
+	StorageRemove    int `json:"storageRemove"`
+	// Per item visited by the storage iteration, charged on top of the read bytes
+	IterateItem   int `json:"iterateItem"`
+	CodeReadByte  int `json:"codeReadByte"`
+	CodeWriteByte int `json:"codeWriteByte"`
+	Send          int `json:"send"`
+	BurnAll       int `json:"burnAll"`
+	Deploy        int `json:"deploy"`
+	Call          int `json:"call"`
+	Event         int `json:"event"`
+	// Per byte of the event args
+	EventByte int `json:"eventByte"`
+	// Block timestamp, height and seed, network size and min fee per gas
+	ChainRead         int `json:"chainRead"`
+	BalanceRead       int `json:"balanceRead"`
+	IdentityStateRead int `json:"identityStateRead"`
+	// Identity pub key and delegatee
+	IdentityRead      int `json:"identityRead"`
+	EpochRead         int `json:"epochRead"`
+	ContractStakeRead int `json:"contractStakeRead"`
+	// Per executed instruction of a WebAssembly contract
+	WasmInstruction int `json:"wasmInstruction"`
+	// Per function call of a WebAssembly contract, including host functions
+	WasmCall int `json:"wasmCall"`
+	// Per 64KiB page of the WebAssembly contract memory, charged on instantiation and growth
+	WasmMemoryPage int `json:"wasmMemoryPage"`
+}
@@ -11,4 +11,23 @@ type P2P struct {
 	DisableMetrics bool
 	Multishard     bool
 	Shared         bool
+
+	// StaticPeers are multiaddrs with peer ids (/ip4/1.2.3.4/tcp/40405/ipfs/<id>) of trusted peers which are always
+	// redialed, they are not counted against the peer limits, are never rotated and never banned
+	StaticPeers []string
+	// AllowlistOnly restricts all libp2p connections of the node, ipfs ones included, to static peers and AllowedPeers
+	AllowlistOnly bool
+	// AllowedPeers are peer ids accepted in addition to static peers in the allowlist only mode
+	AllowedPeers []string
+
+	// SentryNodes are multiaddrs with peer ids of the sentries of a private validator, the validator runs the idena
+	// protocol with its sentries only and never joins shard topics
+	SentryNodes []string
+	// PrivatePeers are peer ids of the validators behind this sentry, they are treated as static peers which are
+	// never dialed by the sentry
+	PrivatePeers []string
+}
+
+func (p *P2P) SentryMode() bool {
+	return len(p.SentryNodes) > 0
 }
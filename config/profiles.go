@@ -34,3 +34,10 @@ func applyDefaultProfile(cfg *Config) {
 	cfg.P2P.Multishard = false
 	cfg.Sync.LoadAllFlips = false
 }
+
+// applySentryMode bootstraps ipfs from the sentries instead of the public bootstrap nodes and disables the dht, so the
+// validator neither announces itself nor looks up other peers
+func applySentryMode(cfg *Config) {
+	cfg.IpfsConf.BootNodes = cfg.P2P.SentryNodes
+	cfg.IpfsConf.Routing = "none"
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestApplySentryMode(t *testing.T) {
+	cfg := getDefaultConfig(DefaultDataDir)
+	applyDefaultProfile(cfg)
+	cfg.IpfsConf.Routing = "dht"
+	require.False(t, cfg.P2P.SentryMode())
+	require.NotEmpty(t, cfg.IpfsConf.BootNodes)
+
+	sentries := []string{"/ip4/10.0.0.2/tcp/40405/ipfs/QmSentry"}
+	cfg.P2P.SentryNodes = sentries
+	require.True(t, cfg.P2P.SentryMode())
+	applySentryMode(cfg)
+	require.Equal(t, sentries, cfg.IpfsConf.BootNodes)
+	require.Equal(t, "none", cfg.IpfsConf.Routing)
+}
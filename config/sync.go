@@ -7,4 +7,6 @@ type SyncConfig struct {
 	ForceFullSync       uint64
 	LoadAllFlips        bool
 	AllFlipsLoadingTime time.Duration
+	// LightMode syncs headers with certificates and identity state diffs only, the state is requested from peers
+	LightMode bool
 }
@@ -106,6 +106,10 @@ func (engine *Engine) ReadonlyAppState() (*appstate.AppState, error) {
 	return engine.appState.Readonly(engine.chain.Head.Height())
 }
 
+func (engine *Engine) ReadonlyAppStateAt(height uint64) (*appstate.AppState, error) {
+	return engine.appState.Readonly(height)
+}
+
 func (engine *Engine) AppStateForCheck() (*appstate.AppState, error) {
 	return engine.appState.ForCheck(engine.chain.Head.Height())
 }
@@ -1,6 +1,7 @@
 package appstate
 
 import (
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common/eventbus"
 	"github.com/idena-network/idena-go/core/state"
@@ -21,10 +22,14 @@ type AppState struct {
 	defaultTree       bool
 	prevPrecommitDiff *state.IdentityStateDiff
 
-	readonlyStateCache map[uint64]*AppState
+	readonlyStateCache *lru.Cache
 	readonlyStateMutex sync.RWMutex
 }
 
+// readonlyStatesCacheSize limits the number of readonly states kept in memory, historical queries of archive nodes
+// must not evict the head state
+const readonlyStatesCacheSize = 8
+
 func NewAppState(db dbm.DB, bus eventbus.Bus) (*AppState, error) {
 	stateDb, err := state.NewLazy(db)
 	if err != nil {
@@ -41,6 +46,13 @@ func NewAppState(db dbm.DB, bus eventbus.Bus) (*AppState, error) {
 		defaultTree:   true,
 	}, nil
 }
+
+// SetArchiveMode makes the state trees keep statesCount latest versions, all the versions are kept if statesCount is 0
+func (s *AppState) SetArchiveMode(statesCount uint64) {
+	s.State.SetArchiveMode(statesCount)
+	s.IdentityState.SetArchiveMode(statesCount)
+}
+
 func (s *AppState) ForCheck(height uint64) (*AppState, error) {
 	st, err := s.State.ForCheck(height)
 	if err != nil {
@@ -67,17 +79,22 @@ func (s *AppState) ForCheck(height uint64) (*AppState, error) {
 func (s *AppState) Readonly(height uint64) (*AppState, error) {
 
 	s.readonlyStateMutex.RLock()
-	if state, ok := s.readonlyStateCache[height]; ok {
-		s.readonlyStateMutex.RUnlock()
-		return state, nil
+	if s.readonlyStateCache != nil {
+		if state, ok := s.readonlyStateCache.Get(height); ok {
+			s.readonlyStateMutex.RUnlock()
+			return state.(*AppState), nil
+		}
 	}
 	s.readonlyStateMutex.RUnlock()
 
 	s.readonlyStateMutex.Lock()
 	defer s.readonlyStateMutex.Unlock()
 
-	if state, ok := s.readonlyStateCache[height]; ok {
-		return state, nil
+	if s.readonlyStateCache == nil {
+		s.readonlyStateCache, _ = lru.New(readonlyStatesCacheSize)
+	}
+	if state, ok := s.readonlyStateCache.Get(height); ok {
+		return state.(*AppState), nil
 	}
 
 	st, err := s.State.Readonly(int64(height))
@@ -101,7 +118,7 @@ func (s *AppState) Readonly(height uint64) (*AppState, error) {
 		NonceCache:      s.NonceCache,
 	}
 
-	s.readonlyStateCache = map[uint64]*AppState{height: state}
+	s.readonlyStateCache.Add(height, state)
 	return state, nil
 }
 
@@ -47,3 +47,26 @@ func TestAppState_ForCheckWithReload(t *testing.T) {
 	require.Equal(t, stateHash, appState.State.Root())
 	require.Equal(t, identityHash, appState.IdentityState.Root())
 }
+
+func TestAppState_Readonly(t *testing.T) {
+	appState, _ := NewAppState(db2.NewMemDB(), eventbus.New())
+	appState.Initialize(0)
+
+	addr := common.Address{0x1}
+	for i := uint32(1); i <= readonlyStatesCacheSize+1; i++ {
+		appState.State.SetNonce(addr, i)
+		require.NoError(t, appState.Commit(nil))
+	}
+
+	head, err := appState.Readonly(readonlyStatesCacheSize + 1)
+	require.NoError(t, err)
+	for height := uint64(1); height < readonlyStatesCacheSize; height++ {
+		state, err := appState.Readonly(height)
+		require.NoError(t, err)
+		require.Equal(t, uint32(height), state.State.GetNonce(addr))
+	}
+	// historical queries don't evict recently used states
+	cached, err := appState.Readonly(readonlyStatesCacheSize + 1)
+	require.NoError(t, err)
+	require.True(t, head == cached)
+}
@@ -22,6 +22,9 @@ type IdentityStateDB struct {
 	stateIdentities      map[common.Address]*stateApprovedIdentity
 	stateIdentitiesDirty map[common.Address]struct{}
 
+	archive            bool
+	archiveStatesCount uint64
+
 	log  log.Logger
 	lock sync.Mutex
 }
@@ -153,13 +156,21 @@ func (s *IdentityStateDB) Commit(deleteEmptyObjects bool) (root []byte, version
 	return hash, version, diff, err
 }
 
+// SetArchiveMode makes the state keep statesCount latest versions of the tree instead of MaxSavedStatesCount,
+// all the versions are kept if statesCount is 0
+func (s *IdentityStateDB) SetArchiveMode(statesCount uint64) {
+	s.archive = true
+	s.archiveStatesCount = statesCount
+}
+
 func (s *IdentityStateDB) CommitTree(newVersion int64) (root []byte, version int64, err error) {
 	hash, version, err := s.tree.SaveVersionAt(newVersion)
-	if version > MaxSavedStatesCount {
+	savedStatesCount := savedStatesCount(s.archive, s.archiveStatesCount)
+	if savedStatesCount > 0 && version > int64(savedStatesCount) {
 
 		versions := s.tree.AvailableVersions()
 
-		for i := 0; i < len(versions)-MaxSavedStatesCount; i++ {
+		for i := 0; i < len(versions)-savedStatesCount; i++ {
 			if s.tree.ExistVersion(int64(versions[i])) {
 				err = s.tree.DeleteVersion(int64(versions[i]))
 				if err != nil {
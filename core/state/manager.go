@@ -1,7 +1,9 @@
 package state
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
@@ -13,7 +15,9 @@ import (
 	"github.com/idena-network/idena-go/ipfs"
 	"github.com/idena-network/idena-go/log"
 	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
 	dbm "github.com/tendermint/tm-db"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -32,8 +36,21 @@ const SnapshotVersionV2 = SnapshotVersion(2)
 var (
 	InvalidManifestPrefix = []byte("im")
 	MaxManifestTimeouts   = byte(5)
+	// DownloadProgressKey -> loaded bytes + height + cid of the snapshot being loaded
+	DownloadProgressKey = []byte("dp")
 )
 
+// the loaded part of the snapshot file is synced to disk and the progress is saved once per the number of chunks
+const saveProgressChunks = 32
+
+// SnapshotProgress describes the snapshot being loaded, Resumed bytes have been loaded by previous attempts
+type SnapshotProgress struct {
+	Loaded  int64
+	Total   int64
+	Resumed int64
+	Started time.Time
+}
+
 type SnapshotManager struct {
 	db        dbm.DB
 	state     *StateDB
@@ -43,6 +60,9 @@ type SnapshotManager struct {
 	cfg       *config.Config
 	log       log.Logger
 	repo      *database.Repo
+
+	progress      *SnapshotProgress
+	progressMutex sync.RWMutex
 }
 
 func NewSnapshotManager(db dbm.DB, state *StateDB, bus eventbus.Bus, ipfs ipfs.Proxy, cfg *config.Config) *SnapshotManager {
@@ -64,13 +84,17 @@ func NewSnapshotManager(db dbm.DB, state *StateDB, bus eventbus.Bus, ipfs ipfs.P
 	return m
 }
 
+func snapshotFilePath(datadir string, height uint64, version SnapshotVersion) string {
+	return filepath.Join(datadir, SnapshotsFolder, strconv.FormatUint(height, 10)+"."+strconv.FormatInt(int64(version), 10)+".tar")
+}
+
 func createSnapshotFile(datadir string, height uint64, version SnapshotVersion) (fileName string, file *os.File, err error) {
 	newpath := filepath.Join(datadir, SnapshotsFolder)
 	if err := os.MkdirAll(newpath, os.ModePerm); err != nil {
 		return "", nil, err
 	}
 
-	filePath := filepath.Join(newpath, strconv.FormatUint(height, 10)+"."+strconv.FormatInt(int64(version), 10)+".tar")
+	filePath := snapshotFilePath(datadir, height, version)
 	f, err := os.Create(filePath)
 	if err != nil {
 		return "", nil, err
@@ -134,6 +158,10 @@ func (m *SnapshotManager) clearFs(excludedFiles []string) {
 	if prevCidV2, _, _, _ := m.repo.LastSnapshotManifest(); prevCidV2 != nil {
 		m.ipfs.Unpin(prevCidV2)
 	}
+	// the partially loaded snapshot is kept to resume its loading
+	if partial := m.partialSnapshotFile(); partial != "" {
+		excludedFiles = append(excludedFiles, partial)
+	}
 	m.clearSnapshotFolder(excludedFiles)
 }
 
@@ -173,37 +201,69 @@ func (m *SnapshotManager) writeLastManifest(snapshotCidV2 []byte, root common.Ha
 	m.repo.WriteLastSnapshotManifest(snapshotCidV2, root, height, fileV2)
 }
 
+// DownloadSnapshot loads the snapshot file from ipfs, the loading resumes from the part saved by the previous attempt.
+// The manifest is blacklisted if the data is invalid and counted as timed out if the attempt has loaded nothing.
 func (m *SnapshotManager) DownloadSnapshot(snapshot *snapshot.Manifest) (filePath string, version SnapshotVersion, err error) {
 	version = SnapshotVersionV2
-	filePath, file, err := createSnapshotFile(m.cfg.DataDir, snapshot.Height, version)
+	filePath, file, offset, err := m.openSnapshotFile(snapshot, version)
 	if err != nil {
 		return "", 0, err
 	}
+	defer file.Close()
+	if offset > 0 {
+		m.log.Info("Snapshot loading is resumed", "loaded", offset)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m.progressMutex.Lock()
+	m.progress = &SnapshotProgress{
+		Loaded:  offset,
+		Resumed: offset,
+		Started: time.Now(),
+	}
+	m.progressMutex.Unlock()
+	defer func() {
+		m.progressMutex.Lock()
+		m.progress = nil
+		m.progressMutex.Unlock()
+	}()
 
 	lastLoad := time.Now()
 	lock := sync.Mutex{}
 	logLevels := []float32{0.15, 0.3, 0.5, 0.75}
-	onLoading := func(size, read int64) {
+	chunks, loaded := 0, offset
+	saveProgress := func() error {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+		m.writeDownloadProgress(snapshot.CidV2, snapshot.Height, loaded)
+		return nil
+	}
+	onChunk := func(chunkEnd, size int64) error {
 		lock.Lock()
 		lastLoad = time.Now()
 		lock.Unlock()
-		if size > 0 && len(logLevels) > 0 && float32(read)/float32(size) >= logLevels[0] {
+		chunks, loaded = chunks+1, chunkEnd
+		m.progressMutex.Lock()
+		m.progress.Loaded, m.progress.Total = loaded, size
+		m.progressMutex.Unlock()
+		if size > 0 && len(logLevels) > 0 && float32(loaded)/float32(size) >= logLevels[0] {
 			m.log.Info("Snapshot loading", "progress", fmt.Sprintf("%v%%", logLevels[0]*100))
 			logLevels = logLevels[1:]
 		}
+		if chunks%saveProgressChunks == 0 {
+			return saveProgress()
+		}
+		return nil
 	}
 	var loadToErr error
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	//done := false
 	done := make(chan struct{})
 
 	go func() {
-		loadToErr = m.ipfs.LoadTo(snapshot.CidV2, file, ctx, onLoading)
-		wg.Done()
+		loadToErr = m.ipfs.LoadChunksTo(snapshot.CidV2, offset, file, ctx, onChunk)
 		close(done)
 	}()
 
@@ -225,19 +285,90 @@ func (m *SnapshotManager) DownloadSnapshot(snapshot *snapshot.Manifest) (filePat
 		}
 	}()
 
-	wg.Wait()
+	<-done
+
+	if loadToErr != nil {
+		switch {
+		case errors.Is(loadToErr, ipfs.InvalidDataErr):
+			m.AddInvalidManifest(snapshot.CidV2)
+			m.db.Delete(DownloadProgressKey)
+			file.Close()
+			os.Remove(filePath)
+		case loaded == offset:
+			m.AddTimeoutManifest(snapshot.CidV2)
+		default:
+			// the loaded chunks are kept to resume the loading by the next attempt
+			if err := saveProgress(); err != nil {
+				m.log.Warn("Cannot save snapshot loading progress", "err", err)
+			}
+		}
+		return filePath, version, loadToErr
+	}
+	m.db.Delete(DownloadProgressKey)
+	m.clearFs([]string{filePath})
+	var filePath2 string
+
+	if version == SnapshotVersionV2 {
+		filePath2 = filePath
+	}
+	m.writeLastManifest(snapshot.CidV2, snapshot.Root, snapshot.Height, filePath2)
 
-	if loadToErr == nil {
-		m.clearFs([]string{filePath})
-		var filePath2 string
+	return filePath, version, nil
+}
 
-		if version == SnapshotVersionV2 {
-			filePath2 = filePath
+// openSnapshotFile opens the partially loaded snapshot file if the previous loading of the manifest was interrupted
+// or creates a new one
+func (m *SnapshotManager) openSnapshotFile(snapshot *snapshot.Manifest, version SnapshotVersion) (filePath string, file *os.File, offset int64, err error) {
+	if cidBytes, height, loaded := m.readDownloadProgress(); bytes.Equal(cidBytes, snapshot.CidV2) && height == snapshot.Height && loaded > 0 {
+		filePath = snapshotFilePath(m.cfg.DataDir, snapshot.Height, version)
+		if file, err = os.OpenFile(filePath, os.O_RDWR, 0644); err == nil {
+			if stat, err := file.Stat(); err == nil && stat.Size() >= loaded {
+				if err := file.Truncate(loaded); err == nil {
+					if _, err := file.Seek(loaded, io.SeekStart); err == nil {
+						return filePath, file, loaded, nil
+					}
+				}
+			}
+			file.Close()
 		}
-		m.writeLastManifest(snapshot.CidV2, snapshot.Root, snapshot.Height, filePath2)
 	}
+	m.db.Delete(DownloadProgressKey)
+	filePath, file, err = createSnapshotFile(m.cfg.DataDir, snapshot.Height, version)
+	return filePath, file, 0, err
+}
+
+// partialSnapshotFile returns the path of the snapshot file being loaded or an empty string
+func (m *SnapshotManager) partialSnapshotFile() string {
+	if cidBytes, height, _ := m.readDownloadProgress(); cidBytes != nil {
+		return snapshotFilePath(m.cfg.DataDir, height, SnapshotVersionV2)
+	}
+	return ""
+}
 
-	return filePath, version, loadToErr
+func (m *SnapshotManager) writeDownloadProgress(cid []byte, height uint64, loaded int64) {
+	value := make([]byte, 16, 16+len(cid))
+	binary.LittleEndian.PutUint64(value[:8], uint64(loaded))
+	binary.LittleEndian.PutUint64(value[8:], height)
+	m.db.Set(DownloadProgressKey, append(value, cid...))
+}
+
+func (m *SnapshotManager) readDownloadProgress() (cid []byte, height uint64, loaded int64) {
+	value, err := m.db.Get(DownloadProgressKey)
+	if err != nil || len(value) <= 16 {
+		return nil, 0, 0
+	}
+	return value[16:], binary.LittleEndian.Uint64(value[8:16]), int64(binary.LittleEndian.Uint64(value[:8]))
+}
+
+// Progress returns the progress of the snapshot loading or nil if no snapshot is being loaded
+func (m *SnapshotManager) Progress() *SnapshotProgress {
+	m.progressMutex.RLock()
+	defer m.progressMutex.RUnlock()
+	if m.progress == nil {
+		return nil
+	}
+	progress := *m.progress
+	return &progress
 }
 
 func (m *SnapshotManager) StartSync() {
@@ -1,8 +1,19 @@
 package state
 
 import (
+	"context"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/state/snapshot"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/idena-network/idena-go/log"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	db "github.com/tendermint/tm-db"
+	"io"
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -28,3 +39,134 @@ func TestSnapshotManager_IsInvalidManifest(t *testing.T) {
 	require.True(t, m.IsInvalidManifest([]byte{0x3}))
 	require.False(t, m.IsInvalidManifest([]byte{0x4}))
 }
+
+func TestSnapshotManager_openSnapshotFile(t *testing.T) {
+	m := SnapshotManager{
+		db:  db.NewMemDB(),
+		cfg: &config.Config{DataDir: t.TempDir()},
+	}
+	manifest := &snapshot.Manifest{CidV2: []byte{0x1}, Height: 10}
+
+	filePath, file, offset, err := m.openSnapshotFile(manifest, SnapshotVersionV2)
+	require.NoError(t, err)
+	require.Zero(t, offset)
+	_, err = file.Write([]byte{0x1, 0x2, 0x3, 0x4, 0x5})
+	require.NoError(t, err)
+	file.Close()
+
+	m.writeDownloadProgress(manifest.CidV2, manifest.Height, 3)
+
+	resumedPath, file, offset, err := m.openSnapshotFile(manifest, SnapshotVersionV2)
+	require.NoError(t, err)
+	require.Equal(t, filePath, resumedPath)
+	require.Equal(t, int64(3), offset)
+	_, err = file.Write([]byte{0x6})
+	require.NoError(t, err)
+	file.Close()
+	data, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x3, 0x6}, data)
+	require.Equal(t, filePath, m.partialSnapshotFile())
+
+	m.writeDownloadProgress([]byte{0x2}, manifest.Height, 3)
+	_, file, offset, err = m.openSnapshotFile(manifest, SnapshotVersionV2)
+	require.NoError(t, err)
+	require.Zero(t, offset)
+	file.Close()
+	cid, _, _ := m.readDownloadProgress()
+	require.Nil(t, cid)
+}
+
+// interruptedIpfs fails the loading after the limit of chunks and records the offsets of the loadings
+type interruptedIpfs struct {
+	ipfs.Proxy
+	limit   int
+	offsets []int64
+}
+
+func (i *interruptedIpfs) LoadChunksTo(key []byte, offset int64, to io.Writer, ctx context.Context, onChunk func(loaded, size int64) error) error {
+	i.offsets = append(i.offsets, offset)
+	chunks := 0
+	return i.Proxy.LoadChunksTo(key, offset, to, ctx, func(loaded, size int64) error {
+		if chunks == i.limit {
+			return errors.New("interrupted")
+		}
+		chunks++
+		return onChunk(loaded, size)
+	})
+}
+
+func newTestSnapshotManager(t *testing.T, proxy ipfs.Proxy) *SnapshotManager {
+	return &SnapshotManager{
+		db:   db.NewMemDB(),
+		repo: database.NewRepo(db.NewMemDB()),
+		cfg:  &config.Config{DataDir: t.TempDir()},
+		log:  log.New(),
+		ipfs: proxy,
+	}
+}
+
+func TestSnapshotManager_DownloadSnapshot_Resume(t *testing.T) {
+	data := random.GetRandomBytes(10000)
+	proxy := &interruptedIpfs{Proxy: ipfs.NewMemoryIpfsProxy(), limit: 3}
+	c, _ := proxy.Add(data, true)
+	manifest := &snapshot.Manifest{CidV2: c.Bytes(), Height: 10}
+	m := newTestSnapshotManager(t, proxy)
+
+	filePath, _, err := m.DownloadSnapshot(manifest)
+	require.Error(t, err)
+	require.False(t, m.IsInvalidManifest(manifest.CidV2))
+	timeouts, _ := m.db.Get(append(InvalidManifestPrefix, manifest.CidV2...))
+	require.Nil(t, timeouts)
+	cid, height, loaded := m.readDownloadProgress()
+	require.Equal(t, manifest.CidV2, cid)
+	require.Equal(t, manifest.Height, height)
+	require.Equal(t, int64(3072), loaded)
+
+	m.clearFs(nil)
+	_, err = os.Stat(filePath)
+	require.NoError(t, err)
+
+	proxy.limit = 100
+	resumedPath, _, err := m.DownloadSnapshot(manifest)
+	require.NoError(t, err)
+	require.Equal(t, filePath, resumedPath)
+	require.Equal(t, []int64{0, 3072}, proxy.offsets)
+	loadedData, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, data, loadedData)
+	cid, _, _ = m.readDownloadProgress()
+	require.Nil(t, cid)
+}
+
+func TestSnapshotManager_DownloadSnapshot_Failures(t *testing.T) {
+	data := random.GetRandomBytes(10000)
+	proxy := &interruptedIpfs{Proxy: ipfs.NewMemoryIpfsProxy()}
+	c, _ := proxy.Add(data, true)
+	manifest := &snapshot.Manifest{CidV2: c.Bytes(), Height: 10}
+	m := newTestSnapshotManager(t, proxy)
+
+	// no chunk is loaded by the attempt
+	_, _, err := m.DownloadSnapshot(manifest)
+	require.Error(t, err)
+	require.False(t, m.IsInvalidManifest(manifest.CidV2))
+	timeouts, _ := m.db.Get(append(InvalidManifestPrefix, manifest.CidV2...))
+	require.Equal(t, []byte{1}, timeouts)
+
+	proxy.limit = 2
+	_, _, err = m.DownloadSnapshot(manifest)
+	require.Error(t, err)
+	_, _, loaded := m.readDownloadProgress()
+	require.Equal(t, int64(2048), loaded)
+
+	// the stored value is corrupted
+	data[5000] ^= 0xff
+	proxy.limit = 100
+	filePath, _, err := m.DownloadSnapshot(manifest)
+	require.True(t, errors.Is(err, ipfs.InvalidDataErr))
+	require.True(t, m.IsInvalidManifest(manifest.CidV2))
+	cid, _, _ := m.readDownloadProgress()
+	require.Nil(t, cid)
+	_, err = os.Stat(filePath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -77,6 +77,9 @@ type StateDB struct {
 	stateDelayedOfflinePenalties      *stateDelayedOfflinePenalties
 	stateDelayedOfflinePenaltiesDirty bool
 
+	archive            bool
+	archiveStatesCount uint64
+
 	log  log.Logger
 	lock sync.Mutex
 }
@@ -890,6 +893,26 @@ func (s *StateDB) GetOrNewDelayedOfflinePenaltyObject() *stateDelayedOfflinePena
 	return stateObject
 }
 
+// DirtyAddresses returns addresses of the accounts and identities modified since the last commit
+func (s *StateDB) DirtyAddresses() []common.Address {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := make([]common.Address, 0, len(s.stateAccountsDirty)+len(s.stateIdentitiesDirty))
+	for addr := range s.stateAccountsDirty {
+		result = append(result, addr)
+	}
+	for addr := range s.stateIdentitiesDirty {
+		if _, ok := s.stateAccountsDirty[addr]; !ok {
+			result = append(result, addr)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Bytes(), result[j].Bytes()) < 0
+	})
+	return result
+}
+
 // MarkStateAccountObjectDirty adds the specified object to the dirty map to avoid costly
 // state object cache iteration to find a handful of modified ones.
 func (s *StateDB) MarkStateAccountObjectDirty(addr common.Address) {
@@ -1000,13 +1023,21 @@ func (s *StateDB) SaveForcedVersion(height uint64) (root []byte, version int64,
 	return s.CommitTree(int64(height))
 }
 
+// SetArchiveMode makes the state keep statesCount latest versions of the tree instead of MaxSavedStatesCount,
+// all the versions are kept if statesCount is 0
+func (s *StateDB) SetArchiveMode(statesCount uint64) {
+	s.archive = true
+	s.archiveStatesCount = statesCount
+}
+
 func (s *StateDB) CommitTree(newVersion int64) (root []byte, version int64, err error) {
 	hash, version, err := s.tree.SaveVersionAt(newVersion)
-	if version > MaxSavedStatesCount {
+	savedStatesCount := savedStatesCount(s.archive, s.archiveStatesCount)
+	if savedStatesCount > 0 && version > int64(savedStatesCount) {
 
 		versions := s.tree.AvailableVersions()
 
-		for i := 0; i < len(versions)-MaxSavedStatesCount; i++ {
+		for i := 0; i < len(versions)-savedStatesCount; i++ {
 			if s.tree.ExistVersion(int64(versions[i])) {
 				err = s.tree.DeleteVersion(int64(versions[i]))
 				if err != nil {
@@ -1020,6 +1051,20 @@ func (s *StateDB) CommitTree(newVersion int64) (root []byte, version int64, err
 	return hash, version, err
 }
 
+// savedStatesCount returns the number of tree versions to keep, 0 means all the versions should be kept
+func savedStatesCount(archive bool, archiveStatesCount uint64) int {
+	if !archive {
+		return MaxSavedStatesCount
+	}
+	if archiveStatesCount == 0 {
+		return 0
+	}
+	if archiveStatesCount < MaxSavedStatesCount {
+		return MaxSavedStatesCount
+	}
+	return int(archiveStatesCount)
+}
+
 func (s *StateDB) AddDiff(diffs []*StateTreeDiff) {
 	for _, diff := range diffs {
 		if diff.Deleted {
@@ -1205,6 +1250,17 @@ func (s *StateDB) GetIdentityWithProof(addr common.Address) ([]byte, error) {
 	return s.tree.GetImmutable().GetWithProof(StateDbKeys.IdentityKey(addr))
 }
 
+// GetAccountWithProof returns the encoded account of the committed state and the proof against the state root,
+// the account is nil if it does not exist
+func (s *StateDB) GetAccountWithProof(addr common.Address) (account []byte, proof []byte, err error) {
+	return s.tree.GetImmutable().GetWithRangeProof(StateDbKeys.AddressKey(addr))
+}
+
+// GetContractValueWithProof returns the contract value of the committed state and the proof against the state root
+func (s *StateDB) GetContractValueWithProof(addr common.Address, key []byte) (value []byte, proof []byte, err error) {
+	return s.tree.GetImmutable().GetWithRangeProof(StateDbKeys.ContractStoreKey(addr, key))
+}
+
 func (s *StateDB) IterateOverIdentities(callback func(addr common.Address, identity Identity)) {
 	s.IterateIdentities(func(key []byte, value []byte) bool {
 		if key == nil {
@@ -1459,36 +1515,54 @@ func (s *StateDB) RemoveContractValue(addr common.Address, key []byte) {
 	}
 }
 
+// IterateContractStore iterates over the contract store in the key order, the cached values are merged with the values
+// of the tree
 func (s *StateDB) IterateContractStore(addr common.Address, minKey []byte, maxKey []byte, f func(key []byte, value []byte) bool) {
 
-	iteratedKeys := make(map[string]struct{})
-
 	if minKey == nil {
 		minKey = contractStoreMinKey
 	}
 	if maxKey == nil {
 		maxKey = contractStoreMaxKey
 	}
+	minStoreKey, maxStoreKey := StateDbKeys.ContractStoreKey(addr, minKey), StateDbKeys.ContractStoreKey(addr, maxKey)
+	prefixLen := common.AddressLength + len(contractStorePrefix)
 
-	for key, value := range s.contractStoreCache {
-		keyBytes := []byte(key)
-		if (bytes.Compare(keyBytes, StateDbKeys.ContractStoreKey(addr, minKey)) >= 0) && (bytes.Compare(keyBytes, StateDbKeys.ContractStoreKey(addr, maxKey)) <= 0) {
-			iteratedKeys[key] = struct{}{}
-			if !value.removed && f(keyBytes[common.AddressLength+len(contractStorePrefix):], value.value) {
-				return
-			}
+	var cachedKeys []string
+	for key := range s.contractStoreCache {
+		if key >= string(minStoreKey) && key <= string(maxStoreKey) {
+			cachedKeys = append(cachedKeys, key)
 		}
 	}
+	sort.Strings(cachedKeys)
+	cachedValues := make([]*contractStoreValue, len(cachedKeys))
+	for i, key := range cachedKeys {
+		cachedValues[i] = s.contractStoreCache[key]
+	}
 
-	s.tree.GetImmutable().IterateRangeInclusive(StateDbKeys.ContractStoreKey(addr, minKey), StateDbKeys.ContractStoreKey(addr, maxKey), true,
-		func(key []byte, value []byte, version int64) (stopped bool) {
-
-			if _, ok := iteratedKeys[string(key)]; ok {
-				return false
+	next := 0
+	iterateCached := func(value *contractStoreValue, key string) bool {
+		next++
+		return !value.removed && f([]byte(key)[prefixLen:], value.value)
+	}
+	stopped := false
+	s.tree.GetImmutable().IterateRangeInclusive(minStoreKey, maxStoreKey, true,
+		func(key []byte, value []byte, version int64) bool {
+			for next < len(cachedKeys) && cachedKeys[next] <= string(key) {
+				cachedKey := cachedKeys[next]
+				if stopped = iterateCached(cachedValues[next], cachedKey); stopped {
+					return true
+				}
+				if cachedKey == string(key) {
+					return false
+				}
 			}
-
-			return f(key[common.AddressLength+len(contractStorePrefix):], value)
+			stopped = f(key[prefixLen:], value)
+			return stopped
 		})
+	for !stopped && next < len(cachedKeys) {
+		stopped = iterateCached(cachedValues[next], cachedKeys[next])
+	}
 }
 
 // Iterate over all stored contract data
@@ -1499,6 +1573,30 @@ func (s *StateDB) IterateContractValues(f func(key []byte, value []byte) bool) {
 		})
 }
 
+// Iterate over all stored codes of wasm contracts
+func (s *StateDB) IterateContractCodes(f func(key []byte, value []byte) bool) {
+	s.tree.GetImmutable().IterateRange(StateDbKeys.ContractCodeKey(common.MinHash), StateDbKeys.ContractCodeKey(common.BytesToHash(common.MaxHash)), true,
+		func(key []byte, value []byte) (stopped bool) {
+			return f(key, value)
+		})
+}
+
+func (s *StateDB) SetContractCode(codeHash common.Hash, code []byte) {
+	s.contractStoreCache[string(StateDbKeys.ContractCodeKey(codeHash))] = &contractStoreValue{
+		value:   code,
+		removed: false,
+	}
+}
+
+func (s *StateDB) GetContractCode(codeHash common.Hash) []byte {
+	key := StateDbKeys.ContractCodeKey(codeHash)
+	if v, ok := s.contractStoreCache[string(key)]; ok {
+		return v.value
+	}
+	_, value := s.tree.Get(key)
+	return value
+}
+
 func (s *StateDB) DeployContract(addr common.Address, codeHash common.Hash, stake *big.Int) {
 	contract := s.GetOrNewAccountObject(addr)
 	contract.SetCodeHash(codeHash)
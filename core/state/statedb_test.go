@@ -177,6 +177,22 @@ func TestStateDB_AddBalance(t *testing.T) {
 	require.Equal(t, balance, fromDb.Balance())
 }
 
+func TestStateDB_DirtyAddresses(t *testing.T) {
+	stateDb, _ := NewLazy(db.NewMemDB())
+
+	addr1, addr2 := common.Address{0x2}, common.Address{0x1}
+	stateDb.SetBalance(addr1, big.NewInt(10))
+	stateDb.SetState(addr1, Candidate)
+	stateDb.SetState(addr2, Invite)
+	require.Equal(t, []common.Address{addr2, addr1}, stateDb.DirtyAddresses())
+
+	stateDb.Commit(true)
+	require.Empty(t, stateDb.DirtyAddresses())
+
+	stateDb.AddStake(addr2, big.NewInt(1))
+	require.Equal(t, []common.Address{addr2}, stateDb.DirtyAddresses())
+}
+
 func TestStateDB_GetOrNewIdentityObject(t *testing.T) {
 	database := db.NewMemDB()
 	stateDb, _ := NewLazy(database)
@@ -494,3 +510,31 @@ func TestStateDb_ShardId(t *testing.T) {
 	stateDb.SetShardId(identity, common.ShardId(3))
 	require.Equal(t, common.ShardId(3), stateDb.ShardId(identity))
 }
+
+func TestStateDB_ArchiveMode(t *testing.T) {
+	stateDb, _ := NewLazy(db.NewMemDB())
+	archiveDb, _ := NewLazy(db.NewMemDB())
+	archiveDb.SetArchiveMode(0)
+
+	addr := common.Address{0x1}
+	for i := 1; i <= MaxSavedStatesCount+10; i++ {
+		stateDb.SetBalance(addr, big.NewInt(int64(i)))
+		archiveDb.SetBalance(addr, big.NewInt(int64(i)))
+		_, _, _, err := stateDb.Commit(true)
+		require.NoError(t, err)
+		_, _, _, err = archiveDb.Commit(true)
+		require.NoError(t, err)
+	}
+
+	_, err := stateDb.Readonly(1)
+	require.Error(t, err)
+
+	historical, err := archiveDb.Readonly(1)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), historical.GetBalance(addr))
+
+	require.Equal(t, 0, savedStatesCount(true, 0))
+	require.Equal(t, MaxSavedStatesCount, savedStatesCount(true, 10))
+	require.Equal(t, 1000, savedStatesCount(true, 1000))
+	require.Equal(t, MaxSavedStatesCount, savedStatesCount(false, 1000))
+}
@@ -0,0 +1,57 @@
+// Package stateproof verifies values of the state tree against the state root of a block header without
+// trusting the node which has returned them.
+package stateproof
+
+import (
+	"github.com/cosmos/iavl"
+	iavlproto "github.com/cosmos/iavl/proto"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/pkg/errors"
+)
+
+// Verify checks that the state with the root contains the value under the key, nil value checks that the key is absent
+func Verify(root common.Hash, key, value, proof []byte) error {
+	pbProof := new(iavlproto.RangeProof)
+	if err := pbProof.Unmarshal(proof); err != nil {
+		return errors.Wrap(err, "failed to decode proof")
+	}
+	rangeProof, err := iavl.RangeProofFromProto(pbProof)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode proof")
+	}
+	if len(rangeProof.Leaves) == 0 {
+		return errors.New("proof has no leaves")
+	}
+	if err := rangeProof.Verify(root.Bytes()); err != nil {
+		return err
+	}
+	if value == nil {
+		return rangeProof.VerifyAbsence(key)
+	}
+	return rangeProof.VerifyItem(key, value)
+}
+
+// VerifyContractValue checks the value stored by the contract under the key, nil value checks that the key is absent
+func VerifyContractValue(root common.Hash, contract common.Address, key, value, proof []byte) error {
+	return Verify(root, state.StateDbKeys.ContractStoreKey(contract, key), value, proof)
+}
+
+// VerifyAccount checks the encoded account of the address and decodes it, nil account checks that the address has no
+// account and nil is returned
+func VerifyAccount(root common.Hash, addr common.Address, account, proof []byte) (*state.Account, error) {
+	if len(account) == 0 {
+		account = nil
+	}
+	if err := Verify(root, state.StateDbKeys.AddressKey(addr), account, proof); err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, nil
+	}
+	result := new(state.Account)
+	if err := result.FromBytes(account); err != nil {
+		return nil, errors.Wrap(err, "failed to decode account")
+	}
+	return result, nil
+}
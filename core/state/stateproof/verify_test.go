@@ -0,0 +1,54 @@
+package stateproof
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+	"math/big"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	stateDb, _ := state.NewLazy(db.NewMemDB())
+	contract := common.Address{0x1}
+	addr := common.Address{0x2}
+	for i := byte(0); i < 10; i++ {
+		stateDb.SetContractValue(contract, []byte{i * 2}, []byte{i})
+		stateDb.AddBalance(common.Address{0x3, i}, big.NewInt(int64(i)+1))
+	}
+	stateDb.AddBalance(addr, big.NewInt(100))
+	stateDb.Commit(true)
+	root := stateDb.Root()
+
+	value, proof, err := stateDb.GetContractValueWithProof(contract, []byte{0x4})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x2}, value)
+	require.NoError(t, VerifyContractValue(root, contract, []byte{0x4}, value, proof))
+	require.Error(t, VerifyContractValue(root, contract, []byte{0x4}, []byte{0x3}, proof))
+	require.Error(t, VerifyContractValue(root, contract, []byte{0x4}, nil, proof))
+	require.Error(t, VerifyContractValue(common.Hash{0x1}, contract, []byte{0x4}, value, proof))
+
+	value, proof, err = stateDb.GetContractValueWithProof(contract, []byte{0x5})
+	require.NoError(t, err)
+	require.Nil(t, value)
+	require.NoError(t, VerifyContractValue(root, contract, []byte{0x5}, nil, proof))
+	require.Error(t, VerifyContractValue(root, contract, []byte{0x4}, nil, proof))
+
+	account, proof, err := stateDb.GetAccountWithProof(addr)
+	require.NoError(t, err)
+	decoded, err := VerifyAccount(root, addr, account, proof)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), decoded.Balance)
+	_, err = VerifyAccount(root, common.Address{0x3, 0x1}, account, proof)
+	require.Error(t, err)
+
+	account, proof, err = stateDb.GetAccountWithProof(common.Address{0x4})
+	require.NoError(t, err)
+	require.Nil(t, account)
+	decoded, err = VerifyAccount(root, common.Address{0x4}, account, proof)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+
+	require.Error(t, Verify(root, []byte{0x1}, []byte{0x1}, []byte{0x1, 0x2}))
+}
@@ -320,3 +320,16 @@ func (t *ImmutableTree) GetWithProof(key []byte) ([]byte, error) {
 		Proof: proof.LeftPath,
 	}).toBytes()
 }
+
+// GetWithRangeProof returns the value of the key and the encoded proof of its inclusion or absence against the tree hash
+func (t *ImmutableTree) GetWithRangeProof(key []byte) (value []byte, proof []byte, err error) {
+	value, rangeProof, err := t.tree.GetWithProof(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err = rangeProof.ToProto().Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, proof, nil
+}
@@ -13,7 +13,7 @@ import (
 	"time"
 )
 
-const TargetVersion = config.ConsensusV7
+const TargetVersion = config.ConsensusV8
 
 type Upgrader struct {
 	config           *config.Config
@@ -11,6 +11,9 @@ import (
 	"time"
 )
 
+// epochDbPrefix + epoch is the prefix of the epoch db keys
+var epochDbPrefix = []byte("epoch")
+
 var (
 	OwnShortAnswerKey     = []byte("own-short")
 	AnswerHashPrefix      = []byte("hash")
@@ -55,7 +58,7 @@ type DbLotteryIdentity struct {
 }
 
 func NewEpochDb(db dbm.DB, epoch uint16) *EpochDb {
-	prefix := append([]byte("epoch"), uint8(epoch>>8), uint8(epoch&0xff))
+	prefix := append(common.CopyBytes(epochDbPrefix), uint8(epoch>>8), uint8(epoch&0xff))
 	return &EpochDb{db: dbm.NewPrefixDB(db, prefix)}
 }
 
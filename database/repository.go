@@ -1,13 +1,16 @@
 package database
 
 import (
+	"bytes"
 	"encoding/binary"
 	"github.com/golang/protobuf/proto"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/math"
+	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/log"
 	models "github.com/idena-network/idena-go/protobuf"
+	"github.com/pkg/errors"
 	dbm "github.com/tendermint/tm-db"
 	math2 "math"
 	"math/big"
@@ -16,7 +19,8 @@ import (
 
 const (
 	MaxWeakCertificatesCount = 100
-	maxEventName             = "\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F\u007F"
+	// MaxIndexedEventArgs is the number of the first event args which can be used to filter the events
+	MaxIndexedEventArgs = 4
 )
 
 type Repo struct {
@@ -73,12 +77,30 @@ func savedTxKey(sender common.Address, timestamp int64, nonce uint32, hash commo
 	return append(key, hash[:]...)
 }
 
-func savedEventKey(contact common.Address, txHash []byte, idx uint32, event string) []byte {
-	key := append(eventPrefix, contact.Bytes()...)
-	key = append(key, txHash...)
-	key = append(key, common.ToBytes(idx)...)
+// eventPosition = height + receipt index + event index
+func eventPosition(height uint64, receiptIdx uint32, idx uint32) []byte {
+	key := append(encodeUint64Number(height), encodeUint32Number(receiptIdx)...)
+	return append(key, encodeUint32Number(idx)...)
+}
+
+func eventIndexKey(contract common.Address, position []byte) []byte {
+	key := append(common.CopyBytes(eventIndexPrefix), contract.Bytes()...)
+	return append(key, position...)
+}
+
+func eventNameIndexKey(contract common.Address, event string, position []byte) []byte {
+	key := append(common.CopyBytes(eventNameIndexPrefix), contract.Bytes()...)
+	key = append(key, byte(len(event)))
 	key = append(key, []byte(event)...)
-	return key
+	return append(key, position...)
+}
+
+func eventArgIndexKey(contract common.Address, argIdx int, arg []byte, position []byte) []byte {
+	key := append(common.CopyBytes(eventArgIndexPrefix), contract.Bytes()...)
+	key = append(key, byte(argIdx))
+	argHash := crypto.Hash(arg)
+	key = append(key, argHash[:]...)
+	return append(key, position...)
 }
 
 func burntCoinsKey(height uint64, hash common.Hash) []byte {
@@ -434,6 +456,18 @@ func (r *Repo) GetSavedTxs(address common.Address, count int, token []byte) (txs
 	return txs, nil
 }
 
+func (r *Repo) WriteFullTxIndexHeight(height uint64) {
+	r.db.Set(fullTxIndexHeightKey, common.ToBytes(height))
+}
+
+func (r *Repo) ReadFullTxIndexHeight() uint64 {
+	data, err := r.db.Get(fullTxIndexHeightKey)
+	if err != nil || len(data) == 0 {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(data)
+}
+
 func (r *Repo) DeleteOutdatedBurntCoins(blockHeight uint64, blockRange uint64) {
 	if blockHeight <= blockRange {
 		return
@@ -500,35 +534,180 @@ func (r *Repo) GetTotalBurntCoins() []*types.BurntCoins {
 	return res
 }
 
-func (r *Repo) WriteEvent(contract common.Address, txHash common.Hash, idx uint32, event *types.TxEvent) {
+// EventFilter selects indexed events of the contract
+type EventFilter struct {
+	Contract common.Address
+	// Event is the event name, empty name matches all the events
+	Event     string
+	FromBlock uint64
+	// ToBlock is inclusive, 0 means the latest block
+	ToBlock uint64
+	// Args are values of the first MaxIndexedEventArgs event args by their positions, nil value matches any arg
+	Args [][]byte
+}
+
+// WriteIndexedEvent saves the event emitted at the given height, position of the event in the block is the index of
+// the tx receipt and the index of the event in the receipt
+func (r *Repo) WriteIndexedEvent(height uint64, receiptIdx uint32, idx uint32, txHash common.Hash, event *types.TxEvent) {
 	e := types.SavedEvent{
-		Contract: contract,
+		Contract: event.Contract,
 		Event:    event.EventName,
 		Args:     event.Data,
+		TxHash:   txHash,
+		Height:   height,
 	}
 	data, err := e.ToBytes()
 	if err != nil {
 		log.Crit("failed to proto encode saved event", "err", err)
 		return
 	}
-	r.db.Set(savedEventKey(contract, txHash.Bytes(), idx, event.EventName), data)
+	position := eventPosition(height, receiptIdx, idx)
+	r.db.Set(eventIndexKey(event.Contract, position), data)
+	r.db.Set(eventNameIndexKey(event.Contract, event.EventName, position), []byte{})
+	for i := 0; i < len(event.Data) && i < MaxIndexedEventArgs; i++ {
+		r.db.Set(eventArgIndexKey(event.Contract, i, event.Data[i], position), []byte{})
+	}
 }
 
-func (r *Repo) GetSavedEvents(contract common.Address) (events []*types.SavedEvent) {
+// GetIndexedEvents returns up to count events matching the filter ordered by their positions in the chain. The returned
+// token points to the next matching event and can be passed to continue the iteration.
+func (r *Repo) GetIndexedEvents(filter *EventFilter, count int, token []byte) (events []*types.SavedEvent, nextToken []byte, err error) {
+	if len(filter.Args) > MaxIndexedEventArgs {
+		return nil, nil, errors.Errorf("only the first %v event args can be filtered", MaxIndexedEventArgs)
+	}
+	to := filter.ToBlock
+	if to == 0 {
+		to = math2.MaxUint64
+	}
+	start, end := eventPosition(filter.FromBlock, 0, 0), append(eventPosition(to, math2.MaxUint32, math2.MaxUint32), 0)
+	if len(token) == len(start) && bytes.Compare(token, start) > 0 {
+		start = token
+	}
 
-	it, err := r.db.ReverseIterator(savedEventKey(contract, common.MinHash[:], 0, "\x00"), savedEventKey(contract, common.MaxHash, math2.MaxUint32, maxEventName))
+	// the narrowest index is iterated, the other conditions are checked by their index keys
+	var prefix []byte
+	var conditions []func(position []byte) []byte
+	for i, arg := range filter.Args {
+		if arg == nil {
+			continue
+		}
+		argIdx, arg := i, arg
+		if prefix == nil {
+			prefix = eventArgIndexKey(filter.Contract, argIdx, arg, nil)
+			continue
+		}
+		conditions = append(conditions, func(position []byte) []byte {
+			return eventArgIndexKey(filter.Contract, argIdx, arg, position)
+		})
+	}
+	if filter.Event != "" {
+		if prefix == nil {
+			prefix = eventNameIndexKey(filter.Contract, filter.Event, nil)
+		} else {
+			conditions = append(conditions, func(position []byte) []byte {
+				return eventNameIndexKey(filter.Contract, filter.Event, position)
+			})
+		}
+	}
+	indexed := prefix != nil
+	if !indexed {
+		prefix = eventIndexKey(filter.Contract, nil)
+	}
+
+	it, err := r.db.Iterator(append(common.CopyBytes(prefix), start...), append(common.CopyBytes(prefix), end...))
 	assertNoError(err)
 	defer it.Close()
 	for ; it.Valid(); it.Next() {
 		key, value := it.Key(), it.Value()
+		position := key[len(prefix):]
+		if !r.hasEventKeys(position, conditions) {
+			continue
+		}
+		if len(events) == count {
+			return events, common.CopyBytes(position), nil
+		}
+		if indexed {
+			value, err = r.db.Get(eventIndexKey(filter.Contract, position))
+			assertNoError(err)
+		}
 		e := new(types.SavedEvent)
 		if err := e.FromBytes(value); err != nil {
-			log.Error("cannot parse tx", "key", key)
+			log.Error("cannot parse event", "key", key)
 			continue
 		}
 		events = append(events, e)
 	}
-	return events
+	return events, nil, nil
+}
+
+func (r *Repo) hasEventKeys(position []byte, keys []func(position []byte) []byte) bool {
+	for _, key := range keys {
+		has, err := r.db.Has(key(position))
+		assertNoError(err)
+		if !has {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteLegacyEvents removes the events saved before the indexed events were introduced, it is done once
+func (r *Repo) DeleteLegacyEvents() {
+	deleted, err := r.db.Has(legacyEventsDeletedKey)
+	assertNoError(err)
+	if deleted {
+		return
+	}
+	// the epoch db keys start with legacyEventPrefix too, so their range is skipped
+	epochDbEnd := common.CopyBytes(epochDbPrefix)
+	epochDbEnd[len(epochDbEnd)-1]++
+	ranges := [][2][]byte{
+		{legacyEventPrefix, epochDbPrefix},
+		{epochDbEnd, {legacyEventPrefix[0] + 1}},
+	}
+	var keys [][]byte
+	for _, keyRange := range ranges {
+		it, err := r.db.Iterator(keyRange[0], keyRange[1])
+		assertNoError(err)
+		for ; it.Valid(); it.Next() {
+			if isLegacyEvent(it.Key(), it.Value()) {
+				keys = append(keys, common.CopyBytes(it.Key()))
+			}
+		}
+		it.Close()
+	}
+
+	batch := r.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	batch.Set(legacyEventsDeletedKey, []byte{0x1})
+	assertNoError(batch.WriteSync())
+	if len(keys) > 0 {
+		log.Info("Legacy contract events are deleted", "count", len(keys))
+	}
+}
+
+// isLegacyEvent checks that the key has the layout legacyEventPrefix + contract + tx hash + event index + event name
+// and the value is the event saved with this key, the legacy events have neither tx hash nor height in the value
+func isLegacyEvent(key, value []byte) bool {
+	const nameOffset = 1 + common.AddressLength + common.HashLength + 4
+	if len(key) <= nameOffset || !bytes.HasPrefix(key, legacyEventPrefix) {
+		return false
+	}
+	name := key[nameOffset:]
+	for _, c := range name {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	e := new(types.SavedEvent)
+	if err := e.FromBytes(value); err != nil {
+		return false
+	}
+	return bytes.Equal(e.Contract.Bytes(), key[1:1+common.AddressLength]) && e.Event == string(name) &&
+		e.TxHash == (common.Hash{}) && e.Height == 0
 }
 
 func (r *Repo) WriteIntermediateGenesis(batch dbm.Batch, height uint64) {
@@ -617,3 +796,44 @@ func (r *Repo) RemovePreliminaryIntermediateGenesis(batch dbm.Batch) {
 		r.db.Delete(preliminaryIntermediateGenesisKey)
 	}
 }
+
+func peerScoreKey(id string) []byte {
+	return append(common.CopyBytes(peerScorePrefix), []byte(id)...)
+}
+
+func (r *Repo) WritePeerScore(batch dbm.Batch, id string, score float64, updated int64) {
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint64(data, math2.Float64bits(score))
+	binary.LittleEndian.PutUint64(data[8:], uint64(updated))
+	if batch != nil {
+		assertNoError(batch.Set(peerScoreKey(id), data))
+	} else {
+		assertNoError(r.db.Set(peerScoreKey(id), data))
+	}
+}
+
+func (r *Repo) DeletePeerScore(batch dbm.Batch, id string) {
+	if batch != nil {
+		assertNoError(batch.Delete(peerScoreKey(id)))
+	} else {
+		assertNoError(r.db.Delete(peerScoreKey(id)))
+	}
+}
+
+// IteratePeerScores calls f for each saved peer score, updated is the unix time of the score update
+func (r *Repo) IteratePeerScores(f func(id string, score float64, updated int64)) {
+	end := common.CopyBytes(peerScorePrefix)
+	end[len(end)-1]++
+	it, err := r.db.Iterator(peerScorePrefix, end)
+	assertNoError(err)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		if len(value) != 16 {
+			log.Error("invalid peer score", "key", key)
+			continue
+		}
+		f(string(key[len(peerScorePrefix):]), math2.Float64frombits(binary.LittleEndian.Uint64(value)),
+			int64(binary.LittleEndian.Uint64(value[8:])))
+	}
+}
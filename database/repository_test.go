@@ -74,7 +74,7 @@ func TestRepo_WritActivityMonitor(t *testing.T) {
 	require.Equal(monitor.Data[0].Time.Unix(), readActivity.Data[0].Time.Unix())
 }
 
-func TestRepo_GetSavedEvents(t *testing.T) {
+func TestRepo_GetIndexedEvents(t *testing.T) {
 	database := db.NewMemDB()
 	repo := NewRepo(database)
 
@@ -83,42 +83,171 @@ func TestRepo_GetSavedEvents(t *testing.T) {
 
 	tx1 := common.Hash{0x1}
 	tx2 := common.Hash{0x2}
+	tx3 := common.Hash{0x3}
 
-	repo.WriteEvent(addr1, tx1, 1, &types.TxEvent{
+	repo.WriteIndexedEvent(10, 0, 0, tx1, &types.TxEvent{
+		Contract:  addr1,
 		EventName: "event2",
-		Data:      [][]byte{{0x1}},
+		Data:      [][]byte{{0x1, 0x2}},
 	})
-	repo.WriteEvent(addr1, tx1, 2, &types.TxEvent{
+	repo.WriteIndexedEvent(10, 0, 1, tx1, &types.TxEvent{
+		Contract:  addr1,
 		EventName: "event1",
-		Data:      [][]byte{{0x1}},
+		Data:      [][]byte{{0x1, 0x3}, {0x5}},
 	})
-	repo.WriteEvent(addr1, tx1, 3, &types.TxEvent{
+	repo.WriteIndexedEvent(10, 1, 0, tx2, &types.TxEvent{
+		Contract:  addr2,
 		EventName: "event1",
 		Data:      [][]byte{{0x1}},
 	})
-	repo.WriteEvent(addr2, tx2, 1, &types.TxEvent{
-		EventName: "ZZZZZZZZZZZZZZZ ZZZZZZZZZZZZZZZZZZ",
-		Data:      [][]byte{{0x1}},
-	})
-	repo.WriteEvent(addr2, tx2, 2, &types.TxEvent{
-		EventName: "e",
-		Data:      [][]byte{{0x1}},
-	})
-	repo.WriteEvent(addr2, tx2, 3, &types.TxEvent{
-		EventName: "ev2",
-		Data:      [][]byte{{0x1}},
+	repo.WriteIndexedEvent(12, 0, 0, tx3, &types.TxEvent{
+		Contract:  addr1,
+		EventName: "event1",
+		Data:      [][]byte{{0x2}, {0x5}},
 	})
 
-	events1 := repo.GetSavedEvents(addr1)
-	events2 := repo.GetSavedEvents(addr2)
-	require.Len(t, events1, 3)
-	require.Len(t, events2, 3)
+	events, token, err := repo.GetIndexedEvents(&EventFilter{Contract: addr1}, 10, nil)
+	require.NoError(t, err)
+	require.Nil(t, token)
+	require.Len(t, events, 3)
+	require.Equal(t, "event2", events[0].Event)
+	require.Equal(t, "event1", events[1].Event)
+	require.Equal(t, tx3, events[2].TxHash)
+	require.Equal(t, uint64(12), events[2].Height)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr2}, 10, nil)
+	require.Len(t, events, 1)
+	require.Equal(t, addr2, events[0].Contract)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Event: "event1"}, 10, nil)
+	require.Len(t, events, 2)
+	require.Equal(t, tx1, events[0].TxHash)
+	require.Equal(t, tx3, events[1].TxHash)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, FromBlock: 11}, 10, nil)
+	require.Len(t, events, 1)
+	require.Equal(t, tx3, events[0].TxHash)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, ToBlock: 10}, 10, nil)
+	require.Len(t, events, 2)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: [][]byte{{0x1, 0x3}, {0x5}}}, 10, nil)
+	require.Len(t, events, 1)
+	require.Equal(t, "event1", events[0].Event)
+	require.Equal(t, tx1, events[0].TxHash)
+
+	// arg values are matched exactly
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: [][]byte{{0x1}}}, 10, nil)
+	require.Empty(t, events)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: [][]byte{nil, {0x5}}}, 10, nil)
+	require.Len(t, events, 2)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Event: "event2", Args: [][]byte{nil, {0x5}}}, 10, nil)
+	require.Empty(t, events)
+
+	events, _, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Event: "event1", Args: [][]byte{{0x2}}}, 10, nil)
+	require.Len(t, events, 1)
+	require.Equal(t, tx3, events[0].TxHash)
+
+	_, _, err = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: make([][]byte, MaxIndexedEventArgs+1)}, 10, nil)
+	require.Error(t, err)
+
+	events, token, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1}, 2, nil)
+	require.Len(t, events, 2)
+	require.NotNil(t, token)
+	events, token, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1}, 2, token)
+	require.Nil(t, token)
+	require.Len(t, events, 1)
+	require.Equal(t, tx3, events[0].TxHash)
+
+	events, token, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: [][]byte{nil, {0x5}}}, 1, nil)
+	require.Len(t, events, 1)
+	require.Equal(t, tx1, events[0].TxHash)
+	events, token, _ = repo.GetIndexedEvents(&EventFilter{Contract: addr1, Args: [][]byte{nil, {0x5}}}, 1, token)
+	require.Nil(t, token)
+	require.Len(t, events, 1)
+	require.Equal(t, tx3, events[0].TxHash)
+}
 
-	require.Equal(t, "event1", events1[0].Event)
-	require.Equal(t, "event1", events1[1].Event)
-	require.Equal(t, "event2", events1[2].Event)
-	require.Equal(t, "ev2", events2[0].Event)
-	require.Equal(t, "e", events2[1].Event)
-	require.Equal(t, "ZZZZZZZZZZZZZZZ ZZZZZZZZZZZZZZZZZZ", events2[2].Event)
+func TestRepo_DeleteLegacyEvents(t *testing.T) {
+	database := db.NewMemDB()
+	repo := NewRepo(database)
 
+	contract := common.Address{0x1}
+	legacy := types.SavedEvent{Contract: contract, Event: "transfer"}
+	data, _ := legacy.ToBytes()
+	legacyKey := append(common.CopyBytes(legacyEventPrefix), contract.Bytes()...)
+	legacyKey = append(legacyKey, common.Hash{0x2}.Bytes()...)
+	legacyKey = append(legacyKey, common.ToBytes(uint32(1))...)
+	legacyKey = append(legacyKey, []byte("transfer")...)
+	require.NoError(t, database.Set(legacyKey, data))
+
+	// the epoch db data is kept even if it looks like a legacy event
+	epochDb := NewEpochDb(database, 1)
+	epochDb.WriteAnswerHash(contract, common.Hash{0x3}, time.Unix(10, 0))
+	epochDb.WriteEvidenceMap(contract, []byte{0x1, 0x2})
+	epochDb.WriteLotterySeed([]byte{0x4})
+	epochDb.WriteOwnTx(1, []byte{0x5})
+	epochKey := append(append(append([]byte("epoch"), 0x0, 0x1), make([]byte, 50)...), []byte("answers")...)
+	var epochContract common.Address
+	epochContract.SetBytes(epochKey[1 : 1+common.AddressLength])
+	epochName := string(epochKey[1+common.AddressLength+common.HashLength+4:])
+	epochValue, _ := (&types.SavedEvent{Contract: epochContract, Event: epochName}).ToBytes()
+	require.NoError(t, database.Set(epochKey, epochValue))
+
+	// keys which don't have the legacy layout are kept
+	shortKey := common.CopyBytes(legacyKey[:len(legacyKey)-len("transfer")])
+	require.NoError(t, database.Set(shortKey, data))
+	indexedValue, _ := (&types.SavedEvent{Contract: contract, Event: "transfer", TxHash: common.Hash{0x2}, Height: 1}).ToBytes()
+	indexedKey := append(common.CopyBytes(shortKey), []byte("transfer2")...)
+	require.NoError(t, database.Set(indexedKey, indexedValue))
+
+	repo.WriteIndexedEvent(1, 0, 0, common.Hash{0x2}, &types.TxEvent{Contract: contract, EventName: "transfer"})
+
+	repo.DeleteLegacyEvents()
+
+	has, _ := database.Has(legacyKey)
+	require.False(t, has)
+	for _, key := range [][]byte{epochKey, shortKey, indexedKey} {
+		has, _ = database.Has(key)
+		require.True(t, has)
+	}
+	require.Equal(t, map[common.Address]common.Hash{contract: {0x3}}, epochDb.GetAnswers())
+	require.True(t, epochDb.HasEvidenceMap(contract))
+	require.Equal(t, []byte{0x4}, epochDb.ReadLotterySeed())
+	require.Equal(t, []byte{0x5}, epochDb.ReadOwnTx(1))
+	events, _, _ := repo.GetIndexedEvents(&EventFilter{Contract: contract}, 10, nil)
+	require.Len(t, events, 1)
+
+	// the legacy events are deleted once
+	require.NoError(t, database.Set(legacyKey, data))
+	repo.DeleteLegacyEvents()
+	has, _ = database.Has(legacyKey)
+	require.True(t, has)
+}
+
+func TestRepo_PeerScores(t *testing.T) {
+	database := db.NewMemDB()
+	repo := NewRepo(database)
+
+	repo.WritePeerScore(nil, "peer1", -12.5, 100)
+	repo.WritePeerScore(nil, "peer2", 3, 200)
+	repo.WritePeerScore(nil, "peer1", -20, 300)
+
+	scores := make(map[string]float64)
+	updates := make(map[string]int64)
+	repo.IteratePeerScores(func(id string, score float64, updated int64) {
+		scores[id] = score
+		updates[id] = updated
+	})
+	require.Equal(t, map[string]float64{"peer1": -20, "peer2": 3}, scores)
+	require.Equal(t, map[string]int64{"peer1": 300, "peer2": 200}, updates)
+
+	repo.DeletePeerScore(nil, "peer1")
+	scores = make(map[string]float64)
+	repo.IteratePeerScores(func(id string, score float64, updated int64) {
+		scores[id] = score
+	})
+	require.Equal(t, map[string]float64{"peer2": 3}, scores)
 }
@@ -34,7 +34,20 @@ var (
 
 	activityMonitorKey = []byte("activity")
 
-	eventPrefix = []byte("e")
+	// eventIndexPrefix + contract + event position -> event
+	eventIndexPrefix = []byte("xe")
+
+	// eventNameIndexPrefix + contract + event name length + event name + event position -> nothing
+	eventNameIndexPrefix = []byte("xn")
+
+	// eventArgIndexPrefix + contract + arg index + arg hash + event position -> nothing
+	eventArgIndexPrefix = []byte("xa")
+
+	// legacyEventPrefix + contract + tx hash + event index + event name -> event, saved before the indexed events
+	legacyEventPrefix = []byte("e")
+
+	// legacyEventsDeletedKey is set once the events saved with legacyEventPrefix are deleted
+	legacyEventsDeletedKey = []byte("led")
 
 	intermediateGenesisKey = []byte("g")
 
@@ -45,4 +58,10 @@ var (
 	consensusVersionKey = []byte("v")
 
 	preliminaryConsVersionKey = []byte("pv")
+
+	// fullTxIndexHeightKey tracks the height up to which transactions of all the addresses are saved
+	fullTxIndexHeightKey = []byte("fti")
+
+	// peerScorePrefix + peer id -> score + unix time of the score update
+	peerScorePrefix = []byte("ps")
 )
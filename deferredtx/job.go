@@ -176,8 +176,8 @@ func (j *Job) sendTx(dtx *DeferredTx) error {
 		return err
 	}
 
-	vm := j.vmCreator(readonlyAppState, j.head, nil, j.bc.Config())
-	r := vm.Run(tx, -1)
+	contractVm := j.vmCreator(readonlyAppState, j.head, nil, j.bc.Config())
+	r := contractVm.Run(tx, vm.ReadGasLimit)
 	if r.Error != nil {
 		return r.Error
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/stats/collector"
 	"github.com/idena-network/idena-go/vm"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/embedded"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,9 @@ type fakeVm struct {
 func (f fakeVm) Read(contractAddr common.Address, method string, args ...[]byte) ([]byte, error) {
 	panic("implement me")
 }
+func (f fakeVm) Abi(codeHash common.Hash) (*abi.Abi, error) {
+	panic("implement me")
+}
 func (f fakeVm) Run(tx *types.Transaction, gasLimit int64) *types.TxReceipt {
 	return &types.TxReceipt{
 		Error:   fakeVmError,
@@ -35,7 +35,8 @@ func (e *NewTxEvent) EventID() eventbus.EventID {
 }
 
 type NewBlockEvent struct {
-	Block *types.Block
+	Block    *types.Block
+	Receipts types.TxReceipts
 }
 
 func (e *NewBlockEvent) EventID() eventbus.EventID {
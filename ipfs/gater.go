@@ -0,0 +1,83 @@
+package ipfs
+
+import (
+	ipfslibp2p "github.com/ipfs/go-ipfs/core/node/libp2p"
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	libp2pcfg "github.com/libp2p/go-libp2p/config"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Gater restricts libp2p connections of the ipfs node, its private peers are hidden from the dht
+type Gater interface {
+	connmgr.ConnectionGater
+	IsPrivate(id peer.ID) bool
+}
+
+// gatedHostOption creates the libp2p host with the gater added to the connection gater configured by ipfs, a
+// connection is allowed only if both gaters allow it
+func gatedHostOption(gater connmgr.ConnectionGater) ipfslibp2p.HostOption {
+	return func(id peer.ID, ps peerstore.Peerstore, opts ...libp2pcfg.Option) (host.Host, error) {
+		opts = append(opts, func(cfg *libp2pcfg.Config) error {
+			if cfg.ConnectionGater == nil {
+				cfg.ConnectionGater = gater
+			} else {
+				cfg.ConnectionGater = gaters{cfg.ConnectionGater, gater}
+			}
+			return nil
+		})
+		return ipfslibp2p.DefaultHostOption(id, ps, opts...)
+	}
+}
+
+// gaters allows a connection if all the gaters allow it
+type gaters []connmgr.ConnectionGater
+
+func (g gaters) InterceptPeerDial(id peer.ID) bool {
+	for _, gater := range g {
+		if !gater.InterceptPeerDial(id) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g gaters) InterceptAddrDial(id peer.ID, addr multiaddr.Multiaddr) bool {
+	for _, gater := range g {
+		if !gater.InterceptAddrDial(id, addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g gaters) InterceptAccept(addrs network.ConnMultiaddrs) bool {
+	for _, gater := range g {
+		if !gater.InterceptAccept(addrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g gaters) InterceptSecured(dir network.Direction, id peer.ID, addrs network.ConnMultiaddrs) bool {
+	for _, gater := range g {
+		if !gater.InterceptSecured(dir, id, addrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g gaters) InterceptUpgraded(conn network.Conn) (bool, control.DisconnectReason) {
+	for _, gater := range g {
+		if allow, reason := gater.InterceptUpgraded(conn); !allow {
+			return false, reason
+		}
+	}
+	return true, 0
+}
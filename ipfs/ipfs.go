@@ -22,6 +22,7 @@ import (
 	"github.com/ipfs/go-ipfs/core/coreunix"
 	"github.com/ipfs/go-ipfs/plugin/loader"
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	ipld "github.com/ipfs/go-ipld-format"
 	dag "github.com/ipfs/go-merkledag"
 	dagtest "github.com/ipfs/go-merkledag/test"
 	"github.com/ipfs/go-mfs"
@@ -67,6 +68,8 @@ var (
 	MinCid    [CidLength]byte
 	MaxCid    [CidLength]byte
 	TooBigErr = errors.New("ipfs data is too big")
+	// InvalidDataErr means that the loaded data does not match its cid or is not a file
+	InvalidDataErr = errors.New("ipfs data is invalid")
 )
 
 func init() {
@@ -81,7 +84,9 @@ func init() {
 type Proxy interface {
 	Add(data []byte, pin bool) (cid.Cid, error)
 	Get(key []byte, dataType DataType) ([]byte, error)
-	LoadTo(key []byte, to io.Writer, ctx context.Context, onLoading func(size, loaded int64)) error
+	// LoadChunksTo writes the file from the offset chunk by chunk, chunks before the offset are not loaded. Every written
+	// chunk is reported to onChunk with the offset of the chunk end and the size of the file.
+	LoadChunksTo(key []byte, offset int64, to io.Writer, ctx context.Context, onChunk func(loaded, size int64) error) error
 	Pin(key []byte) error
 	Unpin(key []byte) error
 	Cid(data []byte) (cid.Cid, error)
@@ -99,6 +104,7 @@ type ipfsProxy struct {
 	cidCache             *cache.Cache
 	rwLock               sync.RWMutex
 	cfg                  *config.IpfsConfig
+	gater                Gater
 	nodeCtx              context.Context
 	nodeCtxCancel        context.CancelFunc
 	nilNode              *core.IpfsNode
@@ -117,7 +123,9 @@ func (p *ipfsProxy) PubSub() *pubsub.PubSub {
 	return p.node.PubSub
 }
 
-func NewIpfsProxy(cfg *config.IpfsConfig, bus eventbus.Bus) (Proxy, error) {
+// NewIpfsProxy starts the ipfs node, gater restricts libp2p connections of the node and hides its private peers if it
+// is not nil
+func NewIpfsProxy(cfg *config.IpfsConfig, gater Gater, bus eventbus.Bus) (Proxy, error) {
 	logging.SetLevel(0, "core")
 
 	err := loadPlugins(cfg)
@@ -128,7 +136,7 @@ func NewIpfsProxy(cfg *config.IpfsConfig, bus eventbus.Bus) (Proxy, error) {
 
 	logger := log.New()
 
-	node, ctx, cancelCtx, err := createNode(cfg)
+	node, ctx, cancelCtx, err := createNode(cfg, gater)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +155,7 @@ func NewIpfsProxy(cfg *config.IpfsConfig, bus eventbus.Bus) (Proxy, error) {
 		node:                 node,
 		log:                  logger,
 		cfg:                  cfg,
+		gater:                gater,
 		cidCache:             c,
 		nodeCtx:              ctx,
 		nodeCtxCancel:        cancelCtx,
@@ -160,7 +169,7 @@ func NewIpfsProxy(cfg *config.IpfsConfig, bus eventbus.Bus) (Proxy, error) {
 	return p, nil
 }
 
-func createNode(cfg *config.IpfsConfig) (*core.IpfsNode, context.Context, context.CancelFunc, error) {
+func createNode(cfg *config.IpfsConfig, gater Gater) (*core.IpfsNode, context.Context, context.CancelFunc, error) {
 	dataDir, _ := filepath.Abs(cfg.DataDir)
 
 	if ln, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.IpfsPort)); err == nil {
@@ -176,7 +185,12 @@ func createNode(cfg *config.IpfsConfig) (*core.IpfsNode, context.Context, contex
 
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
-	node, err := core.NewNode(ctx, getNodeConfig(dataDir))
+	nodeConfig := getNodeConfig(dataDir)
+	nodeConfig.Routing = routingOption(cfg.Routing, gater)
+	if gater != nil {
+		nodeConfig.Host = gatedHostOption(gater)
+	}
+	node, err := core.NewNode(ctx, nodeConfig)
 	if err != nil {
 		cancelCtx()
 		return nil, nil, func() {}, err
@@ -242,7 +256,7 @@ func (p *ipfsProxy) changePort() {
 	for {
 		p.cfg.IpfsPort += 1
 
-		node, ctx, cancelCtx, err := createNode(p.cfg)
+		node, ctx, cancelCtx, err := createNode(p.cfg, p.gater)
 
 		if err != nil {
 			continue
@@ -463,41 +477,93 @@ func (p *ipfsProxy) get(path path.Path, dataType DataType, maxSize int64) ([]byt
 	return buf.Bytes(), nil
 }
 
-func (p *ipfsProxy) LoadTo(key []byte, to io.Writer, ctx context.Context, onLoading func(size, loaded int64)) error {
-	if len(key) == 0 {
-		return nil
-	}
+func (p *ipfsProxy) LoadChunksTo(key []byte, offset int64, to io.Writer, ctx context.Context, onChunk func(loaded, size int64) error) error {
 	c, err := cid.Cast(key)
 	if err != nil {
 		return err
 	}
-	if c == EmptyCid {
-		return nil
-	}
 
 	p.rwLock.RLock()
 	defer p.rwLock.RUnlock()
 
 	p.cancelGc()
 
-	api, _ := coreapi.NewCoreAPI(p.node)
-
-	f, err := api.Unixfs().Get(ctx, path.IpfsPath(c))
-	select {
-	case <-ctx.Done():
-		return errors.New("ipfs load: context canceled")
-	default:
-		break
+	var size int64
+	loaded := offset
+	write := func(data []byte, start int64) error {
+		if end := start + int64(len(data)); end <= offset {
+			return nil
+		}
+		if start < offset {
+			data = data[offset-start:]
+		}
+		if _, err := to.Write(data); err != nil {
+			return err
+		}
+		loaded += int64(len(data))
+		return onChunk(loaded, size)
+	}
+	// walk writes the content of the node which starts at the start offset of the file, children are skipped by their
+	// block sizes, so chunks before the offset are never fetched
+	var walk func(nd ipld.Node, start int64) error
+	walk = func(nd ipld.Node, start int64) error {
+		switch n := nd.(type) {
+		case *dag.RawNode:
+			return write(n.RawData(), start)
+		case *dag.ProtoNode:
+			fsNode, err := ft.FSNodeFromBytes(n.Data())
+			if err != nil {
+				return errors.Wrap(InvalidDataErr, err.Error())
+			}
+			if len(fsNode.Data()) > 0 {
+				if err := write(fsNode.Data(), start); err != nil {
+					return err
+				}
+				start += int64(len(fsNode.Data()))
+			}
+			if len(n.Links()) != fsNode.NumChildren() {
+				return errors.Wrap(InvalidDataErr, "links do not match block sizes")
+			}
+			for i, l := range n.Links() {
+				childSize := int64(fsNode.BlockSize(i))
+				if start+childSize > offset {
+					child, err := p.node.DAG.Get(ctx, l.Cid)
+					if err != nil {
+						return err
+					}
+					if err := walk(child, start); err != nil {
+						return err
+					}
+				}
+				start += childSize
+			}
+			return nil
+		default:
+			return errors.Wrapf(InvalidDataErr, "unexpected node type %T", nd)
+		}
 	}
-	file := files.ToFile(f)
-	defer file.Close()
 
-	size, err := file.Size()
+	nd, err := p.node.DAG.Get(ctx, c)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(to, &progressReader{r: file, size: size, onLoading: onLoading})
-	return err
+	switch n := nd.(type) {
+	case *dag.RawNode:
+		size = int64(len(n.RawData()))
+	case *dag.ProtoNode:
+		fsNode, err := ft.FSNodeFromBytes(n.Data())
+		if err != nil {
+			return errors.Wrap(InvalidDataErr, err.Error())
+		}
+		size = int64(fsNode.FileSize())
+	}
+	if err := walk(nd, 0); err != nil {
+		return err
+	}
+	if loaded != size {
+		return errors.Wrapf(InvalidDataErr, "loaded %v bytes of %v", loaded, size)
+	}
+	return nil
 }
 
 func (p *ipfsProxy) Pin(key []byte) error {
@@ -764,6 +830,9 @@ func loadPlugins(cfg *config.IpfsConfig) error {
 	return nil
 }
 
+// memoryChunkSize is the chunk size of files loaded from the memory ipfs
+const memoryChunkSize = 1024
+
 func NewMemoryIpfsProxy() Proxy {
 	return &memoryIpfs{
 		values: make(map[cid.Cid][]byte),
@@ -786,8 +855,43 @@ func (i *memoryIpfs) Host() core2.Host {
 	panic("implement me")
 }
 
-func (i *memoryIpfs) LoadTo(key []byte, to io.Writer, ctx context.Context, onLoading func(size, loaded int64)) error {
-	panic("implement me")
+// LoadChunksTo splits the value into chunks of memoryChunkSize bytes, the value is verified against the cid before
+// the first chunk is written
+func (i *memoryIpfs) LoadChunksTo(key []byte, offset int64, to io.Writer, ctx context.Context, onChunk func(loaded, size int64) error) error {
+	c, err := cid.Cast(key)
+	if err != nil {
+		return err
+	}
+	data, ok := i.values[c]
+	if !ok {
+		return errors.New("not found")
+	}
+	if expected, _ := c.Prefix().Sum(data); !expected.Equals(c) {
+		return errors.Wrapf(InvalidDataErr, "cid %v", c.String())
+	}
+	size := int64(len(data))
+	if offset > size {
+		return errors.Wrapf(InvalidDataErr, "offset %v exceeds size %v", offset, size)
+	}
+	for loaded := offset; loaded < size; {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		end := (loaded/memoryChunkSize + 1) * memoryChunkSize
+		if end > size {
+			end = size
+		}
+		if _, err := to.Write(data[loaded:end]); err != nil {
+			return err
+		}
+		loaded = end
+		if err := onChunk(loaded, size); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (i *memoryIpfs) AddFile(absPath string, data io.ReadCloser, fi os.FileInfo) (cid.Cid, error) {
@@ -843,21 +947,3 @@ func (*memoryIpfs) Cid(data []byte) (cid.Cid, error) {
 	}
 	return v1CidPrefix.Sum(data)
 }
-
-type progressReader struct {
-	r         io.Reader
-	read      int
-	size      int64
-	onLoading func(size, loaded int64)
-}
-
-func (r *progressReader) Read(p []byte) (n int, err error) {
-	n, err = r.r.Read(p)
-	if err == nil {
-		r.read += n
-		if r.onLoading != nil {
-			r.onLoading(r.size, int64(r.read))
-		}
-	}
-	return n, err
-}
@@ -1,10 +1,13 @@
 package ipfs
 
 import (
+	"bytes"
+	"context"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
 	"github.com/idena-network/idena-go/config"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -19,7 +22,7 @@ func init() {
 		IpfsPort:    4012,
 		DataDir:     "./datadir-ipfs",
 		GracePeriod: "20s",
-	}, eventbus.New())
+	}, nil, eventbus.New())
 	if err != nil {
 		panic(err)
 	}
@@ -111,3 +114,28 @@ func TestIpfsProxy_Get_Limit(t *testing.T) {
 	_, err = proxy.Get(cid.Bytes(), Block)
 	require.NoError(err)
 }
+
+func TestMemoryIpfs_LoadChunksTo(t *testing.T) {
+	memory := NewMemoryIpfsProxy()
+	data := random.GetRandomBytes(2500)
+	c, _ := memory.Add(data, true)
+
+	var loaded []int64
+	to := new(bytes.Buffer)
+	err := memory.LoadChunksTo(c.Bytes(), 1000, to, context.Background(), func(chunkEnd, size int64) error {
+		require.Equal(t, int64(len(data)), size)
+		loaded = append(loaded, chunkEnd)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1024, 2048, 2500}, loaded)
+	require.Equal(t, data[1000:], to.Bytes())
+
+	data[100] ^= 0xff
+	to.Reset()
+	err = memory.LoadChunksTo(c.Bytes(), 0, to, context.Background(), func(chunkEnd, size int64) error {
+		return nil
+	})
+	require.True(t, errors.Is(err, InvalidDataErr))
+	require.Zero(t, to.Len())
+}
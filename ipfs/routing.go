@@ -0,0 +1,87 @@
+package ipfs
+
+import (
+	"context"
+	"github.com/ipfs/go-datastore"
+	ipfslibp2p "github.com/ipfs/go-ipfs/core/node/libp2p"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/routing"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/libp2p/go-libp2p-kad-dht/dual"
+	record "github.com/libp2p/go-libp2p-record"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// routingOption creates the routing of the node by the routing type of the config, the node config of ipfs ignores
+// the type otherwise. Private peers of the gater are never added to dht routing tables and their addresses are never
+// sent in dht responses, so a sentry does not reveal its validators.
+func routingOption(routingType string, gater Gater) ipfslibp2p.RoutingOption {
+	var mode dht.ModeOpt
+	switch routingType {
+	case "none":
+		return ipfslibp2p.NilRouterOption
+	case "dhtclient":
+		mode = dht.ModeClient
+	case "dhtserver":
+		mode = dht.ModeServer
+	default:
+		mode = dht.ModeAuto
+	}
+	return func(ctx context.Context, h host.Host, dstore datastore.Batching, validator record.Validator,
+		bootstrapPeers ...peer.AddrInfo) (routing.Routing, error) {
+		opts := []dual.Option{
+			dual.DHTOption(
+				dht.Concurrency(10),
+				dht.Mode(mode),
+				dht.Datastore(dstore),
+				dht.Validator(validator)),
+			dual.WanDHTOption(dht.BootstrapPeers(bootstrapPeers...)),
+		}
+		if gater != nil {
+			h = &privateHost{
+				Host: h,
+				ps:   &privatePeerstore{Peerstore: h.Peerstore(), isPrivate: gater.IsPrivate},
+			}
+			opts = append(opts,
+				dual.WanDHTOption(dht.RoutingTableFilter(func(d interface{}, p peer.ID) bool {
+					return !gater.IsPrivate(p) && dht.PublicRoutingTableFilter(d, p)
+				})),
+				dual.LanDHTOption(dht.RoutingTableFilter(func(d interface{}, p peer.ID) bool {
+					return !gater.IsPrivate(p) && dht.PrivateRoutingTableFilter(d, p)
+				})),
+			)
+		}
+		return dual.New(ctx, h, opts...)
+	}
+}
+
+// privateHost is the host of the dht which does not see addresses of private peers
+type privateHost struct {
+	host.Host
+	ps peerstore.Peerstore
+}
+
+func (h *privateHost) Peerstore() peerstore.Peerstore {
+	return h.ps
+}
+
+type privatePeerstore struct {
+	peerstore.Peerstore
+	isPrivate func(id peer.ID) bool
+}
+
+func (ps *privatePeerstore) Addrs(id peer.ID) []multiaddr.Multiaddr {
+	if ps.isPrivate(id) {
+		return nil
+	}
+	return ps.Peerstore.Addrs(id)
+}
+
+func (ps *privatePeerstore) PeerInfo(id peer.ID) peer.AddrInfo {
+	if ps.isPrivate(id) {
+		return peer.AddrInfo{ID: id}
+	}
+	return ps.Peerstore.PeerInfo(id)
+}
@@ -0,0 +1,83 @@
+package ipfs
+
+import (
+	"context"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/ipfs/go-ipns"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-kad-dht/dual"
+	"github.com/libp2p/go-libp2p-peerstore/pstoremem"
+	record "github.com/libp2p/go-libp2p-record"
+	routinghelpers "github.com/libp2p/go-libp2p-routing-helpers"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type testGater struct {
+	connmgr.ConnectionGater
+	private peer.ID
+}
+
+func (g *testGater) IsPrivate(id peer.ID) bool {
+	return id == g.private
+}
+
+func TestPrivatePeerstore(t *testing.T) {
+	ps, err := pstoremem.NewPeerstore()
+	require.NoError(t, err)
+	addr, _ := multiaddr.NewMultiaddr("/ip4/10.0.0.2/tcp/40405")
+	private, public := peer.ID("private"), peer.ID("public")
+	ps.AddAddr(private, addr, time.Hour)
+	ps.AddAddr(public, addr, time.Hour)
+
+	filtered := &privatePeerstore{Peerstore: ps, isPrivate: (&testGater{private: private}).IsPrivate}
+	require.Empty(t, filtered.Addrs(private))
+	require.Empty(t, filtered.PeerInfo(private).Addrs)
+	require.Equal(t, []multiaddr.Multiaddr{addr}, filtered.Addrs(public))
+	require.Equal(t, []multiaddr.Multiaddr{addr}, filtered.PeerInfo(public).Addrs)
+	require.Len(t, ps.Addrs(private), 1)
+}
+
+func TestRoutingOption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := routingOption("none", nil)(ctx, nil, nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, routinghelpers.Null{}, r)
+
+	newHost := func() host.Host {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			h.Close()
+		})
+		return h
+	}
+	newDht := func(h host.Host, gater Gater) *dual.DHT {
+		validator := record.NamespacedValidator{"pk": record.PublicKeyValidator{}, "ipns": ipns.Validator{}}
+		r, err := routingOption("dhtserver", gater)(ctx, h, dssync.MutexWrap(datastore.NewMapDatastore()), validator)
+		require.NoError(t, err)
+		return r.(*dual.DHT)
+	}
+	sentry, validator, public := newHost(), newHost(), newHost()
+	sentryDht := newDht(sentry, &testGater{private: validator.ID()})
+	newDht(validator, nil)
+	newDht(public, nil)
+
+	// the private peer of the sentry is connected but never gets into its routing table
+	for _, h := range []host.Host{validator, public} {
+		require.NoError(t, sentry.Connect(ctx, peer.AddrInfo{ID: h.ID(), Addrs: h.Addrs()}))
+	}
+	require.Eventually(t, func() bool {
+		return sentryDht.LAN.RoutingTable().Find(public.ID()) != ""
+	}, time.Second*5, time.Millisecond*50)
+	require.Empty(t, sentryDht.LAN.RoutingTable().Find(validator.ID()))
+	require.Empty(t, sentryDht.WAN.RoutingTable().Find(validator.ID()))
+}
@@ -33,6 +33,8 @@ func main() {
 		config.TcpPortFlag,
 		config.RpcHostFlag,
 		config.RpcPortFlag,
+		config.WsHostFlag,
+		config.WsPortFlag,
 		config.BootNodeFlag,
 		config.AutomineFlag,
 		config.IpfsBootNodeFlag,
@@ -43,6 +45,7 @@ func main() {
 		config.CeremonyTimeFlag,
 		config.MaxNetworkDelayFlag,
 		config.FastSyncFlag,
+		config.LightModeFlag,
 		config.ForceFullSyncFlag,
 		config.ProfileFlag,
 		config.IpfsPortStaticFlag,
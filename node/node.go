@@ -34,6 +34,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
@@ -55,6 +56,8 @@ type Node struct {
 	rpcAPIs         []rpc.API
 	httpListener    net.Listener // HTTP RPC listener socket to server API requests
 	httpHandler     *rpc.Server  // HTTP RPC request handler to process the API requests
+	keyring         *rpc.Keyring // API keys shared by all the RPC endpoints
+	rpcStats        *rpc.Stats   // calls statistics shared by all the RPC endpoints
 	log             log.Logger
 	keyStore        *keystore.KeyStore
 	fp              *flip.Flipper
@@ -70,6 +73,8 @@ type Node struct {
 	upgrader        *upgrade.Upgrader
 }
 
+const apiKeysReloadInterval = time.Second * 10
+
 type NodeCtx struct {
 	Node            *Node
 	AppState        *appstate.AppState
@@ -156,7 +161,8 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, statsCollect
 		return nil, errors.Wrap(err, "cannot set API key")
 	}
 
-	ipfsProxy, err := ipfs.NewIpfsProxy(config.IpfsConf, bus)
+	peerGater := protocol.NewPeerGater(config.P2P)
+	ipfsProxy, err := ipfs.NewIpfsProxy(config.IpfsConf, peerGater, bus)
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +174,9 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, statsCollect
 	if err != nil {
 		return nil, err
 	}
+	if config.Blockchain.ArchiveMode {
+		appState.SetArchiveMode(config.Blockchain.ArchiveStatesCount)
+	}
 
 	offlineDetector := blockchain.NewOfflineDetector(config, db, appState, secStore, bus)
 
@@ -186,7 +195,7 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, statsCollect
 	chain := blockchain.NewBlockchain(config, db, txpool, appState, ipfsProxy, secStore, bus, offlineDetector, keyStore, subManager, upgrader)
 	proposals, pendingProofs := pengings.NewProposals(chain, appState, offlineDetector, upgrader, statsCollector)
 	flipper := flip.NewFlipper(db, ipfsProxy, flipKeyPool, txpool, secStore, appState, bus)
-	pm := protocol.NewIdenaGossipHandler(ipfsProxy.Host(), ipfsProxy.PubSub(), config.P2P, chain, proposals, votes, txpool, flipper, bus, flipKeyPool, appVersion, &ceremonyChecker{
+	pm := protocol.NewIdenaGossipHandler(ipfsProxy.Host(), ipfsProxy.PubSub(), config.P2P, peerGater, chain, db, proposals, votes, txpool, flipper, bus, flipKeyPool, appVersion, &ceremonyChecker{
 		appState: appState,
 		chain:    chain,
 	})
@@ -288,7 +297,11 @@ func (node *Node) StartWithHeight(height uint64) {
 	node.ceremony.Initialize(node.blockchain.GetBlock(node.blockchain.Head.Hash()))
 	node.blockchain.ProvideApplyNewEpochFunc(node.ceremony.ApplyNewEpoch)
 	node.offlineDetector.Start(node.blockchain.Head)
-	node.consensusEngine.Start()
+	if node.config.Sync.LightMode {
+		go node.downloader.SyncLight()
+	} else {
+		node.consensusEngine.Start()
+	}
 	node.pm.Start()
 	node.upgrader.Start()
 
@@ -307,24 +320,65 @@ func (node *Node) WaitForStop() {
 // startup. It's not meant to be called at any time afterwards as it makes certain
 // assumptions about the state of the node.
 func (node *Node) startRPC() error {
+	node.rpcStats = rpc.NewStats()
 	// Gather all the possible APIs to surface
 	apis := node.apis()
+	node.keyring = rpc.NewKeyring(node.config.RPC.APIKey, node.config.RPC.APIKeys)
 
-	if err := node.startHTTP(node.config.RPC.HTTPEndpoint(), apis, node.config.RPC.HTTPModules, node.config.RPC.HTTPCors, node.config.RPC.HTTPVirtualHosts, node.config.RPC.HTTPTimeouts, node.config.RPC.APIKey); err != nil {
+	if err := node.startHTTP(node.config.RPC.HTTPEndpoint(), apis, node.config.RPC.HTTPModules, node.config.RPC.HTTPCors, node.config.RPC.HTTPVirtualHosts, node.config.RPC.HTTPTimeouts, node.keyring); err != nil {
+		return err
+	}
+	if err := node.startWS(node.config.RPC.WSEndpoint(), apis, node.config.RPC.WSModules, node.config.RPC.WSOrigins, node.keyring); err != nil {
+		node.stopHTTP()
 		return err
 	}
+	go node.watchAPIKeys()
 
 	node.rpcAPIs = apis
 	return nil
 }
 
+// watchAPIKeys reloads scoped API keys each time the configuration file is modified
+func (node *Node) watchAPIKeys() {
+	file := node.config.File()
+	if file == "" {
+		return
+	}
+	var modTime time.Time
+	if info, err := os.Stat(file); err == nil {
+		modTime = info.ModTime()
+	}
+	ticker := time.NewTicker(apiKeysReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-node.stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(file)
+			if err != nil || !info.ModTime().After(modTime) {
+				continue
+			}
+			modTime = info.ModTime()
+			keys, err := node.config.ReadAPIKeys()
+			if err != nil {
+				node.log.Warn("Failed to reload API keys", "err", err)
+				continue
+			}
+			node.keyring.UpdateScopedKeys(keys)
+			node.log.Info("API keys reloaded", "count", len(keys))
+		}
+	}
+}
+
 // startHTTP initializes and starts the HTTP RPC endpoint.
-func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, apiKey string) error {
+func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, keyring *rpc.Keyring) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts, apiKey)
+	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts, keyring, node.config.RPC.Limits, node.rpcStats,
+		api.NewHealthChecker(node.consensusEngine, node.blockchain, node.downloader, node.pm, node.ipfsProxy, node.ceremony))
 	if err != nil {
 		return err
 	}
@@ -350,6 +404,21 @@ func (node *Node) stopHTTP() {
 	}
 }
 
+// startWS initializes and starts the websocket RPC endpoint.
+func (node *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins []string, keyring *rpc.Keyring) error {
+	// Short circuit if the WS endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	listener, _, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, false, keyring, node.config.RPC.Limits, node.rpcStats)
+	if err != nil {
+		return err
+	}
+	node.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()), "origins", strings.Join(wsOrigins, ","))
+
+	return nil
+}
+
 func OpenDatabase(datadir string, name string, cache int, handles int) (db.DB, error) {
 	return db.NewGoLevelDBWithOpts(name, datadir, &opt.Options{
 		OpenFilesCacheCapacity: handles,
@@ -364,7 +433,7 @@ func (node *Node) apis() []rpc.API {
 
 	baseApi := api.NewBaseApi(node.consensusEngine, node.txpool, node.keyStore, node.secStore, node.ipfsProxy)
 
-	return []rpc.API{
+	apis := []rpc.API{
 		{
 			Namespace: "net",
 			Version:   "1.0",
@@ -376,36 +445,72 @@ func (node *Node) apis() []rpc.API {
 			Version:   "1.0",
 			Service:   api.NewDnaApi(baseApi, node.blockchain, node.ceremony, node.appVersion, node.profileManager),
 			Public:    true,
+			Untimed: []string{"sendInvite", "activateInvite", "activateInviteToRandAddr", "becomeOnline", "becomeOffline",
+				"delegate", "undelegate", "killDelegator", "storeToIpfs", "sendToIpfs", "sendTransaction", "burn",
+				"changeProfile", "sendChangeProfileTx", "importKey"},
 		},
 		{
 			Namespace: "account",
 			Version:   "1.0",
 			Service:   api.NewAccountApi(baseApi),
 			Public:    true,
+			Untimed:   []string{"create"},
 		},
 		{
 			Namespace: "flip",
 			Version:   "1.0",
 			Service:   api.NewFlipApi(baseApi, node.fp, node.ipfsProxy, node.ceremony),
 			Public:    true,
+			Untimed: []string{"submit", "rawSubmit", "delete", "sendPublicEncryptionKey", "sendPrivateEncryptionKeysPackage",
+				"submitShortAnswers", "submitLongAnswers"},
 		},
 		{
 			Namespace: "bcn",
 			Version:   "1.0",
 			Service:   api.NewBlockchainApi(baseApi, node.blockchain, node.ipfsProxy, node.txpool, node.downloader, node.pm),
 			Public:    true,
+			Untimed:   []string{"sendRawTx"},
 		},
 		{
 			Namespace: "ipfs",
 			Version:   "1.0",
 			Service:   api.NewIpfsApi(node.ipfsProxy),
 			Public:    true,
+			Untimed:   []string{"add"},
 		},
 		{
 			Namespace: "contract",
 			Version:   "1.0",
 			Service:   api.NewContractApi(baseApi, node.blockchain, node.deferJob, node.subManager),
 			Public:    true,
+			Untimed:   []string{"deploy", "call", "terminate"},
+		},
+		{
+			Namespace: "bcn",
+			Version:   "1.0",
+			Service:   api.NewSubscriptionApi(node.bus),
+			Public:    true,
 		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   api.NewDebugApi(node.blockchain),
+			Public:    false,
+		},
+		{
+			Namespace: "node",
+			Version:   "1.0",
+			Service:   api.NewNodeApi(node.rpcStats),
+			Public:    true,
+		},
+	}
+	if node.config.Sync.LightMode {
+		apis = append(apis, rpc.API{
+			Namespace: "light",
+			Version:   "1.0",
+			Service:   api.NewLightApi(node.pm, node.downloader),
+			Public:    true,
+		})
 	}
+	return apis
 }
@@ -3575,6 +3575,8 @@ type ProtoSavedEvent struct {
 	Contract []byte   `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
 	Event    string   `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
 	Args     [][]byte `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	TxHash   []byte   `protobuf:"bytes,4,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	Height   uint64   `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
 }
 
 func (x *ProtoSavedEvent) Reset() {
@@ -3630,6 +3632,20 @@ func (x *ProtoSavedEvent) GetArgs() [][]byte {
 	return nil
 }
 
+func (x *ProtoSavedEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *ProtoSavedEvent) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 type ProtoUpgradeVotes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -3724,6 +3740,148 @@ func (x *ProtoLotteryIdentitiesDb) GetIdentities() []*ProtoLotteryIdentitiesDb_I
 	return nil
 }
 
+type ProtoGetStateProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReqId   uint32 `protobuf:"varint,1,opt,name=reqId,proto3" json:"reqId,omitempty"`
+	Height  uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Address []byte `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Key     []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ProtoGetStateProofRequest) Reset() {
+	*x = ProtoGetStateProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_models_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProtoGetStateProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoGetStateProofRequest) ProtoMessage() {}
+
+func (x *ProtoGetStateProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_models_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoGetStateProofRequest.ProtoReflect.Descriptor instead.
+func (*ProtoGetStateProofRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_models_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ProtoGetStateProofRequest) GetReqId() uint32 {
+	if x != nil {
+		return x.ReqId
+	}
+	return 0
+}
+
+func (x *ProtoGetStateProofRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ProtoGetStateProofRequest) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ProtoGetStateProofRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type ProtoStateProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReqId uint32 `protobuf:"varint,1,opt,name=reqId,proto3" json:"reqId,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Proof []byte `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ProtoStateProof) Reset() {
+	*x = ProtoStateProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_models_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProtoStateProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoStateProof) ProtoMessage() {}
+
+func (x *ProtoStateProof) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_models_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoStateProof.ProtoReflect.Descriptor instead.
+func (*ProtoStateProof) Descriptor() ([]byte, []int) {
+	return file_protobuf_models_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ProtoStateProof) GetReqId() uint32 {
+	if x != nil {
+		return x.ReqId
+	}
+	return 0
+}
+
+func (x *ProtoStateProof) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *ProtoStateProof) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *ProtoStateProof) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type ProtoTransaction_Data struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -3742,7 +3900,7 @@ type ProtoTransaction_Data struct {
 func (x *ProtoTransaction_Data) Reset() {
 	*x = ProtoTransaction_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[61]
+		mi := &file_protobuf_models_proto_msgTypes[63]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3755,7 +3913,7 @@ func (x *ProtoTransaction_Data) String() string {
 func (*ProtoTransaction_Data) ProtoMessage() {}
 
 func (x *ProtoTransaction_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[61]
+	mi := &file_protobuf_models_proto_msgTypes[63]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3853,7 +4011,7 @@ type ProtoBlockHeader_Proposed struct {
 func (x *ProtoBlockHeader_Proposed) Reset() {
 	*x = ProtoBlockHeader_Proposed{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[62]
+		mi := &file_protobuf_models_proto_msgTypes[64]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3866,7 +4024,7 @@ func (x *ProtoBlockHeader_Proposed) String() string {
 func (*ProtoBlockHeader_Proposed) ProtoMessage() {}
 
 func (x *ProtoBlockHeader_Proposed) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[62]
+	mi := &file_protobuf_models_proto_msgTypes[64]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4011,7 +4169,7 @@ type ProtoBlockHeader_Empty struct {
 func (x *ProtoBlockHeader_Empty) Reset() {
 	*x = ProtoBlockHeader_Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[63]
+		mi := &file_protobuf_models_proto_msgTypes[65]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4024,7 +4182,7 @@ func (x *ProtoBlockHeader_Empty) String() string {
 func (*ProtoBlockHeader_Empty) ProtoMessage() {}
 
 func (x *ProtoBlockHeader_Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[63]
+	mi := &file_protobuf_models_proto_msgTypes[65]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4102,7 +4260,7 @@ type ProtoBlockProposal_Data struct {
 func (x *ProtoBlockProposal_Data) Reset() {
 	*x = ProtoBlockProposal_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[64]
+		mi := &file_protobuf_models_proto_msgTypes[66]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4115,7 +4273,7 @@ func (x *ProtoBlockProposal_Data) String() string {
 func (*ProtoBlockProposal_Data) ProtoMessage() {}
 
 func (x *ProtoBlockProposal_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[64]
+	mi := &file_protobuf_models_proto_msgTypes[66]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4165,7 +4323,7 @@ type ProtoBlockCert_Signature struct {
 func (x *ProtoBlockCert_Signature) Reset() {
 	*x = ProtoBlockCert_Signature{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[65]
+		mi := &file_protobuf_models_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4178,7 +4336,7 @@ func (x *ProtoBlockCert_Signature) String() string {
 func (*ProtoBlockCert_Signature) ProtoMessage() {}
 
 func (x *ProtoBlockCert_Signature) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[65]
+	mi := &file_protobuf_models_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4227,7 +4385,7 @@ type ProtoMsgBatch_BatchItem struct {
 func (x *ProtoMsgBatch_BatchItem) Reset() {
 	*x = ProtoMsgBatch_BatchItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[66]
+		mi := &file_protobuf_models_proto_msgTypes[68]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4240,7 +4398,7 @@ func (x *ProtoMsgBatch_BatchItem) String() string {
 func (*ProtoMsgBatch_BatchItem) ProtoMessage() {}
 
 func (x *ProtoMsgBatch_BatchItem) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[66]
+	mi := &file_protobuf_models_proto_msgTypes[68]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4283,7 +4441,7 @@ type ProtoIdentityStateDiff_IdentityStateDiffValue struct {
 func (x *ProtoIdentityStateDiff_IdentityStateDiffValue) Reset() {
 	*x = ProtoIdentityStateDiff_IdentityStateDiffValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[67]
+		mi := &file_protobuf_models_proto_msgTypes[69]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4296,7 +4454,7 @@ func (x *ProtoIdentityStateDiff_IdentityStateDiffValue) String() string {
 func (*ProtoIdentityStateDiff_IdentityStateDiffValue) ProtoMessage() {}
 
 func (x *ProtoIdentityStateDiff_IdentityStateDiffValue) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[67]
+	mi := &file_protobuf_models_proto_msgTypes[69]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4345,7 +4503,7 @@ type ProtoSnapshotBlock_KeyValue struct {
 func (x *ProtoSnapshotBlock_KeyValue) Reset() {
 	*x = ProtoSnapshotBlock_KeyValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[68]
+		mi := &file_protobuf_models_proto_msgTypes[70]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4358,7 +4516,7 @@ func (x *ProtoSnapshotBlock_KeyValue) String() string {
 func (*ProtoSnapshotBlock_KeyValue) ProtoMessage() {}
 
 func (x *ProtoSnapshotBlock_KeyValue) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[68]
+	mi := &file_protobuf_models_proto_msgTypes[70]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4403,7 +4561,7 @@ type ProtoSnapshotNodes_Node struct {
 func (x *ProtoSnapshotNodes_Node) Reset() {
 	*x = ProtoSnapshotNodes_Node{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[69]
+		mi := &file_protobuf_models_proto_msgTypes[71]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4416,7 +4574,7 @@ func (x *ProtoSnapshotNodes_Node) String() string {
 func (*ProtoSnapshotNodes_Node) ProtoMessage() {}
 
 func (x *ProtoSnapshotNodes_Node) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[69]
+	mi := &file_protobuf_models_proto_msgTypes[71]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4480,7 +4638,7 @@ type ProtoGossipBlockRange_Block struct {
 func (x *ProtoGossipBlockRange_Block) Reset() {
 	*x = ProtoGossipBlockRange_Block{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[70]
+		mi := &file_protobuf_models_proto_msgTypes[72]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4493,7 +4651,7 @@ func (x *ProtoGossipBlockRange_Block) String() string {
 func (*ProtoGossipBlockRange_Block) ProtoMessage() {}
 
 func (x *ProtoGossipBlockRange_Block) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[70]
+	mi := &file_protobuf_models_proto_msgTypes[72]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4542,7 +4700,7 @@ type ProtoProposeProof_Data struct {
 func (x *ProtoProposeProof_Data) Reset() {
 	*x = ProtoProposeProof_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[71]
+		mi := &file_protobuf_models_proto_msgTypes[73]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4555,7 +4713,7 @@ func (x *ProtoProposeProof_Data) String() string {
 func (*ProtoProposeProof_Data) ProtoMessage() {}
 
 func (x *ProtoProposeProof_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[71]
+	mi := &file_protobuf_models_proto_msgTypes[73]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4601,7 +4759,7 @@ type ProtoVote_Data struct {
 func (x *ProtoVote_Data) Reset() {
 	*x = ProtoVote_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[72]
+		mi := &file_protobuf_models_proto_msgTypes[74]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4614,7 +4772,7 @@ func (x *ProtoVote_Data) String() string {
 func (*ProtoVote_Data) ProtoMessage() {}
 
 func (x *ProtoVote_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[72]
+	mi := &file_protobuf_models_proto_msgTypes[74]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4684,7 +4842,7 @@ type ProtoFlipKey_Data struct {
 func (x *ProtoFlipKey_Data) Reset() {
 	*x = ProtoFlipKey_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[73]
+		mi := &file_protobuf_models_proto_msgTypes[75]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4697,7 +4855,7 @@ func (x *ProtoFlipKey_Data) String() string {
 func (*ProtoFlipKey_Data) ProtoMessage() {}
 
 func (x *ProtoFlipKey_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[73]
+	mi := &file_protobuf_models_proto_msgTypes[75]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4739,7 +4897,7 @@ type ProtoPrivateFlipKeysPackage_Data struct {
 func (x *ProtoPrivateFlipKeysPackage_Data) Reset() {
 	*x = ProtoPrivateFlipKeysPackage_Data{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[74]
+		mi := &file_protobuf_models_proto_msgTypes[76]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4752,7 +4910,7 @@ func (x *ProtoPrivateFlipKeysPackage_Data) String() string {
 func (*ProtoPrivateFlipKeysPackage_Data) ProtoMessage() {}
 
 func (x *ProtoPrivateFlipKeysPackage_Data) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[74]
+	mi := &file_protobuf_models_proto_msgTypes[76]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4794,7 +4952,7 @@ type ProtoAnswersDb_Answer struct {
 func (x *ProtoAnswersDb_Answer) Reset() {
 	*x = ProtoAnswersDb_Answer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[75]
+		mi := &file_protobuf_models_proto_msgTypes[77]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4807,7 +4965,7 @@ func (x *ProtoAnswersDb_Answer) String() string {
 func (*ProtoAnswersDb_Answer) ProtoMessage() {}
 
 func (x *ProtoAnswersDb_Answer) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[75]
+	mi := &file_protobuf_models_proto_msgTypes[77]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4849,7 +5007,7 @@ type ProtoActivityMonitor_Activity struct {
 func (x *ProtoActivityMonitor_Activity) Reset() {
 	*x = ProtoActivityMonitor_Activity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[76]
+		mi := &file_protobuf_models_proto_msgTypes[78]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4862,7 +5020,7 @@ func (x *ProtoActivityMonitor_Activity) String() string {
 func (*ProtoActivityMonitor_Activity) ProtoMessage() {}
 
 func (x *ProtoActivityMonitor_Activity) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[76]
+	mi := &file_protobuf_models_proto_msgTypes[78]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4904,7 +5062,7 @@ type ProtoStateAccount_ProtoContractData struct {
 func (x *ProtoStateAccount_ProtoContractData) Reset() {
 	*x = ProtoStateAccount_ProtoContractData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[77]
+		mi := &file_protobuf_models_proto_msgTypes[79]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4917,7 +5075,7 @@ func (x *ProtoStateAccount_ProtoContractData) String() string {
 func (*ProtoStateAccount_ProtoContractData) ProtoMessage() {}
 
 func (x *ProtoStateAccount_ProtoContractData) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[77]
+	mi := &file_protobuf_models_proto_msgTypes[79]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4959,7 +5117,7 @@ type ProtoStateIdentity_Flip struct {
 func (x *ProtoStateIdentity_Flip) Reset() {
 	*x = ProtoStateIdentity_Flip{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[78]
+		mi := &file_protobuf_models_proto_msgTypes[80]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4972,7 +5130,7 @@ func (x *ProtoStateIdentity_Flip) String() string {
 func (*ProtoStateIdentity_Flip) ProtoMessage() {}
 
 func (x *ProtoStateIdentity_Flip) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[78]
+	mi := &file_protobuf_models_proto_msgTypes[80]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5014,7 +5172,7 @@ type ProtoStateIdentity_TxAddr struct {
 func (x *ProtoStateIdentity_TxAddr) Reset() {
 	*x = ProtoStateIdentity_TxAddr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[79]
+		mi := &file_protobuf_models_proto_msgTypes[81]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5027,7 +5185,7 @@ func (x *ProtoStateIdentity_TxAddr) String() string {
 func (*ProtoStateIdentity_TxAddr) ProtoMessage() {}
 
 func (x *ProtoStateIdentity_TxAddr) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[79]
+	mi := &file_protobuf_models_proto_msgTypes[81]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5070,7 +5228,7 @@ type ProtoStateIdentity_Inviter struct {
 func (x *ProtoStateIdentity_Inviter) Reset() {
 	*x = ProtoStateIdentity_Inviter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[80]
+		mi := &file_protobuf_models_proto_msgTypes[82]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5083,7 +5241,7 @@ func (x *ProtoStateIdentity_Inviter) String() string {
 func (*ProtoStateIdentity_Inviter) ProtoMessage() {}
 
 func (x *ProtoStateIdentity_Inviter) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[80]
+	mi := &file_protobuf_models_proto_msgTypes[82]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5132,7 +5290,7 @@ type ProtoStateGlobal_EmptyBlocksByShards struct {
 func (x *ProtoStateGlobal_EmptyBlocksByShards) Reset() {
 	*x = ProtoStateGlobal_EmptyBlocksByShards{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[81]
+		mi := &file_protobuf_models_proto_msgTypes[83]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5145,7 +5303,7 @@ func (x *ProtoStateGlobal_EmptyBlocksByShards) String() string {
 func (*ProtoStateGlobal_EmptyBlocksByShards) ProtoMessage() {}
 
 func (x *ProtoStateGlobal_EmptyBlocksByShards) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[81]
+	mi := &file_protobuf_models_proto_msgTypes[83]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5187,7 +5345,7 @@ type ProtoStateGlobal_ShardSize struct {
 func (x *ProtoStateGlobal_ShardSize) Reset() {
 	*x = ProtoStateGlobal_ShardSize{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[82]
+		mi := &file_protobuf_models_proto_msgTypes[84]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5200,7 +5358,7 @@ func (x *ProtoStateGlobal_ShardSize) String() string {
 func (*ProtoStateGlobal_ShardSize) ProtoMessage() {}
 
 func (x *ProtoStateGlobal_ShardSize) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[82]
+	mi := &file_protobuf_models_proto_msgTypes[84]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5242,7 +5400,7 @@ type ProtoStateDelegationSwitch_Delegation struct {
 func (x *ProtoStateDelegationSwitch_Delegation) Reset() {
 	*x = ProtoStateDelegationSwitch_Delegation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[83]
+		mi := &file_protobuf_models_proto_msgTypes[85]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5255,7 +5413,7 @@ func (x *ProtoStateDelegationSwitch_Delegation) String() string {
 func (*ProtoStateDelegationSwitch_Delegation) ProtoMessage() {}
 
 func (x *ProtoStateDelegationSwitch_Delegation) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[83]
+	mi := &file_protobuf_models_proto_msgTypes[85]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5309,7 +5467,7 @@ type ProtoPredefinedState_Global struct {
 func (x *ProtoPredefinedState_Global) Reset() {
 	*x = ProtoPredefinedState_Global{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[84]
+		mi := &file_protobuf_models_proto_msgTypes[86]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5322,7 +5480,7 @@ func (x *ProtoPredefinedState_Global) String() string {
 func (*ProtoPredefinedState_Global) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Global) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[84]
+	mi := &file_protobuf_models_proto_msgTypes[86]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5447,7 +5605,7 @@ type ProtoPredefinedState_StatusSwitch struct {
 func (x *ProtoPredefinedState_StatusSwitch) Reset() {
 	*x = ProtoPredefinedState_StatusSwitch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[85]
+		mi := &file_protobuf_models_proto_msgTypes[87]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5460,7 +5618,7 @@ func (x *ProtoPredefinedState_StatusSwitch) String() string {
 func (*ProtoPredefinedState_StatusSwitch) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_StatusSwitch) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[85]
+	mi := &file_protobuf_models_proto_msgTypes[87]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5498,7 +5656,7 @@ type ProtoPredefinedState_Account struct {
 func (x *ProtoPredefinedState_Account) Reset() {
 	*x = ProtoPredefinedState_Account{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[86]
+		mi := &file_protobuf_models_proto_msgTypes[88]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5511,7 +5669,7 @@ func (x *ProtoPredefinedState_Account) String() string {
 func (*ProtoPredefinedState_Account) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Account) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[86]
+	mi := &file_protobuf_models_proto_msgTypes[88]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5595,7 +5753,7 @@ type ProtoPredefinedState_Identity struct {
 func (x *ProtoPredefinedState_Identity) Reset() {
 	*x = ProtoPredefinedState_Identity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[87]
+		mi := &file_protobuf_models_proto_msgTypes[89]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5608,7 +5766,7 @@ func (x *ProtoPredefinedState_Identity) String() string {
 func (*ProtoPredefinedState_Identity) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Identity) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[87]
+	mi := &file_protobuf_models_proto_msgTypes[89]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5799,7 +5957,7 @@ type ProtoPredefinedState_ApprovedIdentity struct {
 func (x *ProtoPredefinedState_ApprovedIdentity) Reset() {
 	*x = ProtoPredefinedState_ApprovedIdentity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[88]
+		mi := &file_protobuf_models_proto_msgTypes[90]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5812,7 +5970,7 @@ func (x *ProtoPredefinedState_ApprovedIdentity) String() string {
 func (*ProtoPredefinedState_ApprovedIdentity) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_ApprovedIdentity) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[88]
+	mi := &file_protobuf_models_proto_msgTypes[90]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5868,7 +6026,7 @@ type ProtoPredefinedState_ContractKeyValue struct {
 func (x *ProtoPredefinedState_ContractKeyValue) Reset() {
 	*x = ProtoPredefinedState_ContractKeyValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[89]
+		mi := &file_protobuf_models_proto_msgTypes[91]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5881,7 +6039,7 @@ func (x *ProtoPredefinedState_ContractKeyValue) String() string {
 func (*ProtoPredefinedState_ContractKeyValue) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_ContractKeyValue) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[89]
+	mi := &file_protobuf_models_proto_msgTypes[91]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5923,7 +6081,7 @@ type ProtoPredefinedState_Account_ContractData struct {
 func (x *ProtoPredefinedState_Account_ContractData) Reset() {
 	*x = ProtoPredefinedState_Account_ContractData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[90]
+		mi := &file_protobuf_models_proto_msgTypes[92]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5936,7 +6094,7 @@ func (x *ProtoPredefinedState_Account_ContractData) String() string {
 func (*ProtoPredefinedState_Account_ContractData) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Account_ContractData) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[90]
+	mi := &file_protobuf_models_proto_msgTypes[92]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5978,7 +6136,7 @@ type ProtoPredefinedState_Identity_Flip struct {
 func (x *ProtoPredefinedState_Identity_Flip) Reset() {
 	*x = ProtoPredefinedState_Identity_Flip{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[91]
+		mi := &file_protobuf_models_proto_msgTypes[93]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -5991,7 +6149,7 @@ func (x *ProtoPredefinedState_Identity_Flip) String() string {
 func (*ProtoPredefinedState_Identity_Flip) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Identity_Flip) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[91]
+	mi := &file_protobuf_models_proto_msgTypes[93]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6033,7 +6191,7 @@ type ProtoPredefinedState_Identity_TxAddr struct {
 func (x *ProtoPredefinedState_Identity_TxAddr) Reset() {
 	*x = ProtoPredefinedState_Identity_TxAddr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[92]
+		mi := &file_protobuf_models_proto_msgTypes[94]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6046,7 +6204,7 @@ func (x *ProtoPredefinedState_Identity_TxAddr) String() string {
 func (*ProtoPredefinedState_Identity_TxAddr) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Identity_TxAddr) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[92]
+	mi := &file_protobuf_models_proto_msgTypes[94]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6089,7 +6247,7 @@ type ProtoPredefinedState_Identity_Inviter struct {
 func (x *ProtoPredefinedState_Identity_Inviter) Reset() {
 	*x = ProtoPredefinedState_Identity_Inviter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[93]
+		mi := &file_protobuf_models_proto_msgTypes[95]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6102,7 +6260,7 @@ func (x *ProtoPredefinedState_Identity_Inviter) String() string {
 func (*ProtoPredefinedState_Identity_Inviter) ProtoMessage() {}
 
 func (x *ProtoPredefinedState_Identity_Inviter) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[93]
+	mi := &file_protobuf_models_proto_msgTypes[95]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6158,7 +6316,7 @@ type ProtoTxReceipts_ProtoTxReceipt struct {
 func (x *ProtoTxReceipts_ProtoTxReceipt) Reset() {
 	*x = ProtoTxReceipts_ProtoTxReceipt{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[94]
+		mi := &file_protobuf_models_proto_msgTypes[96]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6171,7 +6329,7 @@ func (x *ProtoTxReceipts_ProtoTxReceipt) String() string {
 func (*ProtoTxReceipts_ProtoTxReceipt) ProtoMessage() {}
 
 func (x *ProtoTxReceipts_ProtoTxReceipt) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[94]
+	mi := &file_protobuf_models_proto_msgTypes[96]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6255,14 +6413,15 @@ type ProtoTxReceipts_ProtoEvent struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Event string   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
-	Data  [][]byte `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty"`
+	Event    string   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Data     [][]byte `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty"`
+	Contract []byte   `protobuf:"bytes,3,opt,name=contract,proto3" json:"contract,omitempty"`
 }
 
 func (x *ProtoTxReceipts_ProtoEvent) Reset() {
 	*x = ProtoTxReceipts_ProtoEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[95]
+		mi := &file_protobuf_models_proto_msgTypes[97]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6275,7 +6434,7 @@ func (x *ProtoTxReceipts_ProtoEvent) String() string {
 func (*ProtoTxReceipts_ProtoEvent) ProtoMessage() {}
 
 func (x *ProtoTxReceipts_ProtoEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[95]
+	mi := &file_protobuf_models_proto_msgTypes[97]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6305,6 +6464,13 @@ func (x *ProtoTxReceipts_ProtoEvent) GetData() [][]byte {
 	return nil
 }
 
+func (x *ProtoTxReceipts_ProtoEvent) GetContract() []byte {
+	if x != nil {
+		return x.Contract
+	}
+	return nil
+}
+
 type ProtoDeferredTxs_ProtoDeferredTx struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -6321,7 +6487,7 @@ type ProtoDeferredTxs_ProtoDeferredTx struct {
 func (x *ProtoDeferredTxs_ProtoDeferredTx) Reset() {
 	*x = ProtoDeferredTxs_ProtoDeferredTx{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[96]
+		mi := &file_protobuf_models_proto_msgTypes[98]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6334,7 +6500,7 @@ func (x *ProtoDeferredTxs_ProtoDeferredTx) String() string {
 func (*ProtoDeferredTxs_ProtoDeferredTx) ProtoMessage() {}
 
 func (x *ProtoDeferredTxs_ProtoDeferredTx) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[96]
+	mi := &file_protobuf_models_proto_msgTypes[98]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6404,7 +6570,7 @@ type ProtoUpgradeVotes_ProtoUpgradeVote struct {
 func (x *ProtoUpgradeVotes_ProtoUpgradeVote) Reset() {
 	*x = ProtoUpgradeVotes_ProtoUpgradeVote{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[97]
+		mi := &file_protobuf_models_proto_msgTypes[99]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6417,7 +6583,7 @@ func (x *ProtoUpgradeVotes_ProtoUpgradeVote) String() string {
 func (*ProtoUpgradeVotes_ProtoUpgradeVote) ProtoMessage() {}
 
 func (x *ProtoUpgradeVotes_ProtoUpgradeVote) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[97]
+	mi := &file_protobuf_models_proto_msgTypes[99]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6463,7 +6629,7 @@ type ProtoLotteryIdentitiesDb_Identity struct {
 func (x *ProtoLotteryIdentitiesDb_Identity) Reset() {
 	*x = ProtoLotteryIdentitiesDb_Identity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_models_proto_msgTypes[98]
+		mi := &file_protobuf_models_proto_msgTypes[100]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -6476,7 +6642,7 @@ func (x *ProtoLotteryIdentitiesDb_Identity) String() string {
 func (*ProtoLotteryIdentitiesDb_Identity) ProtoMessage() {}
 
 func (x *ProtoLotteryIdentitiesDb_Identity) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_models_proto_msgTypes[98]
+	mi := &file_protobuf_models_proto_msgTypes[100]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7257,7 +7423,7 @@ var file_protobuf_models_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x49, 0x70, 0x66, 0x73, 0x41, 0x74, 0x74,
 	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xa0, 0x03,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xbc, 0x03,
 	0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74,
 	0x73, 0x12, 0x42, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f,
@@ -7280,64 +7446,83 @@ var file_protobuf_models_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x6f, 0x54, 0x78, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x2e, 0x50, 0x72,
 	0x6f, 0x74, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
 	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x1a, 0x36, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x1a, 0x52, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74,
 	0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
 	0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0x3d, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x52, 0x65, 0x63, 0x65, 0x69,
-	0x70, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64,
-	0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22,
-	0xe2, 0x01, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65,
-	0x64, 0x54, 0x78, 0x73, 0x12, 0x3a, 0x0a, 0x03, 0x54, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x28, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x44, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x54, 0x78, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x44, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x54, 0x78, 0x52, 0x03, 0x54, 0x78, 0x73,
-	0x1a, 0x91, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66, 0x65, 0x72, 0x72,
-	0x65, 0x64, 0x54, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75,
-	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69,
-	0x70, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x74, 0x69, 0x70, 0x73, 0x12, 0x14,
-	0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x57, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x61, 0x76,
-	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72,
-	0x61, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72,
-	0x61, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67,
-	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x22, 0x99, 0x01,
-	0x0a, 0x11, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f,
-	0x74, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x2e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x05,
-	0x76, 0x6f, 0x74, 0x65, 0x73, 0x1a, 0x42, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x70,
-	0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x74,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x12,
-	0x18, 0x0a, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x22, 0xb8, 0x02, 0x0a, 0x18, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x74, 0x69, 0x65, 0x73, 0x44, 0x62, 0x12, 0x49, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x6d, 0x6f, 0x64,
-	0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79,
-	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x44, 0x62, 0x2e, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x1a, 0xd0, 0x01, 0x0a, 0x08, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x18,
-	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x68, 0x69, 0x66,
-	0x74, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x0e, 0x73, 0x68, 0x69, 0x66, 0x74, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64,
-	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x6c, 0x69, 0x70, 0x43, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0c, 0x52, 0x08, 0x66, 0x6c, 0x69, 0x70, 0x43, 0x69, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06,
-	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75,
-	0x62, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x68, 0x61,
-	0x73, 0x44, 0x6f, 0x6e, 0x65, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
-	0x46, 0x6c, 0x69, 0x70, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x68, 0x61, 0x73,
-	0x44, 0x6f, 0x6e, 0x65, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x46,
-	0x6c, 0x69, 0x70, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x22, 0x3d, 0x0a, 0x13,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0xe2, 0x01, 0x0a, 0x10,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x54, 0x78, 0x73,
+	0x12, 0x3a, 0x0a, 0x03, 0x54, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66, 0x65,
+	0x72, 0x72, 0x65, 0x64, 0x54, 0x78, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66,
+	0x65, 0x72, 0x72, 0x65, 0x64, 0x54, 0x78, 0x52, 0x03, 0x54, 0x78, 0x73, 0x1a, 0x91, 0x01, 0x0a,
+	0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x54, 0x78,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x70, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x74, 0x69, 0x70, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x22, 0x87, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x61, 0x76, 0x65, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x78,
+	0x48, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x78, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x99, 0x01, 0x0a, 0x11, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x73,
+	0x12, 0x40, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2a, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x76, 0x6f, 0x74,
+	0x65, 0x73, 0x1a, 0x42, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x75,
+	0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x22, 0xb8, 0x02, 0x0a, 0x18, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x44, 0x62, 0x12, 0x49, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
+	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x44, 0x62, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x1a, 0xd0,
+	0x01, 0x0a, 0x08, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x68, 0x69, 0x66, 0x74, 0x65, 0x64,
+	0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73,
+	0x68, 0x69, 0x66, 0x74, 0x65, 0x64, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x6c, 0x69, 0x70, 0x43, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x08, 0x66, 0x6c, 0x69, 0x70, 0x43, 0x69, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62,
+	0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x68, 0x61, 0x73, 0x44, 0x6f,
+	0x6e, 0x65, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x46, 0x6c, 0x69,
+	0x70, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x68, 0x61, 0x73, 0x44, 0x6f, 0x6e,
+	0x65, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x46, 0x6c, 0x69, 0x70,
+	0x73, 0x22, 0x75, 0x0a, 0x19, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x72, 0x65, 0x71, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x72,
+	0x65, 0x71, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x69, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x72,
+	0x65, 0x71, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x72, 0x65, 0x71, 0x49,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -7352,7 +7537,7 @@ func file_protobuf_models_proto_rawDescGZIP() []byte {
 	return file_protobuf_models_proto_rawDescData
 }
 
-var file_protobuf_models_proto_msgTypes = make([]protoimpl.MessageInfo, 99)
+var file_protobuf_models_proto_msgTypes = make([]protoimpl.MessageInfo, 101)
 var file_protobuf_models_proto_goTypes = []interface{}{
 	(*ProtoTransaction)(nil),                              // 0: models.ProtoTransaction
 	(*ProtoBlockHeader)(nil),                              // 1: models.ProtoBlockHeader
@@ -7415,99 +7600,101 @@ var file_protobuf_models_proto_goTypes = []interface{}{
 	(*ProtoSavedEvent)(nil),                               // 58: models.ProtoSavedEvent
 	(*ProtoUpgradeVotes)(nil),                             // 59: models.ProtoUpgradeVotes
 	(*ProtoLotteryIdentitiesDb)(nil),                      // 60: models.ProtoLotteryIdentitiesDb
-	(*ProtoTransaction_Data)(nil),                         // 61: models.ProtoTransaction.Data
-	(*ProtoBlockHeader_Proposed)(nil),                     // 62: models.ProtoBlockHeader.Proposed
-	(*ProtoBlockHeader_Empty)(nil),                        // 63: models.ProtoBlockHeader.Empty
-	(*ProtoBlockProposal_Data)(nil),                       // 64: models.ProtoBlockProposal.Data
-	(*ProtoBlockCert_Signature)(nil),                      // 65: models.ProtoBlockCert.Signature
-	(*ProtoMsgBatch_BatchItem)(nil),                       // 66: models.ProtoMsgBatch.BatchItem
-	(*ProtoIdentityStateDiff_IdentityStateDiffValue)(nil), // 67: models.ProtoIdentityStateDiff.IdentityStateDiffValue
-	(*ProtoSnapshotBlock_KeyValue)(nil),                   // 68: models.ProtoSnapshotBlock.KeyValue
-	(*ProtoSnapshotNodes_Node)(nil),                       // 69: models.ProtoSnapshotNodes.Node
-	(*ProtoGossipBlockRange_Block)(nil),                   // 70: models.ProtoGossipBlockRange.Block
-	(*ProtoProposeProof_Data)(nil),                        // 71: models.ProtoProposeProof.Data
-	(*ProtoVote_Data)(nil),                                // 72: models.ProtoVote.Data
-	(*ProtoFlipKey_Data)(nil),                             // 73: models.ProtoFlipKey.Data
-	(*ProtoPrivateFlipKeysPackage_Data)(nil),              // 74: models.ProtoPrivateFlipKeysPackage.Data
-	(*ProtoAnswersDb_Answer)(nil),                         // 75: models.ProtoAnswersDb.Answer
-	(*ProtoActivityMonitor_Activity)(nil),                 // 76: models.ProtoActivityMonitor.Activity
-	(*ProtoStateAccount_ProtoContractData)(nil),           // 77: models.ProtoStateAccount.ProtoContractData
-	(*ProtoStateIdentity_Flip)(nil),                       // 78: models.ProtoStateIdentity.Flip
-	(*ProtoStateIdentity_TxAddr)(nil),                     // 79: models.ProtoStateIdentity.TxAddr
-	(*ProtoStateIdentity_Inviter)(nil),                    // 80: models.ProtoStateIdentity.Inviter
-	(*ProtoStateGlobal_EmptyBlocksByShards)(nil),          // 81: models.ProtoStateGlobal.EmptyBlocksByShards
-	(*ProtoStateGlobal_ShardSize)(nil),                    // 82: models.ProtoStateGlobal.ShardSize
-	(*ProtoStateDelegationSwitch_Delegation)(nil),         // 83: models.ProtoStateDelegationSwitch.Delegation
-	(*ProtoPredefinedState_Global)(nil),                   // 84: models.ProtoPredefinedState.Global
-	(*ProtoPredefinedState_StatusSwitch)(nil),             // 85: models.ProtoPredefinedState.StatusSwitch
-	(*ProtoPredefinedState_Account)(nil),                  // 86: models.ProtoPredefinedState.Account
-	(*ProtoPredefinedState_Identity)(nil),                 // 87: models.ProtoPredefinedState.Identity
-	(*ProtoPredefinedState_ApprovedIdentity)(nil),         // 88: models.ProtoPredefinedState.ApprovedIdentity
-	(*ProtoPredefinedState_ContractKeyValue)(nil),         // 89: models.ProtoPredefinedState.ContractKeyValue
-	(*ProtoPredefinedState_Account_ContractData)(nil),     // 90: models.ProtoPredefinedState.Account.ContractData
-	(*ProtoPredefinedState_Identity_Flip)(nil),            // 91: models.ProtoPredefinedState.Identity.Flip
-	(*ProtoPredefinedState_Identity_TxAddr)(nil),          // 92: models.ProtoPredefinedState.Identity.TxAddr
-	(*ProtoPredefinedState_Identity_Inviter)(nil),         // 93: models.ProtoPredefinedState.Identity.Inviter
-	(*ProtoTxReceipts_ProtoTxReceipt)(nil),                // 94: models.ProtoTxReceipts.ProtoTxReceipt
-	(*ProtoTxReceipts_ProtoEvent)(nil),                    // 95: models.ProtoTxReceipts.ProtoEvent
-	(*ProtoDeferredTxs_ProtoDeferredTx)(nil),              // 96: models.ProtoDeferredTxs.ProtoDeferredTx
-	(*ProtoUpgradeVotes_ProtoUpgradeVote)(nil),            // 97: models.ProtoUpgradeVotes.ProtoUpgradeVote
-	(*ProtoLotteryIdentitiesDb_Identity)(nil),             // 98: models.ProtoLotteryIdentitiesDb.Identity
+	(*ProtoGetStateProofRequest)(nil),                     // 61: models.ProtoGetStateProofRequest
+	(*ProtoStateProof)(nil),                               // 62: models.ProtoStateProof
+	(*ProtoTransaction_Data)(nil),                         // 63: models.ProtoTransaction.Data
+	(*ProtoBlockHeader_Proposed)(nil),                     // 64: models.ProtoBlockHeader.Proposed
+	(*ProtoBlockHeader_Empty)(nil),                        // 65: models.ProtoBlockHeader.Empty
+	(*ProtoBlockProposal_Data)(nil),                       // 66: models.ProtoBlockProposal.Data
+	(*ProtoBlockCert_Signature)(nil),                      // 67: models.ProtoBlockCert.Signature
+	(*ProtoMsgBatch_BatchItem)(nil),                       // 68: models.ProtoMsgBatch.BatchItem
+	(*ProtoIdentityStateDiff_IdentityStateDiffValue)(nil), // 69: models.ProtoIdentityStateDiff.IdentityStateDiffValue
+	(*ProtoSnapshotBlock_KeyValue)(nil),                   // 70: models.ProtoSnapshotBlock.KeyValue
+	(*ProtoSnapshotNodes_Node)(nil),                       // 71: models.ProtoSnapshotNodes.Node
+	(*ProtoGossipBlockRange_Block)(nil),                   // 72: models.ProtoGossipBlockRange.Block
+	(*ProtoProposeProof_Data)(nil),                        // 73: models.ProtoProposeProof.Data
+	(*ProtoVote_Data)(nil),                                // 74: models.ProtoVote.Data
+	(*ProtoFlipKey_Data)(nil),                             // 75: models.ProtoFlipKey.Data
+	(*ProtoPrivateFlipKeysPackage_Data)(nil),              // 76: models.ProtoPrivateFlipKeysPackage.Data
+	(*ProtoAnswersDb_Answer)(nil),                         // 77: models.ProtoAnswersDb.Answer
+	(*ProtoActivityMonitor_Activity)(nil),                 // 78: models.ProtoActivityMonitor.Activity
+	(*ProtoStateAccount_ProtoContractData)(nil),           // 79: models.ProtoStateAccount.ProtoContractData
+	(*ProtoStateIdentity_Flip)(nil),                       // 80: models.ProtoStateIdentity.Flip
+	(*ProtoStateIdentity_TxAddr)(nil),                     // 81: models.ProtoStateIdentity.TxAddr
+	(*ProtoStateIdentity_Inviter)(nil),                    // 82: models.ProtoStateIdentity.Inviter
+	(*ProtoStateGlobal_EmptyBlocksByShards)(nil),          // 83: models.ProtoStateGlobal.EmptyBlocksByShards
+	(*ProtoStateGlobal_ShardSize)(nil),                    // 84: models.ProtoStateGlobal.ShardSize
+	(*ProtoStateDelegationSwitch_Delegation)(nil),         // 85: models.ProtoStateDelegationSwitch.Delegation
+	(*ProtoPredefinedState_Global)(nil),                   // 86: models.ProtoPredefinedState.Global
+	(*ProtoPredefinedState_StatusSwitch)(nil),             // 87: models.ProtoPredefinedState.StatusSwitch
+	(*ProtoPredefinedState_Account)(nil),                  // 88: models.ProtoPredefinedState.Account
+	(*ProtoPredefinedState_Identity)(nil),                 // 89: models.ProtoPredefinedState.Identity
+	(*ProtoPredefinedState_ApprovedIdentity)(nil),         // 90: models.ProtoPredefinedState.ApprovedIdentity
+	(*ProtoPredefinedState_ContractKeyValue)(nil),         // 91: models.ProtoPredefinedState.ContractKeyValue
+	(*ProtoPredefinedState_Account_ContractData)(nil),     // 92: models.ProtoPredefinedState.Account.ContractData
+	(*ProtoPredefinedState_Identity_Flip)(nil),            // 93: models.ProtoPredefinedState.Identity.Flip
+	(*ProtoPredefinedState_Identity_TxAddr)(nil),          // 94: models.ProtoPredefinedState.Identity.TxAddr
+	(*ProtoPredefinedState_Identity_Inviter)(nil),         // 95: models.ProtoPredefinedState.Identity.Inviter
+	(*ProtoTxReceipts_ProtoTxReceipt)(nil),                // 96: models.ProtoTxReceipts.ProtoTxReceipt
+	(*ProtoTxReceipts_ProtoEvent)(nil),                    // 97: models.ProtoTxReceipts.ProtoEvent
+	(*ProtoDeferredTxs_ProtoDeferredTx)(nil),              // 98: models.ProtoDeferredTxs.ProtoDeferredTx
+	(*ProtoUpgradeVotes_ProtoUpgradeVote)(nil),            // 99: models.ProtoUpgradeVotes.ProtoUpgradeVote
+	(*ProtoLotteryIdentitiesDb_Identity)(nil),             // 100: models.ProtoLotteryIdentitiesDb.Identity
 }
 var file_protobuf_models_proto_depIdxs = []int32{
-	61, // 0: models.ProtoTransaction.data:type_name -> models.ProtoTransaction.Data
-	62, // 1: models.ProtoBlockHeader.proposedHeader:type_name -> models.ProtoBlockHeader.Proposed
-	63, // 2: models.ProtoBlockHeader.emptyHeader:type_name -> models.ProtoBlockHeader.Empty
-	0,  // 3: models.ProtoBlockBody.transactions:type_name -> models.ProtoTransaction
-	1,  // 4: models.ProtoBlock.header:type_name -> models.ProtoBlockHeader
-	2,  // 5: models.ProtoBlock.body:type_name -> models.ProtoBlockBody
-	64, // 6: models.ProtoBlockProposal.data:type_name -> models.ProtoBlockProposal.Data
-	65, // 7: models.ProtoBlockCert.signatures:type_name -> models.ProtoBlockCert.Signature
-	66, // 8: models.ProtoMsgBatch.data:type_name -> models.ProtoMsgBatch.BatchItem
-	67, // 9: models.ProtoIdentityStateDiff.values:type_name -> models.ProtoIdentityStateDiff.IdentityStateDiffValue
-	68, // 10: models.ProtoSnapshotBlock.data:type_name -> models.ProtoSnapshotBlock.KeyValue
-	69, // 11: models.ProtoSnapshotNodes.nodes:type_name -> models.ProtoSnapshotNodes.Node
-	70, // 12: models.ProtoGossipBlockRange.blocks:type_name -> models.ProtoGossipBlockRange.Block
-	71, // 13: models.ProtoProposeProof.data:type_name -> models.ProtoProposeProof.Data
-	72, // 14: models.ProtoVote.data:type_name -> models.ProtoVote.Data
-	0,  // 15: models.ProtoFlip.transaction:type_name -> models.ProtoTransaction
-	73, // 16: models.ProtoFlipKey.data:type_name -> models.ProtoFlipKey.Data
-	74, // 17: models.ProtoPrivateFlipKeysPackage.data:type_name -> models.ProtoPrivateFlipKeysPackage.Data
-	75, // 18: models.ProtoAnswersDb.answers:type_name -> models.ProtoAnswersDb.Answer
-	0,  // 19: models.ProtoSavedTransaction.tx:type_name -> models.ProtoTransaction
-	76, // 20: models.ProtoActivityMonitor.activities:type_name -> models.ProtoActivityMonitor.Activity
-	77, // 21: models.ProtoStateAccount.contractData:type_name -> models.ProtoStateAccount.ProtoContractData
-	78, // 22: models.ProtoStateIdentity.flips:type_name -> models.ProtoStateIdentity.Flip
-	79, // 23: models.ProtoStateIdentity.invitees:type_name -> models.ProtoStateIdentity.TxAddr
-	80, // 24: models.ProtoStateIdentity.inviter:type_name -> models.ProtoStateIdentity.Inviter
-	81, // 25: models.ProtoStateGlobal.emptyBlocksByShards:type_name -> models.ProtoStateGlobal.EmptyBlocksByShards
-	82, // 26: models.ProtoStateGlobal.shardSizes:type_name -> models.ProtoStateGlobal.ShardSize
-	83, // 27: models.ProtoStateDelegationSwitch.delegations:type_name -> models.ProtoStateDelegationSwitch.Delegation
-	84, // 28: models.ProtoPredefinedState.global:type_name -> models.ProtoPredefinedState.Global
-	85, // 29: models.ProtoPredefinedState.statusSwitch:type_name -> models.ProtoPredefinedState.StatusSwitch
-	86, // 30: models.ProtoPredefinedState.accounts:type_name -> models.ProtoPredefinedState.Account
-	87, // 31: models.ProtoPredefinedState.identities:type_name -> models.ProtoPredefinedState.Identity
-	88, // 32: models.ProtoPredefinedState.approvedIdentities:type_name -> models.ProtoPredefinedState.ApprovedIdentity
-	89, // 33: models.ProtoPredefinedState.contractValues:type_name -> models.ProtoPredefinedState.ContractKeyValue
-	94, // 34: models.ProtoTxReceipts.receipts:type_name -> models.ProtoTxReceipts.ProtoTxReceipt
-	96, // 35: models.ProtoDeferredTxs.Txs:type_name -> models.ProtoDeferredTxs.ProtoDeferredTx
-	97, // 36: models.ProtoUpgradeVotes.votes:type_name -> models.ProtoUpgradeVotes.ProtoUpgradeVote
-	98, // 37: models.ProtoLotteryIdentitiesDb.identities:type_name -> models.ProtoLotteryIdentitiesDb.Identity
-	1,  // 38: models.ProtoBlockProposal.Data.header:type_name -> models.ProtoBlockHeader
-	2,  // 39: models.ProtoBlockProposal.Data.body:type_name -> models.ProtoBlockBody
-	1,  // 40: models.ProtoGossipBlockRange.Block.header:type_name -> models.ProtoBlockHeader
-	6,  // 41: models.ProtoGossipBlockRange.Block.cert:type_name -> models.ProtoBlockCert
-	16, // 42: models.ProtoGossipBlockRange.Block.diff:type_name -> models.ProtoIdentityStateDiff
-	90, // 43: models.ProtoPredefinedState.Account.contractData:type_name -> models.ProtoPredefinedState.Account.ContractData
-	91, // 44: models.ProtoPredefinedState.Identity.flips:type_name -> models.ProtoPredefinedState.Identity.Flip
-	92, // 45: models.ProtoPredefinedState.Identity.invitees:type_name -> models.ProtoPredefinedState.Identity.TxAddr
-	93, // 46: models.ProtoPredefinedState.Identity.inviter:type_name -> models.ProtoPredefinedState.Identity.Inviter
-	95, // 47: models.ProtoTxReceipts.ProtoTxReceipt.events:type_name -> models.ProtoTxReceipts.ProtoEvent
-	48, // [48:48] is the sub-list for method output_type
-	48, // [48:48] is the sub-list for method input_type
-	48, // [48:48] is the sub-list for extension type_name
-	48, // [48:48] is the sub-list for extension extendee
-	0,  // [0:48] is the sub-list for field type_name
+	63,  // 0: models.ProtoTransaction.data:type_name -> models.ProtoTransaction.Data
+	64,  // 1: models.ProtoBlockHeader.proposedHeader:type_name -> models.ProtoBlockHeader.Proposed
+	65,  // 2: models.ProtoBlockHeader.emptyHeader:type_name -> models.ProtoBlockHeader.Empty
+	0,   // 3: models.ProtoBlockBody.transactions:type_name -> models.ProtoTransaction
+	1,   // 4: models.ProtoBlock.header:type_name -> models.ProtoBlockHeader
+	2,   // 5: models.ProtoBlock.body:type_name -> models.ProtoBlockBody
+	66,  // 6: models.ProtoBlockProposal.data:type_name -> models.ProtoBlockProposal.Data
+	67,  // 7: models.ProtoBlockCert.signatures:type_name -> models.ProtoBlockCert.Signature
+	68,  // 8: models.ProtoMsgBatch.data:type_name -> models.ProtoMsgBatch.BatchItem
+	69,  // 9: models.ProtoIdentityStateDiff.values:type_name -> models.ProtoIdentityStateDiff.IdentityStateDiffValue
+	70,  // 10: models.ProtoSnapshotBlock.data:type_name -> models.ProtoSnapshotBlock.KeyValue
+	71,  // 11: models.ProtoSnapshotNodes.nodes:type_name -> models.ProtoSnapshotNodes.Node
+	72,  // 12: models.ProtoGossipBlockRange.blocks:type_name -> models.ProtoGossipBlockRange.Block
+	73,  // 13: models.ProtoProposeProof.data:type_name -> models.ProtoProposeProof.Data
+	74,  // 14: models.ProtoVote.data:type_name -> models.ProtoVote.Data
+	0,   // 15: models.ProtoFlip.transaction:type_name -> models.ProtoTransaction
+	75,  // 16: models.ProtoFlipKey.data:type_name -> models.ProtoFlipKey.Data
+	76,  // 17: models.ProtoPrivateFlipKeysPackage.data:type_name -> models.ProtoPrivateFlipKeysPackage.Data
+	77,  // 18: models.ProtoAnswersDb.answers:type_name -> models.ProtoAnswersDb.Answer
+	0,   // 19: models.ProtoSavedTransaction.tx:type_name -> models.ProtoTransaction
+	78,  // 20: models.ProtoActivityMonitor.activities:type_name -> models.ProtoActivityMonitor.Activity
+	79,  // 21: models.ProtoStateAccount.contractData:type_name -> models.ProtoStateAccount.ProtoContractData
+	80,  // 22: models.ProtoStateIdentity.flips:type_name -> models.ProtoStateIdentity.Flip
+	81,  // 23: models.ProtoStateIdentity.invitees:type_name -> models.ProtoStateIdentity.TxAddr
+	82,  // 24: models.ProtoStateIdentity.inviter:type_name -> models.ProtoStateIdentity.Inviter
+	83,  // 25: models.ProtoStateGlobal.emptyBlocksByShards:type_name -> models.ProtoStateGlobal.EmptyBlocksByShards
+	84,  // 26: models.ProtoStateGlobal.shardSizes:type_name -> models.ProtoStateGlobal.ShardSize
+	85,  // 27: models.ProtoStateDelegationSwitch.delegations:type_name -> models.ProtoStateDelegationSwitch.Delegation
+	86,  // 28: models.ProtoPredefinedState.global:type_name -> models.ProtoPredefinedState.Global
+	87,  // 29: models.ProtoPredefinedState.statusSwitch:type_name -> models.ProtoPredefinedState.StatusSwitch
+	88,  // 30: models.ProtoPredefinedState.accounts:type_name -> models.ProtoPredefinedState.Account
+	89,  // 31: models.ProtoPredefinedState.identities:type_name -> models.ProtoPredefinedState.Identity
+	90,  // 32: models.ProtoPredefinedState.approvedIdentities:type_name -> models.ProtoPredefinedState.ApprovedIdentity
+	91,  // 33: models.ProtoPredefinedState.contractValues:type_name -> models.ProtoPredefinedState.ContractKeyValue
+	96,  // 34: models.ProtoTxReceipts.receipts:type_name -> models.ProtoTxReceipts.ProtoTxReceipt
+	98,  // 35: models.ProtoDeferredTxs.Txs:type_name -> models.ProtoDeferredTxs.ProtoDeferredTx
+	99,  // 36: models.ProtoUpgradeVotes.votes:type_name -> models.ProtoUpgradeVotes.ProtoUpgradeVote
+	100, // 37: models.ProtoLotteryIdentitiesDb.identities:type_name -> models.ProtoLotteryIdentitiesDb.Identity
+	1,   // 38: models.ProtoBlockProposal.Data.header:type_name -> models.ProtoBlockHeader
+	2,   // 39: models.ProtoBlockProposal.Data.body:type_name -> models.ProtoBlockBody
+	1,   // 40: models.ProtoGossipBlockRange.Block.header:type_name -> models.ProtoBlockHeader
+	6,   // 41: models.ProtoGossipBlockRange.Block.cert:type_name -> models.ProtoBlockCert
+	16,  // 42: models.ProtoGossipBlockRange.Block.diff:type_name -> models.ProtoIdentityStateDiff
+	92,  // 43: models.ProtoPredefinedState.Account.contractData:type_name -> models.ProtoPredefinedState.Account.ContractData
+	93,  // 44: models.ProtoPredefinedState.Identity.flips:type_name -> models.ProtoPredefinedState.Identity.Flip
+	94,  // 45: models.ProtoPredefinedState.Identity.invitees:type_name -> models.ProtoPredefinedState.Identity.TxAddr
+	95,  // 46: models.ProtoPredefinedState.Identity.inviter:type_name -> models.ProtoPredefinedState.Identity.Inviter
+	97,  // 47: models.ProtoTxReceipts.ProtoTxReceipt.events:type_name -> models.ProtoTxReceipts.ProtoEvent
+	48,  // [48:48] is the sub-list for method output_type
+	48,  // [48:48] is the sub-list for method input_type
+	48,  // [48:48] is the sub-list for extension type_name
+	48,  // [48:48] is the sub-list for extension extendee
+	0,   // [0:48] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_models_proto_init() }
@@ -8249,7 +8436,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoTransaction_Data); i {
+			switch v := v.(*ProtoGetStateProofRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8261,7 +8448,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoBlockHeader_Proposed); i {
+			switch v := v.(*ProtoStateProof); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8273,7 +8460,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoBlockHeader_Empty); i {
+			switch v := v.(*ProtoTransaction_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8285,7 +8472,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoBlockProposal_Data); i {
+			switch v := v.(*ProtoBlockHeader_Proposed); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8297,7 +8484,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoBlockCert_Signature); i {
+			switch v := v.(*ProtoBlockHeader_Empty); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8309,7 +8496,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoMsgBatch_BatchItem); i {
+			switch v := v.(*ProtoBlockProposal_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8321,7 +8508,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoIdentityStateDiff_IdentityStateDiffValue); i {
+			switch v := v.(*ProtoBlockCert_Signature); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8333,7 +8520,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoSnapshotBlock_KeyValue); i {
+			switch v := v.(*ProtoMsgBatch_BatchItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8345,7 +8532,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoSnapshotNodes_Node); i {
+			switch v := v.(*ProtoIdentityStateDiff_IdentityStateDiffValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8357,7 +8544,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoGossipBlockRange_Block); i {
+			switch v := v.(*ProtoSnapshotBlock_KeyValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8369,7 +8556,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoProposeProof_Data); i {
+			switch v := v.(*ProtoSnapshotNodes_Node); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8381,7 +8568,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoVote_Data); i {
+			switch v := v.(*ProtoGossipBlockRange_Block); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8393,7 +8580,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoFlipKey_Data); i {
+			switch v := v.(*ProtoProposeProof_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8405,7 +8592,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPrivateFlipKeysPackage_Data); i {
+			switch v := v.(*ProtoVote_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8417,7 +8604,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoAnswersDb_Answer); i {
+			switch v := v.(*ProtoFlipKey_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8429,7 +8616,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoActivityMonitor_Activity); i {
+			switch v := v.(*ProtoPrivateFlipKeysPackage_Data); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8441,7 +8628,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateAccount_ProtoContractData); i {
+			switch v := v.(*ProtoAnswersDb_Answer); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8453,7 +8640,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateIdentity_Flip); i {
+			switch v := v.(*ProtoActivityMonitor_Activity); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8465,7 +8652,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateIdentity_TxAddr); i {
+			switch v := v.(*ProtoStateAccount_ProtoContractData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8477,7 +8664,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateIdentity_Inviter); i {
+			switch v := v.(*ProtoStateIdentity_Flip); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8489,7 +8676,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[81].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateGlobal_EmptyBlocksByShards); i {
+			switch v := v.(*ProtoStateIdentity_TxAddr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8501,7 +8688,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateGlobal_ShardSize); i {
+			switch v := v.(*ProtoStateIdentity_Inviter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8513,7 +8700,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoStateDelegationSwitch_Delegation); i {
+			switch v := v.(*ProtoStateGlobal_EmptyBlocksByShards); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8525,7 +8712,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Global); i {
+			switch v := v.(*ProtoStateGlobal_ShardSize); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8537,7 +8724,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_StatusSwitch); i {
+			switch v := v.(*ProtoStateDelegationSwitch_Delegation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8549,7 +8736,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[86].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Account); i {
+			switch v := v.(*ProtoPredefinedState_Global); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8561,7 +8748,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[87].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Identity); i {
+			switch v := v.(*ProtoPredefinedState_StatusSwitch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8573,7 +8760,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[88].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_ApprovedIdentity); i {
+			switch v := v.(*ProtoPredefinedState_Account); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8585,7 +8772,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[89].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_ContractKeyValue); i {
+			switch v := v.(*ProtoPredefinedState_Identity); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8597,7 +8784,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[90].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Account_ContractData); i {
+			switch v := v.(*ProtoPredefinedState_ApprovedIdentity); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8609,7 +8796,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[91].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Identity_Flip); i {
+			switch v := v.(*ProtoPredefinedState_ContractKeyValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8621,7 +8808,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[92].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Identity_TxAddr); i {
+			switch v := v.(*ProtoPredefinedState_Account_ContractData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8633,7 +8820,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[93].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoPredefinedState_Identity_Inviter); i {
+			switch v := v.(*ProtoPredefinedState_Identity_Flip); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8645,7 +8832,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[94].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoTxReceipts_ProtoTxReceipt); i {
+			switch v := v.(*ProtoPredefinedState_Identity_TxAddr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8657,7 +8844,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[95].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoTxReceipts_ProtoEvent); i {
+			switch v := v.(*ProtoPredefinedState_Identity_Inviter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8669,7 +8856,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[96].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoDeferredTxs_ProtoDeferredTx); i {
+			switch v := v.(*ProtoTxReceipts_ProtoTxReceipt); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8681,7 +8868,7 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[97].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProtoUpgradeVotes_ProtoUpgradeVote); i {
+			switch v := v.(*ProtoTxReceipts_ProtoEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -8693,6 +8880,30 @@ func file_protobuf_models_proto_init() {
 			}
 		}
 		file_protobuf_models_proto_msgTypes[98].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProtoDeferredTxs_ProtoDeferredTx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_models_proto_msgTypes[99].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProtoUpgradeVotes_ProtoUpgradeVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_models_proto_msgTypes[100].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProtoLotteryIdentitiesDb_Identity); i {
 			case 0:
 				return &v.state
@@ -8711,7 +8922,7 @@ func file_protobuf_models_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protobuf_models_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   99,
+			NumMessages:   101,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
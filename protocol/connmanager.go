@@ -2,7 +2,6 @@ package protocol
 
 import (
 	"context"
-	"github.com/deckarep/golang-set"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/config"
 	core "github.com/libp2p/go-libp2p-core"
@@ -20,19 +19,29 @@ import (
 
 const (
 	dialPeerAttempts = 10
+
+	// a failed dial of a static peer is retried after the backoff which doubles with every failure
+	staticDialMinBackoff = time.Second * 15
+	staticDialMaxBackoff = time.Minute * 10
 )
 
 var FailedToDialPeer = errors.New("failed to select peer")
 var NoPeersToDial = errors.New("no peers to dial")
 
 type ConnManager struct {
-	bannedPeers       mapset.Set
+	scores            *peerScores
 	activeConnections map[peer.ID]network.Conn
 	discTimes         map[peer.ID]time.Time
 	resetTimes        map[peer.ID]time.Time
 
 	inboundPeers  map[peer.ID]common.ShardId
 	outboundPeers map[peer.ID]common.ShardId
+	// connected static and private peers, they are kept apart from inbound and outbound peers to bypass limits and
+	// rotation
+	connectedTrusted map[peer.ID]common.ShardId
+
+	gater       *PeerGater
+	staticDials *dialBackoff
 
 	peerMutex sync.RWMutex
 	connMutex sync.Mutex
@@ -42,31 +51,64 @@ type ConnManager struct {
 	ownShardId common.ShardId
 }
 
-func NewConnManager(host core.Host, cfg config.P2P) *ConnManager {
-	return &ConnManager{
+func NewConnManager(host core.Host, cfg config.P2P, gater *PeerGater, scores *peerScores) *ConnManager {
+	m := &ConnManager{
 		host:              host,
 		cfg:               cfg,
-		bannedPeers:       mapset.NewSet(),
+		gater:             gater,
+		scores:            scores,
 		activeConnections: make(map[peer.ID]network.Conn),
 		inboundPeers:      make(map[peer.ID]common.ShardId),
 		outboundPeers:     make(map[peer.ID]common.ShardId),
+		connectedTrusted:  make(map[peer.ID]common.ShardId),
 		discTimes:         make(map[peer.ID]time.Time),
 		resetTimes:        make(map[peer.ID]time.Time),
+		staticDials:       newDialBackoff(),
+	}
+	for _, info := range gater.StaticPeers() {
+		host.ConnManager().Protect(info.ID, "static")
+	}
+	for id := range gater.privatePeers {
+		host.ConnManager().Protect(id, "private")
 	}
+	return m
+}
+
+// IsStatic checks whether the peer is listed in the static peers of the config
+func (m *ConnManager) IsStatic(id peer.ID) bool {
+	return m.gater.IsStatic(id)
+}
+
+func (m *ConnManager) StaticPeers() []peer.AddrInfo {
+	return m.gater.StaticPeers()
+}
+
+// IsTrusted checks whether the peer is a static or a private peer, trusted peers bypass limits, rotation and bans
+func (m *ConnManager) IsTrusted(id peer.ID) bool {
+	return m.gater.IsTrusted(id)
+}
+
+// IsAllowed checks whether the idena protocol may be run with the peer
+func (m *ConnManager) IsAllowed(id peer.ID) bool {
+	return m.gater.IsAllowed(id)
 }
 
 func (m *ConnManager) CanConnect(id peer.ID) bool {
 
-	if m.bannedPeers.Contains(id) {
+	if !m.IsAllowed(id) {
+		return false
+	}
+	trusted := m.IsTrusted(id)
+	if !trusted && m.scores.isBanned(id) {
 		return false
 	}
 	m.peerMutex.RLock()
 	defer m.peerMutex.RUnlock()
-	if discTime, ok := m.discTimes[id]; ok && time.Now().UTC().Sub(discTime) < ReconnectAfterDiscTimeout {
+	if discTime, ok := m.discTimes[id]; ok && !trusted && time.Now().UTC().Sub(discTime) < ReconnectAfterDiscTimeout {
 		return false
 	}
 
-	if resetTime, ok := m.resetTimes[id]; ok && time.Now().UTC().Sub(resetTime) < ReconnectAfterResetTimeout {
+	if resetTime, ok := m.resetTimes[id]; ok && !trusted && time.Now().UTC().Sub(resetTime) < ReconnectAfterResetTimeout {
 		return false
 	}
 	if m.host.Network().Connectedness(id) != network.Connected {
@@ -87,7 +129,9 @@ func (m *ConnManager) CanConnect(id peer.ID) bool {
 func (m *ConnManager) Connected(id peer.ID, inbound bool, shardId common.ShardId) {
 	m.peerMutex.Lock()
 	defer m.peerMutex.Unlock()
-	if inbound {
+	if m.IsTrusted(id) {
+		m.connectedTrusted[id] = shardId
+	} else if inbound {
 		m.inboundPeers[id] = shardId
 	} else {
 		m.outboundPeers[id] = shardId
@@ -105,6 +149,9 @@ func (m *ConnManager) UpdatePeerShardId(id peer.ID, shardId common.ShardId) {
 		m.outboundPeers[id] = shardId
 		return
 	}
+	if _, ok := m.connectedTrusted[id]; ok {
+		m.connectedTrusted[id] = shardId
+	}
 }
 
 func (m *ConnManager) SetShardId(shard common.ShardId) (updated bool) {
@@ -123,13 +170,21 @@ func (m *ConnManager) Disconnected(id peer.ID, reason error) {
 	}
 	delete(m.inboundPeers, id)
 	delete(m.outboundPeers, id)
+	delete(m.connectedTrusted, id)
 }
 
-func (m *ConnManager) BanPeer(id peer.ID) {
-	m.bannedPeers.Add(id)
-	if m.bannedPeers.Cardinality() > MaxBannedPeers {
-		m.bannedPeers.Pop()
-	}
+// PenalizePeer lowers the score of the peer and reports whether the peer is banned
+func (m *ConnManager) PenalizePeer(id peer.ID, penalty PeerPenalty) (banned bool) {
+	return m.scores.penalize(id, penalty) < banScore && !m.IsTrusted(id)
+}
+
+// RewardBandwidth raises the score of the peer according to the rate of data received from it
+func (m *ConnManager) RewardBandwidth(id peer.ID, rateKbs float64) {
+	m.scores.rewardBandwidth(id, rateKbs)
+}
+
+func (m *ConnManager) PeerScore(id peer.ID) float64 {
+	return m.scores.score(id)
 }
 
 func (m *ConnManager) DialRandomPeer() (network.Stream, error) {
@@ -147,8 +202,9 @@ func (m *ConnManager) DialRandomPeer() (network.Stream, error) {
 		m.peerMutex.RLock()
 		_, inbound := m.inboundPeers[id]
 		_, outbound := m.outboundPeers[id]
+		_, trusted := m.connectedTrusted[id]
 		m.peerMutex.RUnlock()
-		if !inbound && !outbound && m.CanConnect(id) {
+		if !inbound && !outbound && !trusted && m.CanConnect(id) {
 			filteredConns = append(filteredConns, c)
 		}
 	}
@@ -158,7 +214,11 @@ func (m *ConnManager) DialRandomPeer() (network.Stream, error) {
 	}
 
 	for attempt := 0; attempt < dialPeerAttempts; attempt++ {
-		idx := rand.Intn(len(filteredConns))
+		weights := make([]float64, len(filteredConns))
+		for i, c := range filteredConns {
+			weights[i] = m.scores.dialWeight(c.RemotePeer())
+		}
+		idx := pickWeighted(weights)
 		conn := filteredConns[idx]
 
 		if stream, err := m.findOrOpenStream(conn); err == nil {
@@ -174,6 +234,53 @@ func (m *ConnManager) DialRandomPeer() (network.Stream, error) {
 	return nil, FailedToDialPeer
 }
 
+// pickWeighted selects a random index, indexes with higher weights are more likely to be selected
+func pickWeighted(weights []float64) int {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return rand.Intn(len(weights))
+	}
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(weights) - 1
+}
+
+// StartStaticDial reports whether the static peer may be dialed now, the dial must be finished by FinishStaticDial
+func (m *ConnManager) StartStaticDial(id peer.ID) bool {
+	return m.staticDials.start(id)
+}
+
+func (m *ConnManager) FinishStaticDial(id peer.ID, success bool) {
+	m.staticDials.finish(id, success)
+}
+
+// DialStaticPeer connects to the static peer if needed and opens an idena stream to it
+func (m *ConnManager) DialStaticPeer(info peer.AddrInfo) (network.Stream, error) {
+	if m.host.Network().Connectedness(info.ID) != network.Connected {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		err := m.host.Connect(ctx, info)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.connMutex.Lock()
+	conn, ok := m.activeConnections[info.ID]
+	m.connMutex.Unlock()
+	if ok {
+		return m.findOrOpenStream(conn)
+	}
+	return m.newStream(info.ID)
+}
+
 func (m *ConnManager) findOrOpenStream(conn network.Conn) (network.Stream, error) {
 	streams := conn.GetStreams()
 	matcher, _ := helpers.MultistreamSemverMatcher(IdenaProtocol)
@@ -221,7 +328,7 @@ func (m *ConnManager) AddConnection(conn network.Conn) {
 
 	go func() {
 		id := conn.RemotePeer()
-		if m.bannedPeers.Contains(id) {
+		if !m.IsAllowed(id) || !m.IsTrusted(id) && m.scores.isBanned(id) {
 			return
 		}
 		time.Sleep(time.Second * 5)
@@ -418,5 +525,69 @@ func (m *ConnManager) peersCntFromShard(shardId common.ShardId) int {
 			cnt++
 		}
 	}
+	for _, s := range m.connectedTrusted {
+		if s == shardId {
+			cnt++
+		}
+	}
 	return cnt
 }
+
+type peerDial struct {
+	inFlight bool
+	failures int
+	next     time.Time
+}
+
+// dialBackoff keeps at most one dial per peer in flight and delays dials of the peers which have failed recently
+type dialBackoff struct {
+	dials map[peer.ID]*peerDial
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+func newDialBackoff() *dialBackoff {
+	return &dialBackoff{
+		dials: make(map[peer.ID]*peerDial),
+		now:   time.Now,
+	}
+}
+
+func (b *dialBackoff) start(id peer.ID) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	dial, ok := b.dials[id]
+	if !ok {
+		dial = &peerDial{}
+		b.dials[id] = dial
+	}
+	if dial.inFlight || b.now().Before(dial.next) {
+		return false
+	}
+	dial.inFlight = true
+	return true
+}
+
+func (b *dialBackoff) finish(id peer.ID, success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	dial, ok := b.dials[id]
+	if !ok {
+		return
+	}
+	dial.inFlight = false
+	if success {
+		dial.failures = 0
+		dial.next = time.Time{}
+		return
+	}
+	backoff := staticDialMaxBackoff
+	if dial.failures < 10 {
+		backoff = staticDialMinBackoff << dial.failures
+		if backoff > staticDialMaxBackoff {
+			backoff = staticDialMaxBackoff
+		}
+	}
+	dial.failures++
+	dial.next = b.now().Add(backoff)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/idena-network/idena-go/stats/collector"
 	"github.com/idena-network/idena-go/subscriptions"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"sync"
 	"time"
 )
 
@@ -32,6 +33,22 @@ var (
 	BanReasonTimeout = errors.New("timeout")
 )
 
+const (
+	SyncPhaseBlocks     = "blocks"
+	SyncPhaseHeaders    = "headers"
+	SyncPhaseSnapshot   = "snapshot"
+	SyncPhaseRecovering = "recovering"
+)
+
+// SyncStatus describes the current phase of the synchronization, bytes are reported for the snapshot phase only
+type SyncStatus struct {
+	Phase       string
+	BytesLoaded int64
+	BytesTotal  int64
+	// estimated time to complete the current phase, zero if unknown
+	Eta time.Duration
+}
+
 type Syncer interface {
 	IsSyncing() bool
 }
@@ -41,6 +58,7 @@ type blockApplier interface {
 	processBatch(batch *batch, attemptNum int) error
 	postConsuming() (err error)
 	preConsuming(head *types.Header) (uint64, error)
+	syncPhase() string
 }
 
 type ForkResolver interface {
@@ -69,9 +87,16 @@ type Downloader struct {
 	keyStore             *keystore.KeyStore
 	subManager           *subscriptions.Manager
 	upgrader             *upgrade.Upgrader
+	applier              blockApplier
+	// applierMutex guards the applier and the sync state read by SyncStatus
+	applierMutex    sync.RWMutex
+	syncStartHeight uint64
+	syncStarted     time.Time
 }
 
 func (d *Downloader) IsSyncing() bool {
+	d.applierMutex.RLock()
+	defer d.applierMutex.RUnlock()
 	return d.isSyncing
 }
 
@@ -156,7 +181,7 @@ func (d *Downloader) SyncBlockchain(forkResolver ForkResolver) error {
 			d.log.Info(fmt.Sprintf("Node is synchronized"))
 			return nil
 		}
-		if !d.isSyncing {
+		if !d.IsSyncing() {
 			d.startSync()
 			defer d.stopSync()
 		}
@@ -169,6 +194,9 @@ func (d *Downloader) Load() {
 	head := d.chain.Head
 
 	applier, toHeight := d.createBlockApplier()
+	d.applierMutex.Lock()
+	d.applier = applier
+	d.applierMutex.Unlock()
 
 	var from uint64
 	var err error
@@ -281,6 +309,11 @@ func (d *Downloader) ClearPotentialForks() {
 
 func (d *Downloader) createBlockApplier() (loader blockApplier, toHeight uint64) {
 
+	if d.cfg.Sync.LightMode {
+		d.log.Info("Light sync will be used")
+		return NewLightSync(d.pm, d.log, d.chain, d.ipfs, d.appState, d.potentialForkedPeers, d.sm, d.bus, d.secStore.GetAddress(), d.keyStore, d.subManager, d.upgrader), d.top
+	}
+
 	canUseFastSync := d.cfg.Sync.FastSync
 
 	if d.top-d.chain.Head.Height() < d.cfg.Sync.ForceFullSync {
@@ -332,7 +365,12 @@ func (d *Downloader) getBestManifest() *snapshot.Manifest {
 }
 
 func (d *Downloader) startSync() {
+	startHeight, _ := d.SyncProgress()
+	d.applierMutex.Lock()
+	d.syncStartHeight = startHeight
+	d.syncStarted = time.Now()
 	d.isSyncing = true
+	d.applierMutex.Unlock()
 	d.chain.StartSync()
 	d.sm.StartSync()
 }
@@ -340,10 +378,71 @@ func (d *Downloader) startSync() {
 func (d *Downloader) stopSync() {
 	d.chain.StopSync()
 	d.sm.StopSync()
+	d.applierMutex.Lock()
+	d.applier = nil
 	d.isSyncing = false
+	d.applierMutex.Unlock()
 	d.top = 0
 }
 
+// SyncStatus returns the phase of the current synchronization with the progress of the snapshot loading and
+// the estimated time to complete the phase
+func (d *Downloader) SyncStatus() SyncStatus {
+	var status SyncStatus
+	d.applierMutex.RLock()
+	isSyncing, syncStartHeight, syncStarted := d.isSyncing, d.syncStartHeight, d.syncStarted
+	status.Phase = SyncPhaseBlocks
+	if d.applier != nil {
+		status.Phase = d.applier.syncPhase()
+	}
+	d.applierMutex.RUnlock()
+	if !isSyncing {
+		return SyncStatus{}
+	}
+	switch status.Phase {
+	case SyncPhaseSnapshot:
+		progress := d.sm.Progress()
+		if progress == nil {
+			break
+		}
+		status.BytesLoaded, status.BytesTotal = progress.Loaded, progress.Total
+		if loaded := progress.Loaded - progress.Resumed; loaded > 0 && progress.Total > progress.Loaded {
+			elapsed := time.Since(progress.Started)
+			status.Eta = time.Duration(float64(elapsed) * float64(progress.Total-progress.Loaded) / float64(loaded))
+		}
+	case SyncPhaseBlocks, SyncPhaseHeaders:
+		head, top := d.SyncProgress()
+		if head > syncStartHeight && top > head {
+			elapsed := time.Since(syncStarted)
+			status.Eta = time.Duration(float64(elapsed) * float64(top-head) / float64(head-syncStartHeight))
+		}
+	}
+	return status
+}
+
+// LightHead returns the last verified header of the light mode
+func (d *Downloader) LightHead() *types.Header {
+	if head := d.chain.PreliminaryHead; head != nil {
+		return head
+	}
+	return d.chain.Head
+}
+
+// SyncLight keeps the light head up to date with peers, it replaces the consensus engine in the light mode
+func (d *Downloader) SyncLight() {
+	for {
+		knownHeights := d.pm.GetKnownHeights()
+		d.filterForkedPeers(knownHeights)
+		if top := getTopHeight(knownHeights); top > d.LightHead().Height() {
+			d.top = top
+			d.startSync()
+			d.Load()
+			d.stopSync()
+		}
+		time.Sleep(time.Second * 10)
+	}
+}
+
 func (d *Downloader) BanPeer(peerId peer.ID, reason error) {
 	if d.pm != nil {
 		d.pm.BanPeer(peerId, reason)
@@ -20,6 +20,7 @@ import (
 	"github.com/idena-network/idena-go/subscriptions"
 	"github.com/pkg/errors"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
@@ -45,6 +46,7 @@ type fastSync struct {
 	subManager           *subscriptions.Manager
 	upgrader             *upgrade.Upgrader
 	prevConfig           *config.ConsensusConf
+	phase                atomic.Value
 
 	pubKeyToAddrCache map[string]common.Address
 }
@@ -81,6 +83,13 @@ func NewFastSync(pm *IdenaGossipHandler, log log.Logger,
 	}
 }
 
+func (fs *fastSync) syncPhase() string {
+	if phase, ok := fs.phase.Load().(string); ok {
+		return phase
+	}
+	return SyncPhaseHeaders
+}
+
 func (fs *fastSync) createPreliminaryCopy(height uint64) (*state.IdentityStateDB, error) {
 	return fs.appState.IdentityState.CreatePreliminaryCopy(height)
 }
@@ -182,13 +191,13 @@ func (fs *fastSync) applyDeferredBlocks() (uint64, error) {
 				return b.Header.Height(), err
 			}
 			fs.chain.WriteTxIndex(b.Header.Hash(), txs)
-			fs.chain.Indexer().HandleBlockTransactions(b.Header, txs)
+			fs.chain.Indexer().HandleBlockTransactions(b.Header, txs, nil)
 
 			receipts, err := fs.GetTxReceipts(b.Header.ProposedHeader.TxReceiptsCid)
 			if err != nil {
 				return b.Header.Height(), err
 			}
-			fs.chain.WriteTxReceipts(b.Header.ProposedHeader.TxReceiptsCid, receipts)
+			fs.chain.WriteTxReceipts(b.Header.Height(), b.Header.ProposedHeader.TxReceiptsCid, receipts)
 		}
 	}
 	return 0, nil
@@ -259,7 +268,7 @@ func (fs *fastSync) processBatch(batch *batch, attemptNum int) error {
 		case block := <-batch.headers:
 			if block == nil {
 				err := errors.New("failed to load block header")
-				fs.pm.BanPeer(batch.p.id, err)
+				fs.pm.PenalizePeer(batch.p.id, PenaltyFailedBatch, err)
 				return err
 			}
 			batch.p.resetTimeouts()
@@ -284,7 +293,7 @@ func (fs *fastSync) processBatch(batch *batch, attemptNum int) error {
 		case <-timeout:
 			fs.log.Warn("process batch - timeout was reached", "peer", batch.p.id)
 			if batch.p.addTimeout() {
-				fs.pm.BanPeer(batch.p.id, BanReasonTimeout)
+				fs.pm.PenalizePeer(batch.p.id, PenaltyTimeout, BanReasonTimeout)
 			}
 			return reload(i)
 		}
@@ -335,12 +344,14 @@ func (fs *fastSync) postConsuming() error {
 		return errors.New("preliminary head's root doesn't equal manifest's root")
 	}*/
 	fs.log.Info("Start loading of snapshot", "height", fs.manifest.Height)
+	fs.phase.Store(SyncPhaseSnapshot)
+	defer fs.phase.Store(SyncPhaseHeaders)
 	filePath, version,  err := fs.sm.DownloadSnapshot(fs.manifest)
 	if err != nil {
-		fs.sm.AddTimeoutManifest(fs.manifest.CidV2)
 		return errors.WithMessage(err, "snapshot's downloading has been failed")
 	}
 	fs.log.Info("Snapshot has been loaded", "height", fs.manifest.Height)
+	fs.phase.Store(SyncPhaseRecovering)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -100,6 +100,10 @@ func (fs *fullSync) preConsuming(head *types.Header) (uint64, error) {
 	return head.Height() + 1, nil
 }
 
+func (fs *fullSync) syncPhase() string {
+	return SyncPhaseBlocks
+}
+
 func (fs *fullSync) postConsuming() error {
 	if len(fs.deferredHeaders) > 0 {
 		fs.log.Warn(fmt.Sprintf("All blocks was consumed but last headers have not been added to chain"))
@@ -133,7 +137,7 @@ func (fs *fullSync) processBatch(batch *batch, attemptNum int) error {
 		case block := <-batch.headers:
 			if block == nil {
 				err := errors.New("failed to load block header")
-				fs.pm.BanPeer(batch.p.id, err)
+				fs.pm.PenalizePeer(batch.p.id, PenaltyFailedBatch, err)
 				return err
 			}
 			batch.p.resetTimeouts()
@@ -155,7 +159,7 @@ func (fs *fullSync) processBatch(batch *batch, attemptNum int) error {
 		case <-timeout:
 			fs.log.Warn("process batch - timeout was reached", "peer", batch.p.id)
 			if batch.p.addTimeout() {
-				fs.pm.BanPeer(batch.p.id, BanReasonTimeout)
+				fs.pm.PenalizePeer(batch.p.id, PenaltyTimeout, BanReasonTimeout)
 			}
 			return reload(i)
 		}
@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/log"
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// PeerGater decides which peers the node may talk to according to the static, allowed and private peers of the config.
+// It is installed as the connection gater of the libp2p host, so the restrictions apply to ipfs connections as well as
+// to the idena protocol.
+type PeerGater struct {
+	cfg          config.P2P
+	staticPeers  map[peer.ID]peer.AddrInfo
+	privatePeers map[peer.ID]struct{}
+	allowedPeers map[peer.ID]struct{}
+}
+
+var _ connmgr.ConnectionGater = (*PeerGater)(nil)
+
+func NewPeerGater(cfg config.P2P) *PeerGater {
+	g := &PeerGater{
+		cfg:          cfg,
+		staticPeers:  make(map[peer.ID]peer.AddrInfo),
+		privatePeers: make(map[peer.ID]struct{}),
+		allowedPeers: make(map[peer.ID]struct{}),
+	}
+	staticPeers := cfg.StaticPeers
+	if cfg.SentryMode() {
+		// a validator behind sentries talks to its sentries only
+		if len(staticPeers) > 0 {
+			log.Warn("static peers are ignored in the sentry mode")
+		}
+		staticPeers = cfg.SentryNodes
+	}
+	for _, addr := range staticPeers {
+		info, err := parsePeerAddr(addr)
+		if err != nil {
+			log.Warn("invalid static peer", "addr", addr, "err", err)
+			continue
+		}
+		g.staticPeers[info.ID] = info
+	}
+	for _, id := range cfg.AllowedPeers {
+		peerId, err := peer.Decode(id)
+		if err != nil {
+			log.Warn("invalid allowed peer", "id", id, "err", err)
+			continue
+		}
+		g.allowedPeers[peerId] = struct{}{}
+	}
+	for _, id := range cfg.PrivatePeers {
+		peerId, err := peer.Decode(id)
+		if err != nil {
+			log.Warn("invalid private peer", "id", id, "err", err)
+			continue
+		}
+		g.privatePeers[peerId] = struct{}{}
+	}
+	return g
+}
+
+func parsePeerAddr(addr string) (peer.AddrInfo, error) {
+	ma, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return peer.AddrInfo{}, err
+	}
+	transportAddr, peerId := peer.SplitAddr(ma)
+	if transportAddr == nil || peerId == "" {
+		return peer.AddrInfo{}, errors.New("invalid url")
+	}
+	return peer.AddrInfo{
+		ID:    peerId,
+		Addrs: []multiaddr.Multiaddr{transportAddr},
+	}, nil
+}
+
+// IsStatic checks whether the peer is listed in the static peers of the config
+func (g *PeerGater) IsStatic(id peer.ID) bool {
+	_, ok := g.staticPeers[id]
+	return ok
+}
+
+// IsPrivate checks whether the peer is a validator behind this sentry
+func (g *PeerGater) IsPrivate(id peer.ID) bool {
+	_, ok := g.privatePeers[id]
+	return ok
+}
+
+func (g *PeerGater) StaticPeers() []peer.AddrInfo {
+	result := make([]peer.AddrInfo, 0, len(g.staticPeers))
+	for _, info := range g.staticPeers {
+		result = append(result, info)
+	}
+	return result
+}
+
+// IsTrusted checks whether the peer is a static or a private peer, trusted peers bypass limits, rotation and bans
+func (g *PeerGater) IsTrusted(id peer.ID) bool {
+	return g.IsStatic(id) || g.IsPrivate(id)
+}
+
+// IsAllowed checks whether the node may talk to the peer, only trusted and allowed peers are allowed in the allowlist
+// only mode and only sentries are allowed in the sentry mode
+func (g *PeerGater) IsAllowed(id peer.ID) bool {
+	if g.cfg.SentryMode() {
+		return g.IsStatic(id)
+	}
+	if !g.cfg.AllowlistOnly {
+		return true
+	}
+	if g.IsTrusted(id) {
+		return true
+	}
+	_, ok := g.allowedPeers[id]
+	return ok
+}
+
+func (g *PeerGater) InterceptPeerDial(id peer.ID) bool {
+	return g.IsAllowed(id)
+}
+
+func (g *PeerGater) InterceptAddrDial(id peer.ID, _ multiaddr.Multiaddr) bool {
+	return g.IsAllowed(id)
+}
+
+// InterceptAccept allows all the inbound connections since the peer is not known before the handshake
+func (g *PeerGater) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *PeerGater) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return g.IsAllowed(id)
+}
+
+func (g *PeerGater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"github.com/idena-network/idena-go/config"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/test"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func randPeerId(t *testing.T) peer.ID {
+	id, err := test.RandPeerID()
+	require.NoError(t, err)
+	return id
+}
+
+func peerAddr(id peer.ID) string {
+	return "/ip4/127.0.0.1/tcp/40405/ipfs/" + id.Pretty()
+}
+
+func TestPeerGater_Allowlist(t *testing.T) {
+	static, private, allowed, other := randPeerId(t), randPeerId(t), randPeerId(t), randPeerId(t)
+	cfg := config.P2P{
+		StaticPeers:  []string{peerAddr(static), "invalid"},
+		AllowedPeers: []string{allowed.Pretty()},
+		PrivatePeers: []string{private.Pretty()},
+	}
+
+	// all the peers are allowed by default
+	g := NewPeerGater(cfg)
+	require.True(t, g.IsStatic(static))
+	require.True(t, g.IsTrusted(private))
+	require.False(t, g.IsTrusted(allowed))
+	require.Len(t, g.StaticPeers(), 1)
+	for _, id := range []peer.ID{static, private, allowed, other} {
+		require.True(t, g.IsAllowed(id))
+	}
+
+	cfg.AllowlistOnly = true
+	g = NewPeerGater(cfg)
+	for _, id := range []peer.ID{static, private, allowed} {
+		require.True(t, g.IsAllowed(id))
+		require.True(t, g.InterceptPeerDial(id))
+		require.True(t, g.InterceptSecured(network.DirInbound, id, nil))
+	}
+	require.False(t, g.IsAllowed(other))
+	require.False(t, g.InterceptPeerDial(other))
+	require.False(t, g.InterceptAddrDial(other, nil))
+	require.False(t, g.InterceptSecured(network.DirInbound, other, nil))
+	require.True(t, g.InterceptAccept(nil))
+}
+
+func TestPeerGater_SentryMode(t *testing.T) {
+	sentry, static, allowed := randPeerId(t), randPeerId(t), randPeerId(t)
+	g := NewPeerGater(config.P2P{
+		SentryNodes:   []string{peerAddr(sentry)},
+		StaticPeers:   []string{peerAddr(static)},
+		AllowlistOnly: true,
+		AllowedPeers:  []string{allowed.Pretty()},
+	})
+	require.True(t, g.IsAllowed(sentry))
+	require.True(t, g.InterceptSecured(network.DirInbound, sentry, nil))
+	require.Equal(t, []peer.AddrInfo{{ID: sentry, Addrs: g.StaticPeers()[0].Addrs}}, g.StaticPeers())
+
+	// the validator connects to the sentries only
+	for _, id := range []peer.ID{static, allowed} {
+		require.False(t, g.IsAllowed(id))
+		require.False(t, g.InterceptPeerDial(id))
+		require.False(t, g.InterceptSecured(network.DirOutbound, id, nil))
+	}
+}
+
+func TestDialBackoff(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	b := newDialBackoff()
+	b.now = func() time.Time {
+		return now
+	}
+	id := peer.ID("peer")
+
+	// only one dial per peer is in flight
+	require.True(t, b.start(id))
+	require.False(t, b.start(id))
+	require.True(t, b.start("other"))
+
+	b.finish(id, false)
+	require.False(t, b.start(id))
+	now = now.Add(staticDialMinBackoff)
+	require.True(t, b.start(id))
+
+	// the backoff doubles with every failure
+	b.finish(id, false)
+	now = now.Add(staticDialMinBackoff)
+	require.False(t, b.start(id))
+	now = now.Add(staticDialMinBackoff)
+	require.True(t, b.start(id))
+
+	for i := 0; i < 20; i++ {
+		b.finish(id, false)
+		now = now.Add(staticDialMaxBackoff)
+		require.True(t, b.start(id))
+	}
+
+	// a successful dial resets the backoff
+	b.finish(id, true)
+	require.True(t, b.start(id))
+}
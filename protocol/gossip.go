@@ -25,8 +25,8 @@ import (
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
-	"github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -72,7 +72,13 @@ type IdenaGossipHandler struct {
 	metrics          *metricCollector
 	ceremonyChecker  CeremonyChecker
 	connManager      *ConnManager
+	gater            *PeerGater
+	scores           *peerScores
 	pubsub           *pubsub.PubSub
+
+	// requests of the light client waiting for state proofs by request id
+	stateProofRequests sync.Map
+	stateProofQueries  chan *stateProofQuery
 }
 
 type metricCollector struct {
@@ -81,9 +87,10 @@ type metricCollector struct {
 	compress       func(code uint64, size int)
 }
 
-func NewIdenaGossipHandler(host core.Host, pubsub *pubsub.PubSub, cfg config.P2P, chain *blockchain.Blockchain, proposals *pengings.Proposals, votes *pengings.Votes, txpool *mempool.TxPool, fp *flip.Flipper, bus eventbus.Bus, flipKeyPool *mempool.KeysPool, appVersion string, ceremonyChecker CeremonyChecker) *IdenaGossipHandler {
+func NewIdenaGossipHandler(host core.Host, pubsub *pubsub.PubSub, cfg config.P2P, gater *PeerGater, chain *blockchain.Blockchain, db dbm.DB, proposals *pengings.Proposals, votes *pengings.Votes, txpool *mempool.TxPool, fp *flip.Flipper, bus eventbus.Bus, flipKeyPool *mempool.KeysPool, appVersion string, ceremonyChecker CeremonyChecker) *IdenaGossipHandler {
 	logger := log.New()
 	throttlingLogger := log.NewThrottlingLogger(logger)
+	scores := newPeerScores(db)
 	handler := &IdenaGossipHandler{
 		host:                host,
 		pubsub:              pubsub,
@@ -108,7 +115,10 @@ func NewIdenaGossipHandler(host core.Host, pubsub *pubsub.PubSub, cfg config.P2P
 		pendingPeers:        make(map[peer.ID]struct{}),
 		metrics:             new(metricCollector),
 		ceremonyChecker:     ceremonyChecker,
-		connManager:         NewConnManager(host, cfg),
+		gater:               gater,
+		scores:              scores,
+		connManager:         NewConnManager(host, cfg, gater, scores),
+		stateProofQueries:   make(chan *stateProofQuery, stateProofQueueSize),
 	}
 	handler.pushPullManager.AddEntryHolder(pushVote, pushpull.NewDefaultHolder(1, pushpull.NewDefaultPushTracker(time.Millisecond*300)))
 	handler.pushPullManager.AddEntryHolder(pushBlock, pushpull.NewDefaultHolder(1, pushpull.NewDefaultPushTracker(time.Second*3)))
@@ -116,6 +126,9 @@ func NewIdenaGossipHandler(host core.Host, pubsub *pubsub.PubSub, cfg config.P2P
 	handler.pushPullManager.AddEntryHolder(pushFlip, pushpull.NewDefaultHolder(1, pushpull.NewDefaultPushTracker(time.Second*5)))
 	handler.pushPullManager.AddEntryHolder(pushKeyPackage, flipKeyPool)
 	handler.pushPullManager.AddEntryHolder(pushTx, txpool)
+	handler.pushPullManager.onPullTimeout = func(id peer.ID) {
+		handler.PenalizePeer(id, PenaltyPullTimeout, nil)
+	}
 	handler.pushPullManager.Run()
 	handler.registerMetrics()
 	return handler
@@ -126,7 +139,7 @@ func (h *IdenaGossipHandler) Start() {
 	setHandler := func() {
 		matcher, _ := helpers.MultistreamSemverMatcher(IdenaProtocol)
 		h.host.SetStreamHandlerMatch(IdenaProtocol, matcher, h.acceptStream)
-		h.connManager = NewConnManager(h.host, h.cfg)
+		h.connManager = NewConnManager(h.host, h.cfg, h.gater, h.scores)
 		notifiee := &notifiee{
 			connManager: h.connManager,
 		}
@@ -172,19 +185,28 @@ func (h *IdenaGossipHandler) Start() {
 	go h.broadcastLoop()
 	go h.checkTime()
 	go h.background()
+	for i := 0; i < stateProofWorkers; i++ {
+		go h.provideStateProofs()
+	}
 	go h.watchShardSubscription()
 }
 
 func (h *IdenaGossipHandler) background() {
 	dialTicker := time.NewTicker(time.Second * 15)
 	renewTicker := time.NewTicker(time.Minute * 5)
+	flushScoresTicker := time.NewTicker(time.Minute)
 
 	for {
 		select {
 		case <-dialTicker.C:
-			h.dialPeers()
+			h.dialStaticPeers()
+			if !h.cfg.SentryMode() {
+				h.dialPeers()
+			}
 		case <-renewTicker.C:
 			h.renewPeers()
+		case <-flushScoresTicker.C:
+			h.scores.flush()
 		}
 	}
 }
@@ -468,13 +490,25 @@ func (h *IdenaGossipHandler) handle(p *protoPeer) error {
 			return errResp(DecodeErr, "%v: %v", msg, err)
 		}
 		p.disconnectReason = dc.Reason
+	case GetStateProof:
+		query := new(models.ProtoGetStateProofRequest)
+		if err := proto.Unmarshal(msg.Payload, query); err != nil {
+			return errResp(DecodeErr, "%v: %v", msg, err)
+		}
+		h.queueStateProof(p, query)
+	case StateProof:
+		response := new(models.ProtoStateProof)
+		if err := proto.Unmarshal(msg.Payload, response); err != nil {
+			return errResp(DecodeErr, "%v: %v", msg, err)
+		}
+		h.deliverStateProof(p, response)
 	}
 
 	return nil
 }
 
 func (h *IdenaGossipHandler) acceptStream(stream network.Stream) {
-	if h.connManager.CanConnect(stream.Conn().RemotePeer()) && (h.connManager.CanAcceptStream() ||
+	if h.connManager.CanConnect(stream.Conn().RemotePeer()) && (h.connManager.CanAcceptStream() || h.connManager.IsTrusted(stream.Conn().RemotePeer()) ||
 		h.connManager.NeedInboundOwnShardPeers() || h.connManager.NeedPeerFromSomeShard(int(h.bcn.ShardsNum()))) {
 		if _, err := h.runPeer(stream, true); err != nil {
 			h.log.Debug("failed to run inbound peer", "err", err)
@@ -528,7 +562,10 @@ func (h *IdenaGossipHandler) runPeer(stream network.Stream, inbound bool) (*prot
 		return nil, err
 	}
 
-	canConnect, shouldDisconnectAnotherPeer := h.connManager.NeedPeerFromShard(inbound, peer.shardId)
+	canConnect, shouldDisconnectAnotherPeer := true, false
+	if !h.connManager.IsTrusted(peer.id) {
+		canConnect, shouldDisconnectAnotherPeer = h.connManager.NeedPeerFromShard(inbound, peer.shardId)
+	}
 
 	if !canConnect {
 		log.Info("no slots for shard, peer will be disconnected", "peerId", peer.id, "shardId", peer.shardId)
@@ -626,6 +663,23 @@ func (h *IdenaGossipHandler) dialPeers() {
 	}()
 }
 
+func (h *IdenaGossipHandler) dialStaticPeers() {
+	for _, info := range h.connManager.StaticPeers() {
+		if h.peers.Peer(info.ID) != nil || !h.connManager.StartStaticDial(info.ID) {
+			continue
+		}
+		go func(info peer.AddrInfo) {
+			stream, err := h.connManager.DialStaticPeer(info)
+			if err != nil {
+				h.log.Debug("static peer dial failed", "id", info.ID.Pretty(), "err", err)
+			} else if _, err = h.runPeer(stream, false); err != nil {
+				h.log.Debug("failed to run static peer", "id", info.ID.Pretty(), "err", err)
+			}
+			h.connManager.FinishStaticDial(info.ID, err == nil)
+		}(info)
+	}
+}
+
 func (h *IdenaGossipHandler) renewPeers() {
 	if !h.connManager.CanDial() {
 		peerId := h.connManager.GetRandomPeer(false)
@@ -645,7 +699,17 @@ func (h *IdenaGossipHandler) renewPeers() {
 }
 
 func (h *IdenaGossipHandler) BanPeer(peerId peer.ID, reason error) {
-	h.connManager.BanPeer(peerId)
+	h.PenalizePeer(peerId, PenaltyInvalidBlock, reason)
+}
+
+// PenalizePeer lowers the score of the peer and disconnects it if the score falls below the ban threshold
+func (h *IdenaGossipHandler) PenalizePeer(peerId peer.ID, penalty PeerPenalty, reason error) {
+	if !h.connManager.PenalizePeer(peerId, penalty) {
+		if reason != nil {
+			h.log.Debug("peer has been penalized", "peer", peerId, "penalty", penalty, "reason", reason)
+		}
+		return
+	}
 
 	peer := h.peers.Peer(peerId)
 	if peer != nil {
@@ -1018,6 +1082,10 @@ func (h *IdenaGossipHandler) Peers() []*protoPeer {
 	return h.peers.Peers()
 }
 
+func (h *IdenaGossipHandler) IsStaticPeer(p *protoPeer) bool {
+	return h.connManager.IsStatic(p.id)
+}
+
 func (h *IdenaGossipHandler) PeerHeights() []uint64 {
 	result := make([]uint64, 0)
 	peers := h.peers.Peers()
@@ -1039,24 +1107,15 @@ func (h *IdenaGossipHandler) Endpoint() string {
 }
 
 func (h *IdenaGossipHandler) AddPeer(url string) error {
-	ma, err := multiaddr.NewMultiaddr(url)
+	info, err := parsePeerAddr(url)
 
 	if err != nil {
 		return err
 	}
 
-	transportAddr, peerId := peer.SplitAddr(ma)
-
-	if transportAddr == nil || peerId == "" {
-		return errors.New("invalid url")
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 
-	err = h.host.Connect(ctx, peer.AddrInfo{
-		ID:    peerId,
-		Addrs: []multiaddr.Multiaddr{transportAddr},
-	})
+	err = h.host.Connect(ctx, info)
 	cancel()
 	return err
 }
@@ -1070,6 +1129,10 @@ func (h *IdenaGossipHandler) IsConnected(id peer.ID) bool {
 }
 
 func (h *IdenaGossipHandler) watchShardSubscription() {
+	// shard topics announce the subscriber to the network, a validator behind sentries keeps its peer id private
+	if h.cfg.SentryMode() {
+		return
+	}
 	var topic *pubsub.Topic
 	var topicShard common.ShardId
 	var sub *pubsub.Subscription
@@ -0,0 +1,176 @@
+package protocol
+
+import (
+	"github.com/deckarep/golang-set"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/core/state/stateproof"
+	"github.com/idena-network/idena-go/core/upgrade"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/idena-network/idena-go/keystore"
+	"github.com/idena-network/idena-go/log"
+	models "github.com/idena-network/idena-go/protobuf"
+	"github.com/idena-network/idena-go/subscriptions"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	stateProofTimeout = time.Second * 10
+
+	// state proofs are served by stateProofWorkers workers, requests which do not fit the queue are rejected at once
+	stateProofWorkers   = 4
+	stateProofQueueSize = 100
+
+	// a peer may request stateProofBurst proofs at once and one more proof every stateProofInterval
+	stateProofInterval = time.Millisecond * 100
+	stateProofBurst    = 20
+)
+
+var stateProofReqId uint32
+
+// lightSync downloads headers with certificates and identity state diffs only, the state itself is never downloaded
+// and the preliminary head serves as the head of the light client
+type lightSync struct {
+	*fastSync
+}
+
+func NewLightSync(pm *IdenaGossipHandler, log log.Logger,
+	chain *blockchain.Blockchain,
+	ipfs ipfs.Proxy,
+	appState *appstate.AppState,
+	potentialForkedPeers mapset.Set,
+	sm *state.SnapshotManager, bus eventbus.Bus, coinbase common.Address, keyStore *keystore.KeyStore,
+	subManager *subscriptions.Manager, upgrader *upgrade.Upgrader) *lightSync {
+	return &lightSync{
+		fastSync: NewFastSync(pm, log, chain, ipfs, appState, potentialForkedPeers, nil, sm, bus, coinbase, keyStore, subManager, upgrader),
+	}
+}
+
+// postConsuming keeps the verified headers, headers after the last certificate are requested again by the next sync
+func (ls *lightSync) postConsuming() error {
+	if ls.chain.PreliminaryHead != nil {
+		ls.log.Info("Light head has been updated", "height", ls.chain.PreliminaryHead.Height())
+	}
+	return nil
+}
+
+type pendingStateProof struct {
+	peer     peer.ID
+	response chan *models.ProtoStateProof
+}
+
+type stateProofQuery struct {
+	peer  *protoPeer
+	query *models.ProtoGetStateProofRequest
+}
+
+func newStateProofLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Every(stateProofInterval), stateProofBurst)
+}
+
+// queueStateProof passes the request to the workers, the request is rejected if the peer exceeds its rate limit or the
+// workers are busy
+func (h *IdenaGossipHandler) queueStateProof(p *protoPeer, query *models.ProtoGetStateProofRequest) {
+	if !p.stateProofLimiter.Allow() {
+		p.sendMsg(StateProof, &models.ProtoStateProof{ReqId: query.ReqId, Error: "rate limit exceeded"}, common.MultiShard, false)
+		return
+	}
+	select {
+	case h.stateProofQueries <- &stateProofQuery{peer: p, query: query}:
+	default:
+		p.sendMsg(StateProof, &models.ProtoStateProof{ReqId: query.ReqId, Error: "too many requests"}, common.MultiShard, false)
+	}
+}
+
+func (h *IdenaGossipHandler) provideStateProofs() {
+	for q := range h.stateProofQueries {
+		response := &models.ProtoStateProof{
+			ReqId: q.query.ReqId,
+		}
+		value, proof, err := h.bcn.StateProof(q.query.Height, common.BytesToAddress(q.query.Address), q.query.Key)
+		if err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Value = value
+			response.Proof = proof
+		}
+		q.peer.sendMsg(StateProof, response, common.MultiShard, false)
+	}
+}
+
+func (h *IdenaGossipHandler) deliverStateProof(p *protoPeer, response *models.ProtoStateProof) {
+	if pending, ok := h.stateProofRequests.Load(response.ReqId); ok && pending.(*pendingStateProof).peer == p.id {
+		select {
+		case pending.(*pendingStateProof).response <- response:
+		default:
+		}
+	}
+}
+
+func (h *IdenaGossipHandler) requestStateProof(p *protoPeer, height uint64, addr common.Address, key []byte) (value []byte, proof []byte, err error) {
+	id := atomic.AddUint32(&stateProofReqId, 1)
+	pending := &pendingStateProof{
+		peer:     p.id,
+		response: make(chan *models.ProtoStateProof, 1),
+	}
+	h.stateProofRequests.Store(id, pending)
+	defer h.stateProofRequests.Delete(id)
+
+	p.sendMsg(GetStateProof, &models.ProtoGetStateProofRequest{
+		ReqId:   id,
+		Height:  height,
+		Address: addr.Bytes(),
+		Key:     key,
+	}, common.MultiShard, false)
+
+	select {
+	case response := <-pending.response:
+		if response.Error != "" {
+			return nil, nil, errors.New(response.Error)
+		}
+		if len(response.Value) == 0 {
+			return nil, response.Proof, nil
+		}
+		return response.Value, response.Proof, nil
+	case <-time.After(stateProofTimeout):
+		return nil, nil, errors.New("state proof request timeout")
+	}
+}
+
+// GetStateProof requests the encoded account of the address or the value stored by the contract under the key if the
+// key is not empty from peers and verifies the proof against the state root of the local header at the height,
+// nil value means that the account or the value is absent
+func (h *IdenaGossipHandler) GetStateProof(height uint64, addr common.Address, key []byte) ([]byte, error) {
+	header := h.bcn.GetBlockHeaderByHeight(height)
+	if header == nil {
+		return nil, errors.Errorf("header %v is not found", height)
+	}
+	for _, p := range h.peers.Peers() {
+		if p.knownHeight.Read() < height {
+			continue
+		}
+		value, proof, err := h.requestStateProof(p, height, addr, key)
+		if err != nil {
+			p.log.Debug("state proof request failed", "err", err)
+			continue
+		}
+		if len(key) == 0 {
+			_, err = stateproof.VerifyAccount(header.Root(), addr, value, proof)
+		} else {
+			err = stateproof.VerifyContractValue(header.Root(), addr, key, value, proof)
+		}
+		if err != nil {
+			h.PenalizePeer(p.id, PenaltyInvalidProof, errors.Wrap(err, "invalid state proof"))
+			continue
+		}
+		return value, nil
+	}
+	return nil, errors.New("no peers provided the state proof")
+}
@@ -0,0 +1,151 @@
+package protocol
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/state/stateproof"
+	"github.com/idena-network/idena-go/crypto"
+	models "github.com/idena-network/idena-go/protobuf"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestPeer(id peer.ID) *protoPeer {
+	return &protoPeer{
+		id:                id,
+		queuedRequests:    make(chan *request, queuedRequestsSize),
+		finished:          make(chan struct{}),
+		stateProofLimiter: newStateProofLimiter(),
+	}
+}
+
+// receiveMsg takes the message sent to the peer and decodes it as the remote peer does
+func receiveMsg(t *testing.T, p *protoPeer, code uint64, msg proto.Message) {
+	select {
+	case req := <-p.queuedRequests:
+		require.Equal(t, code, req.msgcode)
+		data, err := toBytes(req.msgcode, req.data)
+		require.NoError(t, err)
+		require.NoError(t, proto.Unmarshal(data, msg))
+	case <-time.After(time.Second):
+		require.Fail(t, "message is not sent")
+	}
+}
+
+func TestStateProof_Messages(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chain, _, _, _ := blockchain.NewTestBlockchain(false, map[common.Address]config.GenesisAllocation{
+		addr: {Balance: big.NewInt(100)},
+	})
+	server := &IdenaGossipHandler{
+		bcn:               chain.Blockchain,
+		stateProofQueries: make(chan *stateProofQuery, stateProofQueueSize),
+	}
+	go server.provideStateProofs()
+	client := &IdenaGossipHandler{}
+	serverPeer, clientPeer := newTestPeer("server"), newTestPeer("client")
+
+	request := func(height uint64) (value []byte, proof []byte, err error) {
+		done := make(chan struct{})
+		go func() {
+			value, proof, err = client.requestStateProof(serverPeer, height, addr, nil)
+			close(done)
+		}()
+		query := new(models.ProtoGetStateProofRequest)
+		receiveMsg(t, serverPeer, GetStateProof, query)
+		server.queueStateProof(clientPeer, query)
+		response := new(models.ProtoStateProof)
+		receiveMsg(t, clientPeer, StateProof, response)
+		// responses from other peers are ignored
+		client.deliverStateProof(clientPeer, response)
+		client.deliverStateProof(serverPeer, response)
+		<-done
+		return value, proof, err
+	}
+
+	head := chain.Head
+	value, proof, err := request(head.Height())
+	require.NoError(t, err)
+	account, err := stateproof.VerifyAccount(head.Root(), addr, value, proof)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), account.Balance)
+
+	_, _, err = request(head.Height() + 1)
+	require.Error(t, err)
+}
+
+func TestStateProof_Limits(t *testing.T) {
+	h := &IdenaGossipHandler{
+		stateProofQueries: make(chan *stateProofQuery, stateProofQueueSize),
+	}
+	p := newTestPeer("peer")
+	rejected := func() bool {
+		select {
+		case req := <-p.queuedRequests:
+			require.Equal(t, uint64(StateProof), req.msgcode)
+			require.NotEmpty(t, req.data.(*models.ProtoStateProof).Error)
+			return true
+		default:
+			return false
+		}
+	}
+
+	// the peer exceeds its rate limit
+	for i := 0; i < stateProofBurst; i++ {
+		h.queueStateProof(p, &models.ProtoGetStateProofRequest{ReqId: uint32(i)})
+		require.False(t, rejected())
+	}
+	h.queueStateProof(p, &models.ProtoGetStateProofRequest{})
+	require.True(t, rejected())
+	require.Len(t, h.stateProofQueries, stateProofBurst)
+
+	// the workers are busy
+	for i := 0; len(h.stateProofQueries) < stateProofQueueSize; i++ {
+		h.queueStateProof(newTestPeer("other"), &models.ProtoGetStateProofRequest{})
+	}
+	p = newTestPeer("peer")
+	h.queueStateProof(p, &models.ProtoGetStateProofRequest{})
+	require.True(t, rejected())
+}
+
+func TestLightSync_ValidateHeader(t *testing.T) {
+	chain, appState, _, _ := blockchain.NewTestBlockchain(true, nil)
+	chain.GenerateEmptyBlocks(3)
+	headers := make([]*types.Header, 0, 4)
+	for height := uint64(1); height <= 4; height++ {
+		headers = append(headers, chain.GetBlockHeaderByHeight(height))
+	}
+	ls := &lightSync{fastSync: &fastSync{
+		chain:             chain.Blockchain,
+		validators:        appState.ValidatorsCache,
+		pubKeyToAddrCache: map[string]common.Address{},
+	}}
+	chain.PreliminaryHead = headers[0]
+	newBlock := func(header *types.Header, certHeader *types.Header) *block {
+		return &block{Header: header, Cert: chain.GetCertificate(certHeader.Hash())}
+	}
+
+	require.NoError(t, ls.validateHeader(newBlock(headers[1], headers[1])))
+	require.Error(t, ls.validateHeader(newBlock(headers[1], headers[2])))
+	// the header must follow the light head
+	require.Error(t, ls.validateHeader(newBlock(headers[2], headers[2])))
+
+	forked := *headers[1].EmptyBlockHeader
+	forked.ParentHash = common.Hash{0x1}
+	require.Equal(t, blockchain.ParentHashIsInvalid,
+		ls.validateHeader(&block{Header: &types.Header{EmptyBlockHeader: &forked}, Cert: &types.BlockCert{}}))
+
+	// headers which change the identity state must be certified
+	require.NoError(t, ls.validateHeader(&block{Header: headers[1], Cert: &types.BlockCert{}}))
+	flagged := *headers[1].EmptyBlockHeader
+	flagged.Flags = types.IdentityUpdate
+	require.Equal(t, BlockCertIsMissing,
+		ls.validateHeader(&block{Header: &types.Header{EmptyBlockHeader: &flagged}, Cert: &types.BlockCert{}}))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/idena-network/idena-go/log"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/rcrowley/go-metrics"
 	"strconv"
 	"strings"
@@ -55,12 +56,15 @@ type peersRateMetrics struct {
 	rmsByPeer map[string]*rateMetrics
 	enabled   func() bool
 	mutex     sync.RWMutex
+	// onRateIn receives the rate of data received from the peer for each period
+	onRateIn func(peerId string, rateKbs float64)
 }
 
-func newPeersRateMetrics(enabled func() bool) *peersRateMetrics {
+func newPeersRateMetrics(enabled func() bool, onRateIn func(peerId string, rateKbs float64)) *peersRateMetrics {
 	return &peersRateMetrics{
 		enabled:   enabled,
 		rmsByPeer: make(map[string]*rateMetrics),
+		onRateIn:  onRateIn,
 	}
 }
 
@@ -123,6 +127,9 @@ func (rm *peersRateMetrics) loopLog(logger log.Logger) {
 			var rateIn, rateOut float64
 			if durationIn > 0 {
 				rateIn = float64(sizeIn) / float64(1024) / float64(durationIn) * float64(time.Second)
+				if rm.onRateIn != nil {
+					rm.onRateIn(peerId, rateIn)
+				}
 			}
 			if durationOut > 0 {
 				rateOut = float64(sizeOut) / float64(1024) / float64(durationOut) * float64(time.Second)
@@ -144,7 +151,9 @@ func (h *IdenaGossipHandler) registerMetrics() {
 	totalSent := metrics.GetOrRegisterCounter("bs.total", metrics.DefaultRegistry)
 	totalReceived := metrics.GetOrRegisterCounter("br.total", metrics.DefaultRegistry)
 	compressTotal := metrics.GetOrRegisterCounter("cd.total", metrics.DefaultRegistry)
-	rate := newPeersRateMetrics(h.ceremonyChecker.IsRunning)
+	rate := newPeersRateMetrics(h.ceremonyChecker.IsRunning, func(peerId string, rateKbs float64) {
+		h.scores.rewardBandwidth(peer.ID(peerId), rateKbs)
+	})
 
 	msgCodeToString := func(code uint64) string {
 		switch code {
@@ -184,6 +193,10 @@ func (h *IdenaGossipHandler) registerMetrics() {
 			return "batchPush"
 		case BatchFlipKey:
 			return "batchFlipKey"
+		case GetStateProof:
+			return "getStateProof"
+		case StateProof:
+			return "stateProof"
 		default:
 			return fmt.Sprintf("unknown code %v", code)
 		}
@@ -200,6 +213,7 @@ func (h *IdenaGossipHandler) registerMetrics() {
 		GetBlockByHash,
 		GetBlocksRange,
 		GetForkBlockRange,
+		GetStateProof,
 		Handshake,
 		NewTx,
 		ProposeBlock,
@@ -207,6 +221,7 @@ func (h *IdenaGossipHandler) registerMetrics() {
 		Pull,
 		Push,
 		SnapshotManifest,
+		StateProof,
 		Vote,
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/libp2p/go-msgio"
 	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	"math"
 	"math/rand"
 	"strings"
@@ -103,6 +104,7 @@ type protoPeer struct {
 	closed               bool
 	supportedFeatures    map[PeerFeature]struct{}
 	disconnectReason     string
+	stateProofLimiter    *rate.Limiter
 }
 
 func newPeer(stream network.Stream, maxDelayMs int, metrics *metricCollector) *protoPeer {
@@ -142,6 +144,7 @@ func newPeer(stream network.Stream, maxDelayMs int, metrics *metricCollector) *p
 		potentialHeight:      &syncHeight{},
 		version:              vers,
 		supportedFeatures:    map[PeerFeature]struct{}{},
+		stateProofLimiter:    newStateProofLimiter(),
 	}
 	SetSupportedFeatures(p)
 	return p
@@ -385,6 +388,10 @@ func toBytes(msgcode uint64, payload interface{}) ([]byte, error) {
 		return payload.(*msgBatch).ToBytes()
 	case Disconnect:
 		return payload.(*disconnect).ToBytes()
+	case GetStateProof:
+		return proto.Marshal(payload.(*models.ProtoGetStateProofRequest))
+	case StateProof:
+		return proto.Marshal(payload.(*models.ProtoStateProof))
 	}
 	return nil, errors.Errorf("type %T is not serializable", payload)
 }
@@ -22,7 +22,10 @@ type pullRequest struct {
 }
 
 type PushPullManager struct {
-	pendingPushes    *cache.Cache
+	pendingPushes *cache.Cache
+	// lastPulls keeps the peer which has been asked for the entry last time
+	lastPulls        *cache.Cache
+	onPullTimeout    func(id peer.ID)
 	mutex            sync.Mutex
 	requests         chan pullRequest
 	entryHolders     map[pushType]pushpull.Holder
@@ -32,6 +35,7 @@ type PushPullManager struct {
 func NewPushPullManager() *PushPullManager {
 	return &PushPullManager{
 		pendingPushes:    cache.New(time.Minute*3, time.Minute*5),
+		lastPulls:        cache.New(time.Minute*3, time.Minute*5),
 		requests:         make(chan pullRequest, 5000),
 		entryHolders:     make(map[pushType]pushpull.Holder),
 		throttlingLogger: log.NewThrottlingLogger(log.New("component", "pushPullManager")),
@@ -92,6 +96,7 @@ func (m *PushPullManager) addPush(id peer.ID, hash pushPullHash) {
 func (m *PushPullManager) makeRequest(peer peer.ID, hash pushPullHash) {
 	select {
 	case m.requests <- pullRequest{peer: peer, hash: hash}:
+		m.lastPulls.SetDefault(hash.String(), peer)
 	default:
 		m.throttlingLogger.Warn("Pull request skipped")
 	}
@@ -120,10 +125,15 @@ func (m *PushPullManager) Run() {
 func (m *PushPullManager) loop(entryType pushType, holder pushpull.Holder) {
 	for {
 		req := <-holder.PushTracker().Requests()
-		m.makeRequest(req.Id, pushPullHash{
+		hash := pushPullHash{
 			Type: entryType,
 			Hash: req.Hash,
-		})
+		}
+		// the tracker repeats the pull from another peer only if the previous one has not responded in time
+		if prev, ok := m.lastPulls.Get(hash.String()); ok && m.onPullTimeout != nil {
+			m.onPullTimeout(prev.(peer.ID))
+		}
+		m.makeRequest(req.Id, hash)
 		holder.PushTracker().RegisterPull(req.Hash)
 	}
 }
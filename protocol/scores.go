@@ -0,0 +1,222 @@
+package protocol
+
+import (
+	"container/heap"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/libp2p/go-libp2p-core/peer"
+	dbm "github.com/tendermint/tm-db"
+	"math"
+	"sync"
+	"time"
+)
+
+type PeerPenalty float64
+
+const (
+	// PenaltyInvalidBlock is applied to peers which provide invalid blocks, it bans the peer at once
+	PenaltyInvalidBlock PeerPenalty = 100
+	// PenaltyInvalidProof is applied to peers which provide state proofs not matching the state root of the header
+	PenaltyInvalidProof PeerPenalty = 100
+	// PenaltyFailedBatch is applied to peers which fail to provide the requested blocks range
+	PenaltyFailedBatch PeerPenalty = 20
+	// PenaltyTimeout is applied to peers which repeatedly exceed the timeout of the blocks range
+	PenaltyTimeout PeerPenalty = 10
+	// PenaltyPullTimeout is applied to peers which do not respond to a pull in time
+	PenaltyPullTimeout PeerPenalty = 1
+)
+
+const (
+	// peers with lower scores are banned
+	banScore = -50
+	maxScore = 100
+	// scores decay towards zero, a peer banned with PenaltyInvalidBlock is unbanned after the half-life
+	scoreHalfLife = time.Hour * 12
+	// scores closer to zero are forgotten
+	minStoredScore = 0.5
+	maxScoredPeers = 500000
+
+	// a peer gets the max bandwidth reward for each rate metric period if its rate reaches goodRateKbs
+	maxBandwidthReward = 1
+	goodRateKbs        = 256
+)
+
+type peerScore struct {
+	id      peer.ID
+	value   float64
+	updated time.Time
+	// index of the score in the heap
+	index int
+}
+
+func (s *peerScore) decayed(now time.Time) float64 {
+	elapsed := now.Sub(s.updated)
+	if elapsed <= 0 {
+		return s.value
+	}
+	return s.value * math.Pow(0.5, float64(elapsed)/float64(scoreHalfLife))
+}
+
+// strength is the log2 of the absolute decayed score shifted by a value which is the same for all the scores, all the
+// scores decay at the same rate so the order of the strengths doesn't change with time
+func (s *peerScore) strength() float64 {
+	return math.Log2(math.Abs(s.value)) + float64(s.updated.UnixNano())/float64(scoreHalfLife)
+}
+
+// scoresHeap keeps the score closest to zero on the top
+type scoresHeap []*peerScore
+
+func (h scoresHeap) Len() int { return len(h) }
+
+func (h scoresHeap) Less(i, j int) bool { return h[i].strength() < h[j].strength() }
+
+func (h scoresHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scoresHeap) Push(x interface{}) {
+	score := x.(*peerScore)
+	score.index = len(*h)
+	*h = append(*h, score)
+}
+
+func (h *scoresHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	score := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return score
+}
+
+// peerScores keeps reputations of peers in the node db, scores are lowered by penalties, raised by bandwidth rewards
+// and decay towards zero with time. Changed scores are saved to the db by flush.
+type peerScores struct {
+	repo   *database.Repo
+	db     dbm.DB
+	scores map[peer.ID]*peerScore
+	heap   scoresHeap
+	// dirty peers have scores which are not saved yet, the score is deleted from the db if the peer has no score
+	dirty map[peer.ID]struct{}
+	mutex sync.RWMutex
+	now   func() time.Time
+}
+
+func newPeerScores(db dbm.DB) *peerScores {
+	s := &peerScores{
+		repo:   database.NewRepo(db),
+		db:     db,
+		scores: make(map[peer.ID]*peerScore),
+		dirty:  make(map[peer.ID]struct{}),
+		now:    time.Now,
+	}
+	now := s.now()
+	s.repo.IteratePeerScores(func(id string, value float64, updated int64) {
+		score := &peerScore{id: peer.ID(id), value: value, updated: time.Unix(updated, 0)}
+		if math.Abs(score.decayed(now)) < minStoredScore {
+			s.dirty[score.id] = struct{}{}
+			return
+		}
+		s.scores[score.id] = score
+		s.heap = append(s.heap, score)
+	})
+	for i, score := range s.heap {
+		score.index = i
+	}
+	heap.Init(&s.heap)
+	s.flush()
+	return s
+}
+
+func (s *peerScores) score(id peer.ID) float64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if score, ok := s.scores[id]; ok {
+		return score.decayed(s.now())
+	}
+	return 0
+}
+
+func (s *peerScores) isBanned(id peer.ID) bool {
+	return s.score(id) < banScore
+}
+
+// add changes the score of the peer and returns the new score
+func (s *peerScores) add(id peer.ID, delta float64) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := s.now()
+	score, ok := s.scores[id]
+	var value float64
+	if ok {
+		value = score.decayed(now)
+	}
+	value = math.Min(value+delta, maxScore)
+	if math.Abs(value) < minStoredScore {
+		if ok {
+			s.remove(score)
+		}
+		return value
+	}
+	if ok {
+		score.value, score.updated = value, now
+		heap.Fix(&s.heap, score.index)
+	} else {
+		if len(s.scores) >= maxScoredPeers {
+			s.remove(s.heap[0])
+		}
+		score = &peerScore{id: id, value: value, updated: now}
+		s.scores[id] = score
+		heap.Push(&s.heap, score)
+	}
+	s.dirty[id] = struct{}{}
+	return value
+}
+
+func (s *peerScores) remove(score *peerScore) {
+	heap.Remove(&s.heap, score.index)
+	delete(s.scores, score.id)
+	s.dirty[score.id] = struct{}{}
+}
+
+func (s *peerScores) penalize(id peer.ID, penalty PeerPenalty) float64 {
+	return s.add(id, -float64(penalty))
+}
+
+// rewardBandwidth raises the score of the peer proportionally to the rate of data received from it
+func (s *peerScores) rewardBandwidth(id peer.ID, rateKbs float64) {
+	if rateKbs <= 0 {
+		return
+	}
+	s.add(id, maxBandwidthReward*math.Min(rateKbs/goodRateKbs, 1))
+}
+
+// dialWeight is positive for peers which are not banned, peers with higher scores get higher weights
+func (s *peerScores) dialWeight(id peer.ID) float64 {
+	return math.Max(s.score(id)-banScore, 0)
+}
+
+// flush saves the changed scores to the db in one batch
+func (s *peerScores) flush() {
+	s.mutex.Lock()
+	if len(s.dirty) == 0 {
+		s.mutex.Unlock()
+		return
+	}
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	for id := range s.dirty {
+		if score, ok := s.scores[id]; ok {
+			s.repo.WritePeerScore(batch, string(id), score.value, score.updated.Unix())
+		} else {
+			s.repo.DeletePeerScore(batch, string(id))
+		}
+	}
+	s.dirty = make(map[peer.ID]struct{})
+	s.mutex.Unlock()
+	if err := batch.Write(); err != nil {
+		log.Error("failed to save peer scores", "err", err)
+	}
+}
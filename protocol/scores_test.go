@@ -0,0 +1,190 @@
+package protocol
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/pushpull"
+	"github.com/idena-network/idena-go/database"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+	"testing"
+	"time"
+)
+
+func newTestPeerScores(database db.DB, now *time.Time) *peerScores {
+	s := newPeerScores(database)
+	s.now = func() time.Time {
+		return *now
+	}
+	return s
+}
+
+func TestPeerScores_Decay(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	s := newTestPeerScores(db.NewMemDB(), &now)
+	id := peer.ID("peer")
+
+	require.Equal(t, float64(-40), s.penalize(id, 40))
+	now = now.Add(scoreHalfLife)
+	require.InDelta(t, -20, s.score(id), 1e-9)
+	now = now.Add(scoreHalfLife)
+	require.InDelta(t, -10, s.score(id), 1e-9)
+
+	// the penalty is added to the decayed score
+	require.InDelta(t, -15, s.penalize(id, 5), 1e-9)
+
+	// scores close to zero are forgotten
+	now = now.Add(scoreHalfLife * 10)
+	require.InDelta(t, 0.5, s.add(id, 0.5), 0.1)
+	require.Zero(t, s.score(id))
+	require.NotContains(t, s.scores, id)
+
+	require.Equal(t, float64(maxScore), s.add(id, maxScore*2))
+}
+
+func TestPeerScores_Ban(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	s := newTestPeerScores(db.NewMemDB(), &now)
+	id := peer.ID("peer")
+
+	s.penalize(id, PenaltyFailedBatch)
+	s.penalize(id, PenaltyFailedBatch)
+	require.False(t, s.isBanned(id))
+	require.Positive(t, s.dialWeight(id))
+
+	s.penalize(id, PenaltyInvalidBlock)
+	require.True(t, s.isBanned(id))
+	require.Zero(t, s.dialWeight(id))
+
+	s.rewardBandwidth(id, goodRateKbs*10)
+	require.True(t, s.isBanned(id))
+
+	// a peer banned with PenaltyInvalidBlock is unbanned after the half-life
+	other := peer.ID("other")
+	s.penalize(other, PenaltyInvalidBlock)
+	now = now.Add(scoreHalfLife - time.Minute)
+	require.True(t, s.isBanned(other))
+	now = now.Add(time.Minute * 2)
+	require.False(t, s.isBanned(other))
+}
+
+func TestPeerScores_Flush(t *testing.T) {
+	now := time.Now()
+	memDb := db.NewMemDB()
+	s := newTestPeerScores(memDb, &now)
+	s.penalize("peer1", 30)
+	s.add("peer2", 10)
+	s.add("peer3", 10)
+
+	count := func() int {
+		var cnt int
+		database.NewRepo(memDb).IteratePeerScores(func(id string, score float64, updated int64) {
+			cnt++
+		})
+		return cnt
+	}
+	// scores are saved by flush only
+	require.Zero(t, count())
+	s.flush()
+	require.Equal(t, 3, count())
+
+	s.add("peer3", -10)
+	s.flush()
+	require.Equal(t, 2, count())
+
+	restored := newTestPeerScores(memDb, &now)
+	require.InDelta(t, -30, restored.score("peer1"), 0.1)
+	require.InDelta(t, 10, restored.score("peer2"), 0.1)
+	require.Zero(t, restored.score("peer3"))
+}
+
+func TestPeerScores_Weakest(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	s := newTestPeerScores(db.NewMemDB(), &now)
+
+	s.add("peer1", 8)
+	s.penalize("peer2", 3)
+	now = now.Add(scoreHalfLife)
+	s.add("peer3", 2)
+	s.penalize("peer4", 20)
+	require.Equal(t, peer.ID("peer2"), s.heap[0].id)
+
+	// the decayed peer1 score is 4, peer3 score is 2
+	s.penalize("peer2", 10)
+	require.Equal(t, peer.ID("peer3"), s.heap[0].id)
+
+	now = now.Add(scoreHalfLife * 3)
+	require.Equal(t, peer.ID("peer3"), s.heap[0].id)
+	s.add("peer3", 1)
+	require.Equal(t, peer.ID("peer1"), s.heap[0].id)
+
+	s.add("peer1", -s.score("peer1"))
+	require.NotContains(t, s.scores, peer.ID("peer1"))
+	require.Len(t, s.heap, 3)
+	for i, score := range s.heap {
+		require.Equal(t, i, score.index)
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		require.Equal(t, 1, pickWeighted([]float64{0, 1, 0}))
+	}
+	picked := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		idx := pickWeighted([]float64{0, 0, 0})
+		require.True(t, idx >= 0 && idx < 3)
+		picked[pickWeighted([]float64{1, 9})]++
+	}
+	require.Greater(t, picked[1], picked[0])
+	require.Positive(t, picked[0])
+}
+
+func TestPushPullManager_PullTimeout(t *testing.T) {
+	m := NewPushPullManager()
+	timedOut := make(chan peer.ID, 10)
+	m.onPullTimeout = func(id peer.ID) {
+		timedOut <- id
+	}
+	holder := pushpull.NewDefaultHolder(1, pushpull.NewDefaultPushTracker(time.Millisecond*50))
+	m.AddEntryHolder(pushVote, holder)
+	m.Run()
+
+	receiveRequest := func() peer.ID {
+		select {
+		case req := <-m.Requests():
+			return req.peer
+		case <-time.After(time.Second):
+			require.Fail(t, "pull request is not received")
+			return ""
+		}
+	}
+
+	// the first pushes are pulled at once, the rest ones wait for the timeout of the last pull
+	hash := pushPullHash{Type: pushVote, Hash: common.Hash128{0x1}}
+	for _, id := range []peer.ID{"peer1", "peer2", "peer3"} {
+		m.addPush(id, hash)
+	}
+	require.Equal(t, peer.ID("peer1"), receiveRequest())
+	require.Equal(t, peer.ID("peer2"), receiveRequest())
+
+	// the entry is pulled from the pending peer and the peer pulled last is blamed for the timeout
+	require.Equal(t, peer.ID("peer3"), receiveRequest())
+	require.Equal(t, peer.ID("peer2"), <-timedOut)
+
+	// the peer which delivers the entry in time is not blamed
+	delivered := pushPullHash{Type: pushVote, Hash: common.Hash128{0x2}}
+	for _, id := range []peer.ID{"peer4", "peer5", "peer6"} {
+		m.addPush(id, delivered)
+	}
+	require.Equal(t, peer.ID("peer4"), receiveRequest())
+	require.Equal(t, peer.ID("peer5"), receiveRequest())
+	m.AddEntry(delivered, struct{}{}, common.MultiShard, false)
+	select {
+	case id := <-timedOut:
+		require.Failf(t, "unexpected pull timeout", "%v", id)
+	case req := <-m.Requests():
+		require.Failf(t, "unexpected pull request", "%v", req.peer)
+	case <-time.After(time.Millisecond * 200):
+	}
+}
@@ -12,7 +12,6 @@ const (
 	DecodeErr                  = 1
 	ValidationErr              = 2
 	MaxTimestampLagSeconds     = 15
-	MaxBannedPeers             = 500000
 	IdenaProtocolWeight        = 25
 	ReconnectAfterDiscTimeout  = time.Minute * 1
 	ReconnectAfterResetTimeout = time.Minute * 3
@@ -35,7 +35,34 @@ type Config struct {
 	// for ephemeral nodes).
 	HTTPPort int `toml:",omitempty"`
 
+	// WSHost is the host interface on which to start the websocket RPC server. If
+	// this field is empty, no websocket API endpoint will be started.
+	WSHost string `toml:",omitempty"`
+
+	// WSPort is the TCP port number on which to start the websocket RPC server.
+	WSPort int `toml:",omitempty"`
+
+	// WSOrigins is the list of domain to accept websocket requests from. Please be
+	// aware that the server can only act upon the HTTP request the client sends and
+	// cannot verify the validity of the request header. If the list is empty, only
+	// same-origin and localhost requests are accepted.
+	WSOrigins []string `toml:",omitempty"`
+
+	// WSModules is a list of API modules to expose via the websocket RPC interface.
+	// If the module list is empty, all RPC API endpoints designated public will be
+	// exposed.
+	WSModules []string `toml:",omitempty"`
+
+	// Limits restricts size of the requests, number of calls in batch requests and
+	// execution time of the calls for both HTTP and websocket interfaces.
+	Limits Limits
+
+	// APIKey grants access to all the exposed methods.
 	APIKey string
+
+	// APIKeys is a list of named keys limited to a set of modules or methods. The list
+	// is reloaded from the configuration file while the node is running.
+	APIKeys []*APIKey `toml:",omitempty"`
 }
 
 func (c *Config) HTTPEndpoint() string {
@@ -45,14 +72,24 @@ func (c *Config) HTTPEndpoint() string {
 	return fmt.Sprintf("%s:%d", c.HTTPHost, c.HTTPPort)
 }
 
-func GetDefaultRPCConfig(host string, port int) *Config {
+func (c *Config) WSEndpoint() string {
+	if c.WSHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.WSHost, c.WSPort)
+}
+
+func GetDefaultRPCConfig(host string, port int, wsPort int) *Config {
 	// DefaultConfig contains reasonable default settings.
 	return &Config{
 		HTTPCors:         []string{"*"},
 		HTTPHost:         host,
 		HTTPPort:         port,
-		HTTPModules:      []string{"net", "dna", "account", "flip", "bcn", "ipfs", "contract"},
+		HTTPModules:      []string{"net", "dna", "account", "flip", "bcn", "ipfs", "contract", "node", "light"},
 		HTTPVirtualHosts: []string{"localhost"},
 		HTTPTimeouts:     DefaultHTTPTimeouts,
+		Limits:           DefaultLimits,
+		WSPort:           wsPort,
+		WSModules:        []string{"net", "dna", "account", "flip", "bcn", "ipfs", "contract", "node", "light"},
 	}
 }
@@ -71,3 +71,29 @@ type invalidApiKeyError struct{}
 func (e *invalidApiKeyError) ErrorCode() int { return -32800 }
 
 func (e *invalidApiKeyError) Error() string { return "the provided API key is invalid" }
+
+// api key is valid but it doesn't grant access to the method
+type methodNotAllowedError struct {
+	service string
+	method  string
+}
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32801 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("the provided API key is not allowed to call %s%s%s", e.service, serviceMethodSeparator, e.method)
+}
+
+// call or batch request didn't complete in time
+type timeoutError struct{}
+
+func (e *timeoutError) ErrorCode() int { return -32002 }
+
+func (e *timeoutError) Error() string { return "request timed out" }
+
+// request exceeds one of the configured limits
+type limitExceededError struct{ message string }
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string { return e.message }
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	healthPath = "/health"
+	readyPath  = "/ready"
+)
+
+// HealthChecker reports the node status to the health and readiness probes.
+type HealthChecker interface {
+	// Health reports whether the node is alive, the node failing the check should be restarted.
+	Health() (report interface{}, ok bool)
+	// Ready reports whether the node is able to serve requests.
+	Ready() (report interface{}, ok bool)
+}
+
+// SetHealthChecker enables the health and readiness endpoints. It should be called before the server starts serving requests.
+func (srv *Server) SetHealthChecker(checker HealthChecker) {
+	srv.health = checker
+}
+
+// isHealthCheck reports whether the request is sent to the health or readiness endpoint
+func isHealthCheck(r *http.Request) bool {
+	return r.Method == http.MethodGet && (r.URL.Path == healthPath || r.URL.Path == readyPath)
+}
+
+// serveHealthCheck answers GET requests to the health and readiness endpoints, it returns false for other requests.
+// The report is written with 200 status if the check passed and with 503 otherwise.
+func (srv *Server) serveHealthCheck(w http.ResponseWriter, r *http.Request) bool {
+	if srv.health == nil || !isHealthCheck(r) {
+		return false
+	}
+	check := srv.health.Health
+	if r.URL.Path == readyPath {
+		check = srv.health.Ready
+	}
+	report, ok := check()
+	w.Header().Set("content-type", contentType)
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+	return true
+}
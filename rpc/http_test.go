@@ -48,7 +48,59 @@ func TestHTTPErrorResponseWithValidRequest(t *testing.T) {
 func testHTTPErrorResponse(t *testing.T, method, contentType, body string, expected int) {
 	request := httptest.NewRequest(method, "http://url.com", strings.NewReader(body))
 	request.Header.Set("content-type", contentType)
-	if code, _ := validateRequest(request); code != expected {
+	if code, _ := validateRequest(request, maxRequestContentLength); code != expected {
 		t.Fatalf("response code should be %d not %d", expected, code)
 	}
 }
+
+type testHealthChecker struct {
+	healthy, ready bool
+}
+
+func (c *testHealthChecker) Health() (interface{}, bool) {
+	return "health", c.healthy
+}
+
+func (c *testHealthChecker) Ready() (interface{}, bool) {
+	return "ready", c.ready
+}
+
+func TestHTTPHealthCheck(t *testing.T) {
+	srv := NewServer("")
+	srv.SetHealthChecker(&testHealthChecker{healthy: true, ready: false})
+
+	for path, expected := range map[string]int{
+		healthPath: http.StatusOK,
+		readyPath:  http.StatusServiceUnavailable,
+	} {
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://url.com"+path, nil))
+		if recorder.Code != expected {
+			t.Fatalf("response code of %v should be %d not %d", path, expected, recorder.Code)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://url.com/", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.Len() != 0 {
+		t.Fatalf("empty request should get an empty response")
+	}
+}
+
+func TestVHostHandler_HealthCheck(t *testing.T) {
+	srv := NewServer("")
+	srv.SetHealthChecker(&testHealthChecker{healthy: true, ready: true})
+	handler := newVHostHandler([]string{"localhost"}, srv)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://node.example.com"+healthPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("health check should bypass the vhost check, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "http://node.example.com/", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("request with unknown host should be forbidden, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"sync"
+)
+
+// APIKey is a named API key limited to a set of modules and methods.
+type APIKey struct {
+	Name string
+	Key  string
+
+	// Modules is a list of API modules (e.g. "bcn") whose methods are allowed to be called with the key.
+	Modules []string `toml:",omitempty"`
+
+	// Methods is a list of separate methods (e.g. "contract_readData") which are allowed to be called with the key.
+	Methods []string `toml:",omitempty"`
+}
+
+type apiKeyScope struct {
+	modules map[string]struct{}
+	methods map[string]struct{}
+}
+
+func (scope *apiKeyScope) allows(service, method string) bool {
+	if _, ok := scope.modules[service]; ok {
+		return true
+	}
+	_, ok := scope.methods[service+serviceMethodSeparator+method]
+	return ok
+}
+
+// Keyring holds the API key with access to all the methods and the list of scoped API keys.
+// Keys can be replaced at any time, the changes take effect for the next incoming requests.
+type Keyring struct {
+	mutex         sync.RWMutex
+	fullAccessKey string
+	scopes        map[string]*apiKeyScope
+}
+
+func NewKeyring(fullAccessKey string, keys []*APIKey) *Keyring {
+	keyring := &Keyring{}
+	keyring.Update(fullAccessKey, keys)
+	return keyring
+}
+
+// Update replaces all the keys of the keyring.
+func (k *Keyring) Update(fullAccessKey string, keys []*APIKey) {
+	scopes := make(map[string]*apiKeyScope, len(keys))
+	for _, key := range keys {
+		if key == nil || key.Key == "" {
+			continue
+		}
+		scope := &apiKeyScope{
+			modules: make(map[string]struct{}, len(key.Modules)),
+			methods: make(map[string]struct{}, len(key.Methods)),
+		}
+		for _, module := range key.Modules {
+			scope.modules[module] = struct{}{}
+		}
+		for _, method := range key.Methods {
+			scope.methods[method] = struct{}{}
+		}
+		scopes[key.Key] = scope
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.fullAccessKey = fullAccessKey
+	k.scopes = scopes
+}
+
+// UpdateScopedKeys replaces scoped keys keeping the full access key.
+func (k *Keyring) UpdateScopedKeys(keys []*APIKey) {
+	k.mutex.RLock()
+	fullAccessKey := k.fullAccessKey
+	k.mutex.RUnlock()
+	k.Update(fullAccessKey, keys)
+}
+
+// authorize checks whether the key grants access to the given method.
+// Empty method means the key should just be known to the keyring.
+func (k *Keyring) authorize(key, service, method string) Error {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.fullAccessKey == "" && len(k.scopes) == 0 {
+		return nil
+	}
+	if k.fullAccessKey != "" && key == k.fullAccessKey {
+		return nil
+	}
+	scope, ok := k.scopes[key]
+	if !ok {
+		return &invalidApiKeyError{}
+	}
+	if method == "" || scope.allows(service, method) {
+		return nil
+	}
+	return &methodNotAllowedError{service, method}
+}
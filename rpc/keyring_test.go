@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"testing"
+)
+
+func TestKeyring_authorize(t *testing.T) {
+	keyring := NewKeyring("", nil)
+	if err := keyring.authorize("", "dna", "exportKey"); err != nil {
+		t.Fatalf("empty keyring should allow all the methods, got %v", err)
+	}
+
+	keyring.Update("master", []*APIKey{
+		{
+			Name:    "explorer",
+			Key:     "explorer-key",
+			Modules: []string{"bcn"},
+			Methods: []string{"contract_readData"},
+		},
+	})
+
+	cases := []struct {
+		key, service, method string
+		errCode              int
+	}{
+		{"master", "dna", "exportKey", 0},
+		{"explorer-key", "bcn", "lastBlock", 0},
+		{"explorer-key", "contract", "readData", 0},
+		{"explorer-key", "contract", "call", -32801},
+		{"explorer-key", "dna", "exportKey", -32801},
+		{"explorer-key", "", "", 0},
+		{"unknown-key", "bcn", "lastBlock", -32800},
+		{"", "bcn", "lastBlock", -32800},
+	}
+	for i, c := range cases {
+		err := keyring.authorize(c.key, c.service, c.method)
+		if c.errCode == 0 && err != nil {
+			t.Errorf("case %d: unexpected error %v", i, err)
+		}
+		if c.errCode != 0 && (err == nil || err.ErrorCode() != c.errCode) {
+			t.Errorf("case %d: expected error code %d, got %v", i, c.errCode, err)
+		}
+	}
+
+	keyring.UpdateScopedKeys(nil)
+	if err := keyring.authorize("explorer-key", "bcn", "lastBlock"); err == nil {
+		t.Errorf("revoked key should not be accepted")
+	}
+	if err := keyring.authorize("master", "bcn", "lastBlock"); err != nil {
+		t.Errorf("full access key should be kept, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+)
+
+// Limits restricts resources consumed by RPC requests. Zero values disable the corresponding limit except the
+// request size which falls back to the default one.
+type Limits struct {
+	// MaxRequestContentLength is the max size of a request in bytes.
+	MaxRequestContentLength int64
+
+	// BatchSize is the max number of calls in a batch request.
+	BatchSize int
+
+	// BatchTimeout is the max duration of a batch request, the calls which are not
+	// started before the deadline fail with the timeout error.
+	BatchTimeout time.Duration
+
+	// CallTimeout is the max duration of a single call. The caller gets the timeout
+	// error after the deadline while the call itself is not interrupted. Untimed
+	// methods of the APIs, which change the node state, are not limited.
+	CallTimeout time.Duration
+}
+
+// DefaultLimits represents the default limits used if further configuration is not provided.
+var DefaultLimits = Limits{
+	MaxRequestContentLength: maxRequestContentLength,
+	BatchSize:               1000,
+	BatchTimeout:            60 * time.Second,
+	CallTimeout:             30 * time.Second,
+}
+
+func (l Limits) contentLengthLimit() int64 {
+	if l.MaxRequestContentLength <= 0 {
+		return maxRequestContentLength
+	}
+	return l.MaxRequestContentLength
+}
+
+// MethodStats holds counters of a single RPC method.
+type MethodStats struct {
+	Calls        uint64
+	Errors       uint64
+	Timeouts     uint64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// Stats collects number of calls and latency of each RPC method. It can be shared by several servers.
+type Stats struct {
+	mutex   sync.Mutex
+	methods map[string]*MethodStats
+}
+
+func NewStats() *Stats {
+	return &Stats{methods: make(map[string]*MethodStats)}
+}
+
+func (s *Stats) add(method string, latency time.Duration, err Error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stats, ok := s.methods[method]
+	if !ok {
+		stats = &MethodStats{}
+		s.methods[method] = stats
+	}
+	stats.Calls++
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+	if err != nil {
+		stats.Errors++
+		if _, ok := err.(*timeoutError); ok {
+			stats.Timeouts++
+		}
+	}
+}
+
+// Methods returns a copy of the collected counters by method name.
+func (s *Stats) Methods() map[string]MethodStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result := make(map[string]MethodStats, len(s.methods))
+	for method, stats := range s.methods {
+		result[method] = *stats
+	}
+	return result
+}
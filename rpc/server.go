@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/idena-network/idena-go/log"
@@ -44,8 +45,14 @@ const (
 
 // NewServer will create a new server instance with no registered handlers.
 func NewServer(apiKey string) *Server {
+	return NewServerWithKeyring(NewKeyring(apiKey, nil))
+}
+
+// NewServerWithKeyring will create a new server instance with no registered handlers
+// which authorizes requests using the given keyring.
+func NewServerWithKeyring(keyring *Keyring) *Server {
 	server := &Server{
-		apiKey:   apiKey,
+		keyring:  keyring,
 		services: make(serviceRegistry),
 		codecs:   mapset.NewSet(),
 		run:      1,
@@ -59,6 +66,16 @@ func NewServer(apiKey string) *Server {
 	return server
 }
 
+// SetLimits sets limits of the incoming requests. It should be called before the server starts serving requests.
+func (s *Server) SetLimits(limits Limits) {
+	s.limits = limits
+}
+
+// SetStats sets the collector of the calls statistics. It should be called before the server starts serving requests.
+func (s *Server) SetStats(stats *Stats) {
+	s.stats = stats
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -117,6 +134,21 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 	return nil
 }
 
+// registerAPI registers the service of the api and exempts its untimed methods from the call timeout.
+func (s *Server) registerAPI(api API) error {
+	if err := s.RegisterName(api.Namespace, api.Service); err != nil {
+		return err
+	}
+	for _, method := range api.Untimed {
+		callb, ok := s.services[api.Namespace].callbacks[method]
+		if !ok {
+			return fmt.Errorf("untimed method %s%s%s is not found", api.Namespace, serviceMethodSeparator, method)
+		}
+		callb.untimed = true
+	}
+	return nil
+}
+
 // serveRequest will reads requests from the codec, calls the RPC callback and
 // writes the response to the given codec.
 //
@@ -298,6 +330,39 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, rpcErr), nil
 	}
 
+	// execute RPC method and return result
+	reply, err := s.call(ctx, req)
+	if err != nil {
+		return codec.CreateErrorResponse(&req.id, err), nil
+	}
+	if len(reply) == 0 {
+		return codec.CreateResponse(req.id, nil), nil
+	}
+	return codec.CreateResponse(req.id, reply[0].Interface()), nil
+}
+
+// call executes the regular RPC method within the call timeout and records the call statistics.
+// Untimed methods are always completed, so the caller never gets the timeout error for a change which has been applied.
+// A panic of the method is turned into the error response, so it doesn't affect other calls of the batch.
+func (s *Server) call(ctx context.Context, req *serverRequest) (reply []reflect.Value, err Error) {
+	start := time.Now()
+	defer func() {
+		s.stats.add(req.svcname+serviceMethodSeparator+formatName(req.callb.method.Name), time.Since(start), err)
+	}()
+
+	timeout := s.limits.CallTimeout
+	if deadline, ok := ctx.Deadline(); ok && (timeout <= 0 || time.Until(deadline) < timeout) {
+		timeout = time.Until(deadline)
+	}
+	if req.callb.untimed {
+		timeout = 0
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	arguments := []reflect.Value{req.callb.rcvr}
 	if req.callb.hasCtx {
 		arguments = append(arguments, reflect.ValueOf(ctx))
@@ -306,19 +371,42 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		arguments = append(arguments, req.args...)
 	}
 
-	// execute RPC method and return result
-	reply := req.callb.method.Func.Call(arguments)
-	if len(reply) == 0 {
-		return codec.CreateResponse(req.id, nil), nil
+	type result struct {
+		reply []reflect.Value
+		err   Error
 	}
-	if req.callb.errPos >= 0 { // test if method returned an error
-		if !reply[req.callb.errPos].IsNil() {
+	done := make(chan result, 1)
+	invoke := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				const size = 64 << 10
+				buf := make([]byte, size)
+				buf = buf[:runtime.Stack(buf, false)]
+				log.Error("RPC method crashed", "method", req.callb.method.Name, "err", r, "stack", string(buf))
+				done <- result{err: &callbackError{fmt.Sprintf("method handler crashed: %v", r)}}
+			}
+		}()
+		reply := req.callb.method.Func.Call(arguments)
+		if req.callb.errPos >= 0 && !reply[req.callb.errPos].IsNil() { // test if method returned an error
 			e := reply[req.callb.errPos].Interface().(error)
-			res := codec.CreateErrorResponse(&req.id, &callbackError{e.Error()})
-			return res, nil
+			done <- result{err: &callbackError{e.Error()}}
+			return
 		}
+		done <- result{reply: reply}
+	}
+
+	if timeout <= 0 {
+		invoke()
+		res := <-done
+		return res.reply, res.err
+	}
+	go invoke()
+	select {
+	case res := <-done:
+		return res.reply, res.err
+	case <-ctx.Done():
+		return nil, &timeoutError{}
 	}
-	return codec.CreateResponse(req.id, reply[0].Interface()), nil
 }
 
 // exec executes the given request and writes the result back using the codec.
@@ -345,11 +433,27 @@ func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest
 // execBatch executes the given requests and writes the result back using the codec.
 // It will only write the response back when the last request is processed.
 func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*serverRequest) {
+	if s.limits.BatchSize > 0 && len(requests) > s.limits.BatchSize {
+		err := &limitExceededError{fmt.Sprintf("batch size exceeds the limit (%d>%d)", len(requests), s.limits.BatchSize)}
+		if err := codec.Write(codec.CreateErrorResponse(nil, err)); err != nil {
+			log.Error(fmt.Sprintf("%v\n", err))
+			codec.Close()
+		}
+		return
+	}
+	if s.limits.BatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.limits.BatchTimeout)
+		defer cancel()
+	}
+
 	responses := make([]interface{}, len(requests))
 	var callbacks []func()
 	for i, req := range requests {
 		if req.err != nil {
 			responses[i] = codec.CreateErrorResponse(&req.id, req.err)
+		} else if ctx.Err() != nil {
+			responses[i] = codec.CreateErrorResponse(&req.id, &timeoutError{})
 		} else {
 			var callback func()
 			if responses[i], callback = s.handle(ctx, codec, req); callback != nil {
@@ -390,12 +494,11 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			continue
 		}
 
-		if s.apiKey != "" && r.key != s.apiKey {
-			requests[i] = &serverRequest{id: r.id, err: &invalidApiKeyError{}}
-			continue
-		}
-
 		if r.isPubSub && strings.HasSuffix(r.method, unsubscribeMethodSuffix) {
+			if err := s.keyring.authorize(r.key, "", ""); err != nil {
+				requests[i] = &serverRequest{id: r.id, err: err}
+				continue
+			}
 			requests[i] = &serverRequest{id: r.id, isUnsubscribe: true}
 			argTypes := []reflect.Type{reflect.TypeOf("")} // expect subscription id as first arg
 			if args, err := codec.ParseRequestArguments(argTypes, r.params); err == nil {
@@ -406,6 +509,11 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			continue
 		}
 
+		if err := s.keyring.authorize(r.key, r.service, r.method); err != nil {
+			requests[i] = &serverRequest{id: r.id, err: err}
+			continue
+		}
+
 		if svc, ok = s.services[r.service]; !ok { // rpc method isn't available
 			requests[i] = &serverRequest{id: r.id, err: &methodNotFoundError{r.service, r.method}}
 			continue
@@ -238,3 +238,127 @@ func testApiKey(t *testing.T, sendKey bool) {
 		}
 	}
 }
+
+type LimitedService struct{}
+
+func (s *LimitedService) Echo(str string) string {
+	return str
+}
+
+func (s *LimitedService) Crash() string {
+	panic("crash")
+}
+
+func (s *LimitedService) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+func TestServerBatchLimits(t *testing.T) {
+	server := NewServer("")
+	if err := server.RegisterName("test", new(LimitedService)); err != nil {
+		t.Fatalf("%v", err)
+	}
+	stats := NewStats()
+	server.SetLimits(Limits{BatchSize: 3, CallTimeout: 50 * time.Millisecond})
+	server.SetStats(stats)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation)
+
+	out := json.NewEncoder(clientConn)
+	in := json.NewDecoder(clientConn)
+
+	request := func(id int, method string, params ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"id":      id,
+			"method":  "test_" + method,
+			"version": "2.0",
+			"params":  params,
+		}
+	}
+
+	batch := []interface{}{
+		request(1, "echo", "abc"),
+		request(2, "crash"),
+		request(3, "sleep", time.Second),
+	}
+	if err := out.Encode(batch); err != nil {
+		t.Fatal(err)
+	}
+	var responses []jsonErrResponse
+	if err := in.Decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+	if responses[0].Error.Code != 0 {
+		t.Errorf("expected successful echo, got error %v", responses[0].Error.Message)
+	}
+	if responses[1].Error.Code != (&callbackError{}).ErrorCode() {
+		t.Errorf("expected callback error for crashed method, got %v", responses[1].Error.Code)
+	}
+	if responses[2].Error.Code != (&timeoutError{}).ErrorCode() {
+		t.Errorf("expected timeout error, got %v", responses[2].Error.Code)
+	}
+
+	if err := out.Encode(append(batch, request(4, "echo", "abc"))); err != nil {
+		t.Fatal(err)
+	}
+	var response jsonErrResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error.Code != (&limitExceededError{}).ErrorCode() {
+		t.Errorf("expected limit exceeded error, got %v", response.Error.Code)
+	}
+
+	methods := stats.Methods()
+	if s := methods["test_echo"]; s.Calls != 1 || s.Errors != 0 {
+		t.Errorf("unexpected echo stats %+v", s)
+	}
+	if s := methods["test_crash"]; s.Calls != 1 || s.Errors != 1 {
+		t.Errorf("unexpected crash stats %+v", s)
+	}
+	if s := methods["test_sleep"]; s.Calls != 1 || s.Timeouts != 1 {
+		t.Errorf("unexpected sleep stats %+v", s)
+	}
+}
+
+func TestServerUntimedMethods(t *testing.T) {
+	server := NewServer("")
+	if err := server.registerAPI(API{Namespace: "test", Service: new(LimitedService), Untimed: []string{"sleep"}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := server.registerAPI(API{Namespace: "test2", Service: new(LimitedService), Untimed: []string{"unknown"}}); err == nil {
+		t.Fatal("expected error for unknown untimed method")
+	}
+	server.SetLimits(Limits{CallTimeout: 50 * time.Millisecond})
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation)
+
+	out := json.NewEncoder(clientConn)
+	in := json.NewDecoder(clientConn)
+
+	request := map[string]interface{}{
+		"id":      1,
+		"method":  "test_sleep",
+		"version": "2.0",
+		"params":  []interface{}{200 * time.Millisecond},
+	}
+	if err := out.Encode(request); err != nil {
+		t.Fatal(err)
+	}
+	var response jsonErrResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error.Code != 0 {
+		t.Errorf("expected completed untimed call, got error %v", response.Error.Message)
+	}
+}
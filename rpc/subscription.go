@@ -115,6 +115,12 @@ func (n *Notifier) Closed() <-chan interface{} {
 	return n.codec.Closed()
 }
 
+// Unsubscribe closes the subscription by the server, e.g. when the client can't keep up with notifications.
+// If the subscription could not be found ErrSubscriptionNotFound is returned.
+func (n *Notifier) Unsubscribe(id ID) error {
+	return n.unsubscribe(id)
+}
+
 // unsubscribe a subscription.
 // If the subscription could not be found ErrSubscriptionNotFound is returned.
 func (n *Notifier) unsubscribe(id ID) error {
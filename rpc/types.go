@@ -33,6 +33,7 @@ type API struct {
 	Version   string      // api version for DApp's
 	Service   interface{} // receiver instance which holds the methods
 	Public    bool        // indication if the methods must be considered safe for public use
+	Untimed   []string    // methods changing the node state which are completed regardless of the call timeout
 }
 
 // callback is a method callback which was registered in the server
@@ -43,6 +44,7 @@ type callback struct {
 	hasCtx      bool           // method's first argument is a context (not included in argTypes)
 	errPos      int            // err return idx, of -1 when method cannot return error
 	isSubscribe bool           // indication if the callback is a subscription
+	untimed     bool           // indication if the callback is exempted from the call timeout
 }
 
 // service represents a registered object
@@ -70,7 +72,10 @@ type subscriptions map[string]*callback  // collection of subscription callbacks
 // Server represents a RPC server
 type Server struct {
 	services serviceRegistry
-	apiKey   string
+	keyring  *Keyring
+	limits   Limits
+	stats    *Stats
+	health   HealthChecker
 
 	run      int32
 	codecsMu sync.Mutex
@@ -16,7 +16,10 @@
 
 package rpc
 
-import "testing"
+import (
+	"net/http"
+	"testing"
+)
 
 func TestWSGetConfigNoAuth(t *testing.T) {
 	config, err := wsGetConfig("ws://example.com:1234", "")
@@ -52,3 +55,33 @@ func TestWSGetConfigWithBasicAuth(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestWSHandshakeValidator(t *testing.T) {
+	request := func(host, origin string) *http.Request {
+		req, _ := http.NewRequest("GET", "http://"+host, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		return req
+	}
+	cases := []struct {
+		origins []string
+		host    string
+		origin  string
+		allowed bool
+	}{
+		{nil, "node.example.com:9010", "", true},
+		{nil, "node.example.com:9010", "http://node.example.com:9010", true},
+		{nil, "node.example.com:9010", "http://localhost", true},
+		{nil, "node.example.com:9010", "https://evil.example.com", false},
+		{[]string{"https://wallet.example.com"}, "node.example.com:9010", "https://wallet.example.com", true},
+		{[]string{"https://wallet.example.com"}, "node.example.com:9010", "https://evil.example.com", false},
+		{[]string{"*"}, "node.example.com:9010", "https://evil.example.com", true},
+	}
+	for i, c := range cases {
+		err := wsHandshakeValidator(c.origins)(nil, request(c.host, c.origin))
+		if (err == nil) != c.allowed {
+			t.Errorf("case %d: origin %q allowed = %v, want %v", i, c.origin, err == nil, c.allowed)
+		}
+	}
+}
@@ -116,6 +116,11 @@ type StatsCollector interface {
 	AddTimeLockCallTransfer(dest common.Address, amount *big.Int)
 	AddTimeLockTermination(dest common.Address)
 
+	AddPaymentScheduleDeploy(contractAddress common.Address, recipients []common.Address, amount *big.Int, period, startBlock, endBlock uint64)
+	AddPaymentScheduleCallClaim(recipient common.Address, amount *big.Int)
+	AddPaymentScheduleCallCancel(refund *big.Int)
+	AddPaymentScheduleTermination(dest common.Address)
+
 	AddTxReceipt(txReceipt *types.TxReceipt, appState *appstate.AppState)
 
 	RemoveMemPoolTx(tx *types.Transaction)
@@ -1033,6 +1038,50 @@ func AddTimeLockTermination(c StatsCollector, dest common.Address) {
 	c.AddTimeLockTermination(dest)
 }
 
+func (c *collectorStub) AddPaymentScheduleDeploy(contractAddress common.Address, recipients []common.Address, amount *big.Int, period, startBlock, endBlock uint64) {
+	// do nothing
+}
+
+func AddPaymentScheduleDeploy(c StatsCollector, contractAddress common.Address, recipients []common.Address, amount *big.Int, period, startBlock, endBlock uint64) {
+	if c == nil {
+		return
+	}
+	c.AddPaymentScheduleDeploy(contractAddress, recipients, amount, period, startBlock, endBlock)
+}
+
+func (c *collectorStub) AddPaymentScheduleCallClaim(recipient common.Address, amount *big.Int) {
+	// do nothing
+}
+
+func AddPaymentScheduleCallClaim(c StatsCollector, recipient common.Address, amount *big.Int) {
+	if c == nil {
+		return
+	}
+	c.AddPaymentScheduleCallClaim(recipient, amount)
+}
+
+func (c *collectorStub) AddPaymentScheduleCallCancel(refund *big.Int) {
+	// do nothing
+}
+
+func AddPaymentScheduleCallCancel(c StatsCollector, refund *big.Int) {
+	if c == nil {
+		return
+	}
+	c.AddPaymentScheduleCallCancel(refund)
+}
+
+func (c *collectorStub) AddPaymentScheduleTermination(dest common.Address) {
+	// do nothing
+}
+
+func AddPaymentScheduleTermination(c StatsCollector, dest common.Address) {
+	if c == nil {
+		return
+	}
+	c.AddPaymentScheduleTermination(dest)
+}
+
 func (c *collectorStub) AddTxReceipt(txReceipt *types.TxReceipt, appState *appstate.AppState) {
 	// do nothing
 }
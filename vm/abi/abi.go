@@ -0,0 +1,157 @@
+package abi
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// ReservedPrefix starts the names of the methods and events handled by the node rather than by contracts,
+// e.g. the contract upgrade, contracts can't declare such names
+const ReservedPrefix = "@"
+
+// ArgType is the format of an argument, it matches the formats of the contract RPC args
+type ArgType string
+
+const (
+	Byte    ArgType = "byte"
+	Uint64  ArgType = "uint64"
+	BigInt  ArgType = "bigint"
+	Dna     ArgType = "dna"
+	Address ArgType = "address"
+	Hex     ArgType = "hex"
+	String  ArgType = "string"
+)
+
+type Arg struct {
+	Name string  `json:"name"`
+	Type ArgType `json:"type"`
+	// Optional args may be omitted, the contract gets nil for them
+	Optional bool `json:"optional,omitempty"`
+}
+
+type Method struct {
+	Name string `json:"name"`
+	Args []Arg  `json:"args"`
+	// Returns is the format of the data returned by a read method
+	Returns ArgType `json:"returns,omitempty"`
+}
+
+type Event struct {
+	Name string `json:"name"`
+	Args []Arg  `json:"args"`
+}
+
+// Abi describes the interface of a contract: args of deploy and terminate, methods which may be called by txs,
+// read methods and emitted events
+type Abi struct {
+	Deploy      []Arg    `json:"deploy"`
+	Methods     []Method `json:"methods"`
+	ReadMethods []Method `json:"readMethods"`
+	Terminate   []Arg    `json:"terminate"`
+	Events      []Event  `json:"events"`
+}
+
+func (a *Abi) Method(name string) *Method {
+	return findMethod(a.Methods, name)
+}
+
+func (a *Abi) ReadMethod(name string) *Method {
+	return findMethod(a.ReadMethods, name)
+}
+
+func findMethod(methods []Method, name string) *Method {
+	for i := range methods {
+		if methods[i].Name == name {
+			return &methods[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that arg types are known, names are unique and not reserved
+func (a *Abi) Validate() error {
+	if err := validateArgs(a.Deploy); err != nil {
+		return errors.Wrap(err, "deploy")
+	}
+	if err := validateArgs(a.Terminate); err != nil {
+		return errors.Wrap(err, "terminate")
+	}
+	names := make(map[string]struct{})
+	for _, m := range append(append([]Method{}, a.Methods...), a.ReadMethods...) {
+		if _, ok := names[m.Name]; ok || m.Name == "" || strings.HasPrefix(m.Name, ReservedPrefix) {
+			return errors.Errorf("invalid method name %q", m.Name)
+		}
+		names[m.Name] = struct{}{}
+		if err := validateArgs(m.Args); err != nil {
+			return errors.Wrap(err, m.Name)
+		}
+		if m.Returns != "" && !m.Returns.valid() {
+			return errors.Errorf("%v: unknown type %q", m.Name, m.Returns)
+		}
+	}
+	for _, e := range a.Events {
+		if strings.HasPrefix(e.Name, ReservedPrefix) {
+			return errors.Errorf("invalid event name %q", e.Name)
+		}
+		if err := validateArgs(e.Args); err != nil {
+			return errors.Wrap(err, e.Name)
+		}
+	}
+	return nil
+}
+
+func validateArgs(args []Arg) error {
+	names := make(map[string]struct{})
+	for _, arg := range args {
+		if _, ok := names[arg.Name]; ok || arg.Name == "" {
+			return errors.Errorf("invalid arg name %q", arg.Name)
+		}
+		names[arg.Name] = struct{}{}
+		if !arg.Type.valid() {
+			return errors.Errorf("unknown type %q of arg %v", arg.Type, arg.Name)
+		}
+	}
+	return nil
+}
+
+func (t ArgType) valid() bool {
+	switch t {
+	case Byte, Uint64, BigInt, Dna, Address, Hex, String:
+		return true
+	default:
+		return false
+	}
+}
+
+// Positions maps named args to their indexes in the positional args list. It fails on unknown names
+// and missing required args.
+func Positions(args []Arg, named map[string]string) (map[int]string, error) {
+	result := make(map[int]string, len(named))
+	for i, arg := range args {
+		value, ok := named[arg.Name]
+		if !ok {
+			if !arg.Optional {
+				return nil, errors.Errorf("missing arg %v", arg.Name)
+			}
+			continue
+		}
+		result[i] = value
+	}
+	if len(result) != len(named) {
+		for name := range named {
+			if !hasArg(args, name) {
+				return nil, errors.Errorf("unknown arg %v", name)
+			}
+		}
+	}
+	return result, nil
+}
+
+func hasArg(args []Arg, name string) bool {
+	for _, arg := range args {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package abi
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPositions(t *testing.T) {
+	args := []Arg{
+		{Name: "dest", Type: Address},
+		{Name: "delay", Type: Uint64, Optional: true},
+		{Name: "amount", Type: Dna},
+	}
+
+	positions, err := Positions(args, map[string]string{"amount": "1.5", "dest": "0x01"})
+	require.NoError(t, err)
+	require.Equal(t, map[int]string{0: "0x01", 2: "1.5"}, positions)
+
+	_, err = Positions(args, map[string]string{"dest": "0x01"})
+	require.EqualError(t, err, "missing arg amount")
+
+	_, err = Positions(args, map[string]string{"amount": "1", "dest": "0x01", "fee": "1"})
+	require.EqualError(t, err, "unknown arg fee")
+}
+
+func TestAbi_Validate(t *testing.T) {
+	a := &Abi{
+		Deploy:      []Arg{{Name: "timestamp", Type: Uint64}},
+		Methods:     []Method{{Name: "transfer", Args: []Arg{{Name: "dest", Type: Address}}}},
+		ReadMethods: []Method{{Name: "timestamp", Returns: Uint64}},
+	}
+	require.NoError(t, a.Validate())
+	require.NotNil(t, a.Method("transfer"))
+	require.Nil(t, a.Method("timestamp"))
+	require.NotNil(t, a.ReadMethod("timestamp"))
+
+	a.Methods = append(a.Methods, Method{Name: "timestamp"})
+	require.Error(t, a.Validate())
+
+	a.Methods = a.Methods[:1]
+	a.Deploy = append(a.Deploy, Arg{Name: "value", Type: "float"})
+	require.Error(t, a.Validate())
+
+	// reserved names
+	a.Deploy = a.Deploy[:1]
+	a.Methods = append(a.Methods, Method{Name: ReservedPrefix + "upgrade"})
+	require.EqualError(t, a.Validate(), `invalid method name "@upgrade"`)
+
+	a.Methods = a.Methods[:1]
+	a.Events = []Event{{Name: ReservedPrefix + "upgrade"}}
+	require.EqualError(t, a.Validate(), `invalid event name "@upgrade"`)
+}
+
+func TestEncode(t *testing.T) {
+	data, err := Encode(Uint64, "5")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x5, 0, 0, 0, 0, 0, 0, 0}, data)
+
+	data, err = Encode(Dna, "1.5")
+	require.NoError(t, err)
+	require.Equal(t, "14d1120d7b160000", hex.EncodeToString(data))
+
+	data, err = Encode(Address, "0x0000000000000000000000000000000000000001")
+	require.NoError(t, err)
+	require.Len(t, data, 20)
+
+	data, err = Encode("unknown", "0x0102")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, data)
+
+	_, err = Encode(Byte, "256")
+	require.EqualError(t, err, "cannot parse byte: \"256\"")
+}
@@ -0,0 +1,67 @@
+package abi
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/common/math"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"strconv"
+)
+
+// Encode converts the value of the format to the contract arg, unknown formats are decoded as hex
+func Encode(format ArgType, value string) ([]byte, error) {
+	switch format {
+	case Byte:
+		i, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse byte: \"%v\"", value)
+		}
+		return []byte{byte(i)}, nil
+	case "int8":
+		i, err := strconv.ParseInt(value, 10, 8)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse int8: \"%v\"", value)
+		}
+		return common.ToBytes(i), nil
+	case Uint64:
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse uint64: \"%v\"", value)
+		}
+		return common.ToBytes(i), nil
+	case "int64":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse int64: \"%v\"", value)
+		}
+		return common.ToBytes(i), nil
+	case String:
+		return []byte(value), nil
+	case Address:
+		if !common.IsHexAddress(value) {
+			return nil, errors.Errorf("cannot parse address: \"%v\"", value)
+		}
+		return common.HexToAddress(value).Bytes(), nil
+	case BigInt:
+		v := new(big.Int)
+		_, ok := v.SetString(value, 10)
+		if !ok {
+			return nil, errors.Errorf("cannot parse bigint: \"%v\"", value)
+		}
+		return v.Bytes(), nil
+	case Dna:
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse dna: \"%v\"", value)
+		}
+		return math.ToInt(d.Mul(decimal.NewFromBigInt(common.DnaBase, 0))).Bytes(), nil
+	default:
+		data, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, errors.Errorf("cannot parse hex: \"%v\"", value)
+		}
+		return data, nil
+	}
+}
@@ -3,6 +3,7 @@ package embedded
 import (
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	env2 "github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"math/big"
@@ -16,6 +17,7 @@ var (
 	OracleLockContract           EmbeddedContractType
 	RefundableOracleLockContract EmbeddedContractType
 	MultisigContract             EmbeddedContractType
+	PaymentScheduleContract      EmbeddedContractType
 	AvailableContracts           map[EmbeddedContractType]struct{}
 )
 
@@ -25,6 +27,7 @@ func init() {
 	OracleLockContract.SetBytes([]byte{0x3})
 	RefundableOracleLockContract.SetBytes([]byte{0x4})
 	MultisigContract.SetBytes([]byte{0x5})
+	PaymentScheduleContract.SetBytes([]byte{0x6})
 
 	AvailableContracts = map[EmbeddedContractType]struct{}{
 		TimeLockContract:             {},
@@ -32,6 +35,7 @@ func init() {
 		OracleLockContract:           {},
 		RefundableOracleLockContract: {},
 		MultisigContract:             {},
+		PaymentScheduleContract:      {},
 	}
 }
 
@@ -40,6 +44,8 @@ type Contract interface {
 	Call(method string, args ...[]byte) error
 	Read(method string, args ...[]byte) ([]byte, error)
 	Terminate(args ...[]byte) (stakeDest common.Address, err error)
+	// Abi describes args of the contract methods
+	Abi() *abi.Abi
 }
 
 // base contract with useful common methods
@@ -12,9 +12,11 @@ import (
 	"github.com/idena-network/idena-go/secstore"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
 	dbm "github.com/tendermint/tm-db"
 	"math/big"
 	"math/rand"
+	"testing"
 )
 
 func createHeader(height uint64, time int64) *types.Header {
@@ -137,6 +139,8 @@ func (c *contractTester) createContract(ctx env.CallContext, e env.Env) Contract
 		return NewRefundableOracleLock2(ctx, e, nil)
 	case MultisigContract:
 		return NewMultisig(ctx, e, nil)
+	case PaymentScheduleContract:
+		return NewPaymentSchedule(ctx, e, nil)
 	default:
 		return nil
 	}
@@ -273,3 +277,21 @@ func (c *contractTester) ContractBalance() *big.Int {
 func (c *contractTester) CodeHash() *common.Hash {
 	return c.appState.State.GetCodeHash(c.contractAddr)
 }
+
+func TestContractsAbi(t *testing.T) {
+	contracts := []Contract{
+		NewTimeLock(nil, nil, nil),
+		NewMultisig(nil, nil, nil),
+		NewPaymentSchedule(nil, nil, nil),
+		NewOracleVotingContract3(nil, nil, nil),
+		NewOracleVotingContract4(nil, nil, nil),
+		NewOracleLock2(nil, nil, nil),
+		NewRefundableOracleLock2(nil, nil, nil),
+	}
+	for _, contract := range contracts {
+		require.NotNil(t, contract.Abi())
+		require.NoError(t, contract.Abi().Validate())
+		// the upgrade call never reaches the contract
+		require.Nil(t, contract.Abi().Method(UpgradeMethod))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
@@ -15,6 +16,26 @@ const (
 	multisigInitialized   = byte(2)
 )
 
+var multisigAbi = &abi.Abi{
+	Deploy: []abi.Arg{{Name: "maxVotes", Type: abi.Byte}, {Name: "minVotes", Type: abi.Byte}},
+	Methods: []abi.Method{
+		{Name: "add", Args: []abi.Arg{{Name: "address", Type: abi.Address}}},
+		{Name: "send", Args: []abi.Arg{{Name: "dest", Type: abi.Address}, {Name: "amount", Type: abi.Dna}}},
+		{Name: "push", Args: []abi.Arg{{Name: "dest", Type: abi.Address}, {Name: "amount", Type: abi.Dna}}},
+	},
+	ReadMethods: []abi.Method{
+		{Name: "state", Returns: abi.Byte},
+		{Name: "minVotes", Returns: abi.Byte},
+		{Name: "maxVotes", Returns: abi.Byte},
+		{Name: "voters", Returns: abi.Hex},
+		{Name: "voteAddress", Args: []abi.Arg{{Name: "voter", Type: abi.Address}}, Returns: abi.Address},
+		{Name: "voteAmount", Args: []abi.Arg{{Name: "voter", Type: abi.Address}}, Returns: abi.Dna},
+		{Name: "votes", Args: []abi.Arg{{Name: "dest", Type: abi.Address}, {Name: "amount", Type: abi.Dna}}, Returns: abi.Uint64},
+		{Name: "isReady", Args: []abi.Arg{{Name: "dest", Type: abi.Address}, {Name: "amount", Type: abi.Dna}}, Returns: abi.Byte},
+	},
+	Terminate: []abi.Arg{{Name: "dest", Type: abi.Address}},
+}
+
 type Multisig struct {
 	*BaseContract
 	voteAddress *env.Map
@@ -70,8 +91,68 @@ func (m *Multisig) Call(method string, args ...[]byte) (err error) {
 	}
 }
 
+func (m *Multisig) Abi() *abi.Abi {
+	return multisigAbi
+}
+
 func (m *Multisig) Read(method string, args ...[]byte) ([]byte, error) {
-	panic("implement me")
+	switch method {
+	case "state", "minVotes", "maxVotes":
+		return []byte{m.GetByte(method)}, nil
+	case "voters":
+		// concatenated 20-byte addresses of the added voters
+		var voters []byte
+		m.voteAmount.Iterate(func(key []byte, value []byte) bool {
+			voters = append(voters, key...)
+			return false
+		})
+		return voters, nil
+	case "voteAddress", "voteAmount":
+		voter, err := helpers.ExtractAddr(0, args...)
+		if err != nil {
+			return nil, err
+		}
+		if m.voteAmount.Get(voter.Bytes()) == nil {
+			return nil, errors.New("unknown voter")
+		}
+		if method == "voteAddress" {
+			return m.voteAddress.Get(voter.Bytes()), nil
+		}
+		return m.voteAmount.Get(voter.Bytes()), nil
+	case "votes", "isReady":
+		dest, err := helpers.ExtractAddr(0, args...)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := helpers.ExtractArray(1, args...)
+		if err != nil {
+			return nil, err
+		}
+		votes := m.countVotes(dest, amount)
+		if method == "votes" {
+			return common.ToBytes(uint64(votes)), nil
+		}
+		if m.GetByte("state") == multisigInitialized && votes >= int(m.GetByte("minVotes")) {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	default:
+		return nil, errors.New("unknown method")
+	}
+}
+
+// countVotes returns the number of voters who voted for sending the amount to the dest
+func (m *Multisig) countVotes(dest common.Address, amount []byte) int {
+	votes := 0
+	m.voteAddress.Iterate(func(key []byte, value []byte) bool {
+		if bytes.Compare(value, dest.Bytes()) == 0 {
+			if bytes.Compare(m.voteAmount.Get(key), amount) == 0 {
+				votes++
+			}
+		}
+		return false
+	})
+	return votes
 }
 
 func (m *Multisig) add(args ...[]byte) (err error) {
@@ -141,15 +222,7 @@ func (m *Multisig) push(args ...[]byte) error {
 	if err != nil {
 		return err
 	}
-	votes := 0
-	m.voteAddress.Iterate(func(key []byte, value []byte) bool {
-		if bytes.Compare(value, dest.Bytes()) == 0 {
-			if bytes.Compare(m.voteAmount.Get(key), amount) == 0 {
-				votes++
-			}
-		}
-		return false
-	})
+	votes := m.countVotes(dest, amount)
 
 	minVotes := m.GetByte("minVotes")
 	if votes < int(minVotes) {
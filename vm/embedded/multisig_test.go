@@ -0,0 +1,81 @@
+package embedded
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+type multisigDeploy struct {
+	deployStake *big.Int
+	maxVotes    byte
+	minVotes    byte
+}
+
+func (s *deployContractSwitch) Multisig(maxVotes, minVotes byte) *multisigDeploy {
+	return &multisigDeploy{deployStake: s.deployStake, maxVotes: maxVotes, minVotes: minVotes}
+}
+
+func (d *multisigDeploy) Parameters() (contract EmbeddedContractType, deployStake *big.Int, params [][]byte) {
+	return MultisigContract, d.deployStake, [][]byte{{d.maxVotes}, {d.minVotes}}
+}
+
+func TestMultisig_Read(t *testing.T) {
+	tester := createTestContractBuilder(4, common.DnaBase).Build()
+	require.NoError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).Multisig(3, 2)))
+	tester.Commit()
+
+	data, err := tester.Read(MultisigContract, "state")
+	require.NoError(t, err)
+	require.Equal(t, []byte{multisigUninitialized}, data)
+
+	voter := func(key *ecdsa.PrivateKey) common.Address {
+		return crypto.PubkeyToAddress(key.PublicKey)
+	}
+	for _, key := range tester.identities {
+		require.NoError(t, tester.OwnerCall(MultisigContract, "add", voter(key).Bytes()))
+		tester.Commit()
+	}
+
+	data, err = tester.Read(MultisigContract, "voters")
+	require.NoError(t, err)
+	require.Len(t, data, 3*common.AddressLength)
+	for _, key := range tester.identities {
+		require.True(t, bytes.Contains(data, voter(key).Bytes()))
+	}
+
+	dest := common.Address{0x1}
+	amount := big.NewInt(100).Bytes()
+	require.NoError(t, tester.IdentityCall(0, MultisigContract, "send", dest.Bytes(), amount))
+	tester.Commit()
+
+	data, err = tester.Read(MultisigContract, "voteAddress", voter(tester.identities[0]).Bytes())
+	require.NoError(t, err)
+	require.Equal(t, dest.Bytes(), data)
+	data, err = tester.Read(MultisigContract, "voteAmount", voter(tester.identities[0]).Bytes())
+	require.NoError(t, err)
+	require.Equal(t, amount, data)
+	_, err = tester.Read(MultisigContract, "voteAmount", dest.Bytes())
+	require.EqualError(t, err, "unknown voter")
+
+	data, err = tester.Read(MultisigContract, "isReady", dest.Bytes(), amount)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, data)
+
+	require.NoError(t, tester.IdentityCall(1, MultisigContract, "send", dest.Bytes(), amount))
+	tester.Commit()
+
+	data, err = tester.Read(MultisigContract, "votes", dest.Bytes(), amount)
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(2)), data)
+	data, err = tester.Read(MultisigContract, "isReady", dest.Bytes(), amount)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, data)
+
+	_, err = tester.Read(MultisigContract, "unknown")
+	require.EqualError(t, err, "unknown method")
+}
@@ -3,11 +3,22 @@ package embedded
 import (
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
 )
 
+var oracleLockAbi = &abi.Abi{
+	Deploy: []abi.Arg{
+		{Name: "oracleVotingAddress", Type: abi.Address},
+		{Name: "value", Type: abi.Byte},
+		{Name: "successAddress", Type: abi.Address},
+		{Name: "failAddress", Type: abi.Address},
+	},
+	Methods: []abi.Method{{Name: "push"}, {Name: "checkOracleVoting"}},
+}
+
 type OracleLock2 struct {
 	*BaseContract
 }
@@ -61,6 +72,10 @@ func (e *OracleLock2) Call(method string, args ...[]byte) error {
 	}
 }
 
+func (e *OracleLock2) Abi() *abi.Abi {
+	return oracleLockAbi
+}
+
 func (e *OracleLock2) Read(method string, args ...[]byte) ([]byte, error) {
 	panic("implement me")
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/idena-network/idena-go/common/math"
 	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
@@ -38,6 +39,39 @@ func init() {
 	maxHash = new(big.Float).SetInt(i)
 }
 
+// oracleVotingAbi is shared by all versions of the oracle voting contract
+var oracleVotingAbi = &abi.Abi{
+	Deploy: []abi.Arg{
+		{Name: "fact", Type: abi.Hex},
+		{Name: "startTime", Type: abi.Uint64},
+		{Name: "votingDuration", Type: abi.Uint64, Optional: true},
+		{Name: "publicVotingDuration", Type: abi.Uint64, Optional: true},
+		{Name: "winnerThreshold", Type: abi.Byte, Optional: true},
+		{Name: "quorum", Type: abi.Byte, Optional: true},
+		{Name: "committeeSize", Type: abi.Uint64, Optional: true},
+		{Name: "votingMinPayment", Type: abi.Dna, Optional: true},
+		{Name: "ownerFee", Type: abi.Byte, Optional: true},
+	},
+	Methods: []abi.Method{
+		{Name: "startVoting"},
+		{Name: "sendVoteProof", Args: []abi.Arg{{Name: "voteHash", Type: abi.Hex}}},
+		{Name: "sendVote", Args: []abi.Arg{{Name: "vote", Type: abi.Byte}, {Name: "salt", Type: abi.Hex}}},
+		{Name: FinishVotingMethod},
+		{Name: "prolongVoting"},
+		{Name: "addStake"},
+	},
+	ReadMethods: []abi.Method{
+		{Name: "proof", Args: []abi.Arg{{Name: "addr", Type: abi.Address}}, Returns: abi.Hex},
+		{Name: "voteHash", Args: []abi.Arg{{Name: "vote", Type: abi.Byte}, {Name: "salt", Type: abi.Hex}}, Returns: abi.Hex},
+		{Name: "voteBlock", Returns: abi.Uint64},
+		{Name: "state", Returns: abi.Byte},
+		{Name: "secretVotesCount", Returns: abi.Uint64},
+	},
+	Events: []abi.Event{
+		{Name: "reward", Args: []abi.Arg{{Name: "address", Type: abi.Address}, {Name: "amount", Type: abi.BigInt}}},
+	},
+}
+
 type OracleVoting4 struct {
 	*BaseContract
 	voteHashes  *env.Map
@@ -555,6 +589,10 @@ func (f *OracleVoting4) addStake(args ...[]byte) error {
 	return err
 }
 
+func (f *OracleVoting4) Abi() *abi.Abi {
+	return oracleVotingAbi
+}
+
 func (f *OracleVoting4) Read(method string, args ...[]byte) ([]byte, error) {
 
 	switch method {
@@ -608,6 +646,10 @@ func (f *OracleVoting4) Read(method string, args ...[]byte) ([]byte, error) {
 	case "voteBlock":
 		block := f.GetUint64("startBlock") + f.GetUint64("votingDuration")
 		return common.ToBytes(block), nil
+	case "state":
+		return []byte{f.GetByte("state")}, nil
+	case "secretVotesCount":
+		return common.ToBytes(f.getSecretVotesCount()), nil
 	default:
 		return nil, errors.New("unknown method")
 	}
@@ -716,8 +758,6 @@ func (f *OracleVoting4) Terminate(args ...[]byte) (common.Address, error) {
 	return common.Address{}, errors.New("voting can not be terminated")
 }
 
-
-
 type ContractError struct {
 	error    string
 	tryLater bool
@@ -736,4 +776,4 @@ func (e *ContractError) Error() string {
 
 func (e *ContractError) TryLater() bool {
 	return e.tryLater
-}
\ No newline at end of file
+}
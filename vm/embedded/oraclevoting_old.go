@@ -6,6 +6,7 @@ import (
 	"github.com/idena-network/idena-go/common/math"
 	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
@@ -533,6 +534,10 @@ func (f *OracleVoting3) addStake(args ...[]byte) error {
 	return err
 }
 
+func (f *OracleVoting3) Abi() *abi.Abi {
+	return oracleVotingAbi
+}
+
 func (f *OracleVoting3) Read(method string, args ...[]byte) ([]byte, error) {
 
 	switch method {
@@ -586,6 +591,10 @@ func (f *OracleVoting3) Read(method string, args ...[]byte) ([]byte, error) {
 	case "voteBlock":
 		block := f.GetUint64("startBlock") + f.GetUint64("votingDuration")
 		return common.ToBytes(block), nil
+	case "state":
+		return []byte{f.GetByte("state")}, nil
+	case "secretVotesCount":
+		return common.ToBytes(f.getSecretVotesCount()), nil
 	default:
 		return nil, errors.New("unknown method")
 	}
@@ -692,4 +701,4 @@ func (f *OracleVoting3) Terminate(args ...[]byte) (common.Address, error) {
 		return f.Owner(), nil
 	}
 	return common.Address{}, errors.New("voting can not be terminated")
-}
\ No newline at end of file
+}
@@ -658,3 +658,17 @@ func TestOracleVoting_RewardPools(t *testing.T) {
 
 	require.Equal(t, big.NewInt(0).Mul(balance, big.NewInt(5)).String(), caller.contractTester.appState.State.GetBalance(pool2).String())
 }
+
+func TestOracleVoting_ReadState(t *testing.T) {
+	tester := createTestContractBuilder(2, common.DnaBase).Build()
+	_, err := tester.ConfigureDeploy(common.DnaBase).OracleVoting().Deploy()
+	require.NoError(t, err)
+	tester.Commit()
+
+	state, err := tester.Read(OracleVotingContract, "state")
+	require.NoError(t, err)
+	require.Equal(t, []byte{oracleVotingStatePending}, state)
+	secretVotesCount, err := tester.Read(OracleVotingContract, "secretVotesCount")
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(0)), secretVotesCount)
+}
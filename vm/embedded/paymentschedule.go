@@ -0,0 +1,285 @@
+package embedded
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/idena-network/idena-go/vm/helpers"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+const maxPaymentScheduleRecipients = 32
+
+var paymentScheduleAbi = &abi.Abi{
+	Deploy: []abi.Arg{
+		{Name: "recipients", Type: abi.Hex},
+		{Name: "amount", Type: abi.Dna},
+		{Name: "period", Type: abi.Uint64},
+		{Name: "startBlock", Type: abi.Uint64},
+		{Name: "endBlock", Type: abi.Uint64},
+	},
+	Methods: []abi.Method{
+		{Name: "claim"},
+		{Name: "cancel"},
+	},
+	ReadMethods: []abi.Method{
+		{Name: "recipients", Returns: abi.Hex},
+		{Name: "amount", Returns: abi.Dna},
+		{Name: "period", Returns: abi.Uint64},
+		{Name: "startBlock", Returns: abi.Uint64},
+		{Name: "endBlock", Returns: abi.Uint64},
+		{Name: "cancelBlock", Returns: abi.Uint64},
+		{Name: "vested", Args: []abi.Arg{{Name: "recipient", Type: abi.Address}}, Returns: abi.Dna},
+		{Name: "claimed", Args: []abi.Arg{{Name: "recipient", Type: abi.Address}}, Returns: abi.Dna},
+		{Name: "claimable", Args: []abi.Arg{{Name: "recipient", Type: abi.Address}}, Returns: abi.Dna},
+		{Name: "owed", Returns: abi.Dna},
+	},
+	Events: []abi.Event{
+		{Name: "claim", Args: []abi.Arg{{Name: "recipient", Type: abi.Address}, {Name: "amount", Type: abi.BigInt}}},
+		{Name: "cancel", Args: []abi.Arg{{Name: "refund", Type: abi.BigInt}}},
+	},
+	Terminate: []abi.Arg{{Name: "dest", Type: abi.Address, Optional: true}},
+}
+
+// PaymentSchedule pays the amount to every recipient for each complete period between the start and the end blocks.
+// Recipients claim vested payments, the owner may cancel the schedule and get back the coins which are not vested yet.
+type PaymentSchedule struct {
+	*BaseContract
+	claimed *env.Map
+}
+
+func NewPaymentSchedule(ctx env.CallContext, e env.Env, statsCollector collector.StatsCollector) *PaymentSchedule {
+	return &PaymentSchedule{&BaseContract{
+		ctx:            ctx,
+		env:            e,
+		statsCollector: statsCollector,
+	}, env.NewMap([]byte("claimed"), e, ctx)}
+}
+
+func (p *PaymentSchedule) Deploy(args ...[]byte) error {
+	data, err := helpers.ExtractArray(0, args...)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || len(data)%common.AddressLength != 0 {
+		return errors.New("recipients should be concatenated addresses")
+	}
+	recipients := splitAddresses(data)
+	if len(recipients) > maxPaymentScheduleRecipients {
+		return errors.Errorf("recipients count should be in range [1;%v]", maxPaymentScheduleRecipients)
+	}
+	unique := make(map[common.Address]struct{}, len(recipients))
+	for _, recipient := range recipients {
+		if _, ok := unique[recipient]; ok {
+			return errors.New("duplicated recipient")
+		}
+		unique[recipient] = struct{}{}
+	}
+	amount, err := helpers.ExtractBigInt(1, args...)
+	if err != nil {
+		return err
+	}
+	if amount.Sign() <= 0 {
+		return errors.New("amount should be positive")
+	}
+	period, err := helpers.ExtractUInt64(2, args...)
+	if err != nil {
+		return err
+	}
+	if period == 0 {
+		return errors.New("period should be positive")
+	}
+	startBlock, err := helpers.ExtractUInt64(3, args...)
+	if err != nil {
+		return err
+	}
+	endBlock, err := helpers.ExtractUInt64(4, args...)
+	if err != nil {
+		return err
+	}
+	if endBlock <= startBlock || endBlock-startBlock < period {
+		return errors.New("schedule should contain at least one period")
+	}
+	p.SetArray("recipients", data)
+	p.SetBigInt("amount", amount)
+	p.SetUint64("period", period)
+	p.SetUint64("startBlock", startBlock)
+	p.SetUint64("endBlock", endBlock)
+	p.SetOwner(p.ctx.Sender())
+	collector.AddPaymentScheduleDeploy(p.statsCollector, p.ctx.ContractAddr(), recipients, amount, period, startBlock, endBlock)
+	return nil
+}
+
+func (p *PaymentSchedule) Call(method string, args ...[]byte) error {
+	switch method {
+	case "claim":
+		return p.claim()
+	case "cancel":
+		return p.cancel()
+	default:
+		return errors.New("unknown method")
+	}
+}
+
+func (p *PaymentSchedule) Abi() *abi.Abi {
+	return paymentScheduleAbi
+}
+
+func (p *PaymentSchedule) Read(method string, args ...[]byte) ([]byte, error) {
+	switch method {
+	case "recipients":
+		return p.GetArray("recipients"), nil
+	case "amount":
+		return p.GetArray("amount"), nil
+	case "period", "startBlock", "endBlock", "cancelBlock":
+		return common.ToBytes(p.GetUint64(method)), nil
+	case "vested", "claimed", "claimable":
+		recipient, err := helpers.ExtractAddr(0, args...)
+		if err != nil {
+			return nil, err
+		}
+		if !p.isRecipient(recipient) {
+			return nil, errors.New("unknown recipient")
+		}
+		switch method {
+		case "vested":
+			return p.vested().Bytes(), nil
+		case "claimed":
+			return p.claimedAmount(recipient).Bytes(), nil
+		default:
+			return p.claimable(recipient).Bytes(), nil
+		}
+	case "owed":
+		return p.owed().Bytes(), nil
+	default:
+		return nil, errors.New("unknown method")
+	}
+}
+
+func (p *PaymentSchedule) claim() error {
+	recipient := p.ctx.Sender()
+	if !p.isRecipient(recipient) {
+		return errors.New("sender is not a recipient")
+	}
+	amount := p.claimable(recipient)
+	if amount.Sign() == 0 {
+		return errors.New("nothing to claim")
+	}
+	if err := p.env.Send(p.ctx, recipient, amount); err != nil {
+		return err
+	}
+	p.claimed.Set(recipient.Bytes(), new(big.Int).Add(p.claimedAmount(recipient), amount).Bytes())
+	p.env.Event("claim", recipient.Bytes(), amount.Bytes())
+	collector.AddPaymentScheduleCallClaim(p.statsCollector, recipient, amount)
+	return nil
+}
+
+func (p *PaymentSchedule) cancel() error {
+	if !p.IsOwner() {
+		return errors.New("sender is not an owner")
+	}
+	if p.GetUint64("cancelBlock") != 0 {
+		return errors.New("schedule is already canceled")
+	}
+	p.SetUint64("cancelBlock", p.env.BlockNumber())
+
+	// coins vested before the cancellation stay in the contract until the recipients claim them
+	refund := new(big.Int).Sub(p.env.Balance(p.ctx.ContractAddr()), p.owed())
+	if refund.Sign() > 0 {
+		if err := p.env.Send(p.ctx, p.Owner(), refund); err != nil {
+			return err
+		}
+	} else {
+		refund = big.NewInt(0)
+	}
+	p.env.Event("cancel", refund.Bytes())
+	collector.AddPaymentScheduleCallCancel(p.statsCollector, refund)
+	return nil
+}
+
+func (p *PaymentSchedule) Terminate(args ...[]byte) (common.Address, error) {
+	if !p.IsOwner() {
+		return common.Address{}, errors.New("sender is not an owner")
+	}
+	if p.GetUint64("cancelBlock") == 0 && p.env.BlockNumber() < p.GetUint64("endBlock") {
+		return common.Address{}, errors.New("schedule is not finished")
+	}
+	if p.owed().Sign() > 0 {
+		return common.Address{}, errors.New("recipients have unclaimed payments")
+	}
+	balance := p.env.Balance(p.ctx.ContractAddr())
+	dust := big.NewInt(0).Mul(p.env.MinFeePerGas(), big.NewInt(100))
+	if balance.Cmp(dust) > 0 {
+		return common.Address{}, errors.New("contract has dna")
+	}
+	if balance.Sign() > 0 {
+		p.env.BurnAll(p.ctx)
+	}
+	// the stake is refunded to the owner if dest is omitted
+	dest := p.Owner()
+	if len(args) > 0 && len(args[0]) > 0 {
+		var err error
+		if dest, err = helpers.ExtractAddr(0, args...); err != nil {
+			return common.Address{}, err
+		}
+	}
+	collector.AddPaymentScheduleTermination(p.statsCollector, dest)
+	return dest, nil
+}
+
+// vested returns the amount vested to every recipient, payments stop at the end block or at the cancellation and
+// the last incomplete period is not paid
+func (p *PaymentSchedule) vested() *big.Int {
+	startBlock, endBlock := p.GetUint64("startBlock"), p.GetUint64("endBlock")
+	if cancelBlock := p.GetUint64("cancelBlock"); cancelBlock != 0 && cancelBlock < endBlock {
+		endBlock = cancelBlock
+	}
+	if block := p.env.BlockNumber(); block < endBlock {
+		endBlock = block
+	}
+	if endBlock <= startBlock {
+		return big.NewInt(0)
+	}
+	periods := (endBlock - startBlock) / p.GetUint64("period")
+	return new(big.Int).Mul(p.GetBigInt("amount"), new(big.Int).SetUint64(periods))
+}
+
+func (p *PaymentSchedule) claimedAmount(recipient common.Address) *big.Int {
+	return new(big.Int).SetBytes(p.claimed.Get(recipient.Bytes()))
+}
+
+func (p *PaymentSchedule) claimable(recipient common.Address) *big.Int {
+	return new(big.Int).Sub(p.vested(), p.claimedAmount(recipient))
+}
+
+// owed returns the total amount vested to the recipients but not claimed yet
+func (p *PaymentSchedule) owed() *big.Int {
+	vested := p.vested()
+	result := big.NewInt(0)
+	for _, recipient := range splitAddresses(p.GetArray("recipients")) {
+		result.Add(result, vested)
+		result.Sub(result, p.claimedAmount(recipient))
+	}
+	return result
+}
+
+func (p *PaymentSchedule) isRecipient(address common.Address) bool {
+	for _, recipient := range splitAddresses(p.GetArray("recipients")) {
+		if recipient == address {
+			return true
+		}
+	}
+	return false
+}
+
+func splitAddresses(data []byte) []common.Address {
+	result := make([]common.Address, 0, len(data)/common.AddressLength)
+	for i := 0; i+common.AddressLength <= len(data); i += common.AddressLength {
+		var addr common.Address
+		addr.SetBytes(data[i : i+common.AddressLength])
+		result = append(result, addr)
+	}
+	return result
+}
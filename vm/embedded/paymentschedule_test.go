@@ -0,0 +1,119 @@
+package embedded
+
+import (
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+type paymentScheduleDeploy struct {
+	deployStake *big.Int
+	recipients  []byte
+	amount      *big.Int
+	period      uint64
+	startBlock  uint64
+	endBlock    uint64
+}
+
+func (s *deployContractSwitch) PaymentSchedule(recipients []common.Address, amount *big.Int, period, startBlock, endBlock uint64) *paymentScheduleDeploy {
+	var data []byte
+	for _, recipient := range recipients {
+		data = append(data, recipient.Bytes()...)
+	}
+	return &paymentScheduleDeploy{deployStake: s.deployStake, recipients: data, amount: amount, period: period,
+		startBlock: startBlock, endBlock: endBlock}
+}
+
+func (d *paymentScheduleDeploy) Parameters() (contract EmbeddedContractType, deployStake *big.Int, params [][]byte) {
+	return PaymentScheduleContract, d.deployStake, [][]byte{d.recipients, d.amount.Bytes(), common.ToBytes(d.period),
+		common.ToBytes(d.startBlock), common.ToBytes(d.endBlock)}
+}
+
+func TestPaymentSchedule_Deploy(t *testing.T) {
+	tester := createTestContractBuilder(2, common.DnaBase).Build()
+	recipient := crypto.PubkeyToAddress(tester.identities[0].PublicKey)
+	amount := big.NewInt(100)
+
+	invalidRecipients := &paymentScheduleDeploy{deployStake: common.DnaBase, recipients: []byte{0x1}, amount: amount,
+		period: 10, startBlock: 10, endBlock: 50}
+	require.EqualError(t, tester.Deploy(invalidRecipients), "recipients should be concatenated addresses")
+	require.EqualError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient, recipient}, amount, 10, 10, 50)),
+		"duplicated recipient")
+	require.EqualError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient}, big.NewInt(0), 10, 10, 50)),
+		"amount should be positive")
+	require.EqualError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient}, amount, 0, 10, 50)),
+		"period should be positive")
+	require.EqualError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient}, amount, 10, 10, 15)),
+		"schedule should contain at least one period")
+	require.NoError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient}, amount, 10, 10, 50)))
+}
+
+func TestPaymentSchedule_ClaimAndCancel(t *testing.T) {
+	tester := createTestContractBuilder(3, common.DnaBase).Build()
+	address := func(key *ecdsa.PrivateKey) common.Address {
+		return crypto.PubkeyToAddress(key.PublicKey)
+	}
+	recipient1, recipient2 := address(tester.identities[0]), address(tester.identities[1])
+	amount := big.NewInt(100)
+
+	require.NoError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).PaymentSchedule([]common.Address{recipient1, recipient2}, amount, 10, 10, 55)))
+	tester.Commit()
+	// 4 periods for 2 recipients
+	tester.AddBalance(big.NewInt(800))
+
+	tester.setHeight(5)
+	require.EqualError(t, tester.IdentityCall(0, PaymentScheduleContract, "claim"), "nothing to claim")
+	require.EqualError(t, tester.OwnerCall(PaymentScheduleContract, "claim"), "sender is not a recipient")
+
+	tester.setHeight(31)
+	require.NoError(t, tester.IdentityCall(0, PaymentScheduleContract, "claim"))
+	events := tester.env.Commit()
+	tester.appState.Commit(nil)
+	require.Equal(t, big.NewInt(200), tester.appState.State.GetBalance(recipient1))
+	require.Equal(t, big.NewInt(600), tester.ContractBalance())
+	require.Len(t, events, 1)
+	require.Equal(t, "claim", events[0].EventName)
+	require.Equal(t, [][]byte{recipient1.Bytes(), big.NewInt(200).Bytes()}, events[0].Data)
+
+	data, err := tester.Read(PaymentScheduleContract, "claimable", recipient2.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(200).Bytes(), data)
+	data, err = tester.Read(PaymentScheduleContract, "owed")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(200).Bytes(), data)
+
+	_, err = tester.Terminate(tester.mainKey, PaymentScheduleContract)
+	require.EqualError(t, err, "schedule is not finished")
+
+	tester.setHeight(40)
+	require.EqualError(t, tester.IdentityCall(0, PaymentScheduleContract, "cancel"), "sender is not an owner")
+	require.NoError(t, tester.OwnerCall(PaymentScheduleContract, "cancel"))
+	tester.Commit()
+	// 3 periods are vested, recipient2 has not claimed them yet
+	require.Equal(t, new(big.Int).Add(common.DnaBase, big.NewInt(200)), tester.appState.State.GetBalance(tester.mainAddr))
+	require.Equal(t, big.NewInt(400), tester.ContractBalance())
+	require.EqualError(t, tester.OwnerCall(PaymentScheduleContract, "cancel"), "schedule is already canceled")
+
+	_, err = tester.Terminate(tester.mainKey, PaymentScheduleContract)
+	require.EqualError(t, err, "recipients have unclaimed payments")
+
+	tester.setHeight(60)
+	require.NoError(t, tester.IdentityCall(0, PaymentScheduleContract, "claim"))
+	tester.Commit()
+	require.NoError(t, tester.IdentityCall(1, PaymentScheduleContract, "claim"))
+	tester.Commit()
+	require.Equal(t, big.NewInt(300), tester.appState.State.GetBalance(recipient1))
+	require.Equal(t, big.NewInt(300), tester.appState.State.GetBalance(recipient2))
+	require.Zero(t, tester.ContractBalance().Sign())
+
+	data, err = tester.Read(PaymentScheduleContract, "cancelBlock")
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(40)), data)
+
+	dest, err := tester.Terminate(tester.mainKey, PaymentScheduleContract)
+	require.NoError(t, err)
+	require.Equal(t, tester.mainAddr, dest)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/idena-network/idena-go/common"
 	math2 "github.com/idena-network/idena-go/common/math"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
@@ -18,6 +19,23 @@ const (
 	oracleLockUnlockedRefund  = byte(4)
 )
 
+var refundableOracleLockAbi = &abi.Abi{
+	Deploy: []abi.Arg{
+		{Name: "oracleVotingAddress", Type: abi.Address},
+		{Name: "value", Type: abi.Byte},
+		{Name: "successAddress", Type: abi.Address, Optional: true},
+		{Name: "failAddress", Type: abi.Address, Optional: true},
+		{Name: "refundDelay", Type: abi.Uint64, Optional: true},
+		{Name: "depositDeadline", Type: abi.Uint64},
+		{Name: "oracleVotingFee", Type: abi.Byte},
+	},
+	Methods:   []abi.Method{{Name: "deposit"}, {Name: "push"}, {Name: "refund"}},
+	Terminate: []abi.Arg{{Name: "dest", Type: abi.Address}},
+	Events: []abi.Event{
+		{Name: "refund", Args: []abi.Arg{{Name: "address", Type: abi.Address}, {Name: "amount", Type: abi.BigInt}}},
+	},
+}
+
 type RefundableOracleLock2 struct {
 	*BaseContract
 	deposits *env.Map
@@ -98,6 +116,10 @@ func (e *RefundableOracleLock2) Call(method string, args ...[]byte) error {
 	}
 }
 
+func (e *RefundableOracleLock2) Abi() *abi.Abi {
+	return refundableOracleLockAbi
+}
+
 func (e *RefundableOracleLock2) Read(method string, args ...[]byte) ([]byte, error) {
 	panic("implement me")
 }
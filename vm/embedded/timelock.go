@@ -3,12 +3,26 @@ package embedded
 import (
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/env"
 	"github.com/idena-network/idena-go/vm/helpers"
 	"github.com/pkg/errors"
 	"math/big"
 )
 
+var timeLockAbi = &abi.Abi{
+	Deploy: []abi.Arg{{Name: "timestamp", Type: abi.Uint64}},
+	Methods: []abi.Method{
+		{Name: "transfer", Args: []abi.Arg{{Name: "dest", Type: abi.Address}, {Name: "amount", Type: abi.Dna}}},
+	},
+	ReadMethods: []abi.Method{
+		{Name: "timestamp", Returns: abi.Uint64},
+		{Name: "isUnlocked", Returns: abi.Byte},
+		{Name: "balance", Returns: abi.Dna},
+	},
+	Terminate: []abi.Arg{{Name: "dest", Type: abi.Address}},
+}
+
 type TimeLock struct {
 	*BaseContract
 }
@@ -43,8 +57,24 @@ func (t *TimeLock) Call(method string, args ...[]byte) (err error) {
 	}
 }
 
+func (t *TimeLock) Abi() *abi.Abi {
+	return timeLockAbi
+}
+
 func (t *TimeLock) Read(method string, args ...[]byte) ([]byte, error) {
-	panic("implement me")
+	switch method {
+	case "timestamp":
+		return common.ToBytes(t.GetUint64("timestamp")), nil
+	case "isUnlocked":
+		if uint64(t.env.BlockTimeStamp()) < t.GetUint64("timestamp") {
+			return []byte{0}, nil
+		}
+		return []byte{1}, nil
+	case "balance":
+		return t.env.Balance(t.ctx.ContractAddr()).Bytes(), nil
+	default:
+		return nil, errors.New("unknown method")
+	}
 }
 
 func (t *TimeLock) transfer(args ...[]byte) (err error) {
@@ -0,0 +1,50 @@
+package embedded
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+type timeLockDeploy struct {
+	deployStake *big.Int
+	timestamp   uint64
+}
+
+func (s *deployContractSwitch) TimeLock(timestamp uint64) *timeLockDeploy {
+	return &timeLockDeploy{deployStake: s.deployStake, timestamp: timestamp}
+}
+
+func (d *timeLockDeploy) Parameters() (contract EmbeddedContractType, deployStake *big.Int, params [][]byte) {
+	return TimeLockContract, d.deployStake, [][]byte{common.ToBytes(d.timestamp)}
+}
+
+func TestTimeLock_Read(t *testing.T) {
+	tester := createTestContractBuilder(1, common.DnaBase).Build()
+	require.NoError(t, tester.Deploy(tester.ConfigureDeploy(common.DnaBase).TimeLock(100)))
+	tester.Commit()
+	tester.AddBalance(common.DnaBase)
+
+	data, err := tester.Read(TimeLockContract, "timestamp")
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(100)), data)
+
+	data, err = tester.Read(TimeLockContract, "balance")
+	require.NoError(t, err)
+	require.Equal(t, common.DnaBase.Bytes(), data)
+
+	data, err = tester.Read(TimeLockContract, "isUnlocked")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, data)
+
+	tester.setTimestamp(100)
+	// the call recreates the contract instance with the new block
+	require.Error(t, tester.OwnerCall(TimeLockContract, "transfer"))
+	data, err = tester.Read(TimeLockContract, "isUnlocked")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, data)
+
+	_, err = tester.Read(TimeLockContract, "unknown")
+	require.EqualError(t, err, "unknown method")
+}
@@ -0,0 +1,48 @@
+package embedded
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/idena-network/idena-go/vm/env"
+)
+
+const (
+	// UpgradeMethod is the method of the call tx which upgrades the contract, only the owner may call it.
+	// The name is reserved, so it never shadows a method of the contract
+	UpgradeMethod = abi.ReservedPrefix + "upgrade"
+	// UpgradeEvent is emitted by the upgraded contract with the previous and the new code hashes
+	UpgradeEvent = abi.ReservedPrefix + "upgrade"
+)
+
+// ContractUpgrade moves deployed contracts of the code hash to the newer implementation
+type ContractUpgrade struct {
+	From EmbeddedContractType
+	To   EmbeddedContractType
+	// Migrate converts the storage of the contract to the layout of the new implementation, it may be nil
+	Migrate func(ctx env.CallContext, e env.Env) error
+}
+
+// contractUpgrades maps code hashes of deployed contracts to their upgrades, a new implementation gets its own code hash
+// and is registered here together with the migration of the storage layout
+var contractUpgrades = map[EmbeddedContractType]*ContractUpgrade{}
+
+// GetContractUpgrade returns the upgrade of the contracts with the code hash or nil if there is no newer implementation
+func GetContractUpgrade(codeHash common.Hash) *ContractUpgrade {
+	return contractUpgrades[codeHash]
+}
+
+// Apply migrates the storage of the contract and emits the upgrade event, the code hash is changed by the caller
+func (u *ContractUpgrade) Apply(ctx env.CallContext, e env.Env) error {
+	if u.Migrate != nil {
+		if err := u.Migrate(ctx, e); err != nil {
+			return err
+		}
+	}
+	e.Event(UpgradeEvent, u.From.Bytes(), u.To.Bytes())
+	return nil
+}
+
+// IsContractOwner checks that the sender is the owner of the contract
+func IsContractOwner(ctx env.CallContext, e env.Env) bool {
+	return (&BaseContract{ctx: ctx, env: e}).IsOwner()
+}
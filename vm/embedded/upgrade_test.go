@@ -0,0 +1,73 @@
+package embedded
+
+import (
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestContractUpgrade_Apply(t *testing.T) {
+	require.Nil(t, GetContractUpgrade(OracleVotingContract))
+
+	// the test implementation stores the count of secret votes which oracle votings compute on demand
+	target := common.Hash{0xff}
+	upgrade := &ContractUpgrade{
+		From: OracleVotingContract,
+		To:   target,
+		Migrate: func(ctx env.CallContext, e env.Env) error {
+			NewOracleVotingContract4(ctx, e, nil).getSecretVotesCount()
+			return nil
+		},
+	}
+	contractUpgrades[OracleVotingContract] = upgrade
+	defer delete(contractUpgrades, OracleVotingContract)
+	require.Equal(t, upgrade, GetContractUpgrade(OracleVotingContract))
+
+	tester := createTestContractBuilder(2, common.DnaBase).Build()
+	_, err := tester.ConfigureDeploy(common.DnaBase).OracleVoting().Deploy()
+	require.NoError(t, err)
+	tester.Commit()
+	require.Nil(t, tester.ReadData("secretVotesCount"))
+
+	callAttach := attachments.CreateCallContractAttachment(UpgradeMethod)
+	payload, _ := callAttach.ToBytes()
+	newCtx := func(key bool) env.CallContext {
+		tx := &types.Transaction{AccountNonce: 2, To: &tester.contractAddr, Type: types.CallContractTx, Payload: payload}
+		if key {
+			tx, _ = types.SignTx(tx, tester.mainKey)
+		} else {
+			tx, _ = types.SignTx(tx, tester.identities[0])
+		}
+		return env.NewCallContextImpl(tx, OracleVotingContract)
+	}
+	gas := new(env.GasCounter)
+	gas.Reset(-1)
+	e := env.NewEnvImp(tester.appState, createHeader(3, 30), gas, nil)
+
+	require.False(t, IsContractOwner(newCtx(false), e))
+	ctx := newCtx(true)
+	require.True(t, IsContractOwner(ctx, e))
+
+	require.NoError(t, upgrade.Apply(ctx, e))
+	e.UpgradeContract(ctx, upgrade.To)
+	events := e.Commit()
+	tester.appState.Commit(nil)
+
+	require.Equal(t, target, *tester.CodeHash())
+	require.Equal(t, common.DnaBase, tester.ContractStake())
+	require.Equal(t, common.ToBytes(uint64(0)), tester.ReadData("secretVotesCount"))
+	require.Equal(t, common.ToBytes(byte(0)), tester.ReadData("state"))
+	require.Len(t, events, 1)
+	require.Equal(t, UpgradeEvent, events[0].EventName)
+	require.Equal(t, [][]byte{OracleVotingContract.Bytes(), target.Bytes()}, events[0].Data)
+}
+
+func TestContractUpgrade_Config(t *testing.T) {
+	v8 := config.ConsensusVersions[config.ConsensusV8]
+	require.True(t, v8.EnableContractUpgrades)
+	require.Empty(t, v8.ForcedContractUpgrades)
+}
@@ -11,6 +11,12 @@ import (
 	"github.com/pkg/errors"
 	"math/big"
 	"regexp"
+	"sort"
+)
+
+const (
+	// MaxCallDepth is the max number of nested contract calls
+	MaxCallDepth = 8
 )
 
 var (
@@ -43,8 +49,12 @@ type Env interface {
 	ContractStake(common.Address) *big.Int
 	MoveToStake(ctx CallContext, amount *big.Int) error
 	Delegatee(addr common.Address) *common.Address
+	Call(ctx CallContext, contract common.Address, method string, args ...[]byte) error
 }
 
+// ContractCaller runs the method of the contract in the nested call context, it is provided by the VM
+type ContractCaller func(ctx CallContext, method string, args ...[]byte) error
+
 type contractValue struct {
 	value   []byte
 	removed bool
@@ -61,7 +71,14 @@ type EnvImp struct {
 	deployedContractCache map[common.Address]*state.ContractData
 	droppedContracts      map[common.Address]struct{}
 	events                []*types.TxEvent
-	contractStakeCache    map[common.Address]*big.Int
+	// recipients of coins sent by contracts
+	recipients         []common.Address
+	contractStakeCache map[common.Address]*big.Int
+	contractCodeCache  map[common.Hash][]byte
+
+	contractCaller ContractCaller
+	// contracts of the running nested calls
+	callStack []common.Address
 }
 
 func NewEnvImp(s *appstate.AppState, block *types.Header, gasCounter *GasCounter, statsCollector collector.StatsCollector) *EnvImp {
@@ -72,12 +89,13 @@ func NewEnvImp(s *appstate.AppState, block *types.Header, gasCounter *GasCounter
 		droppedContracts:      map[common.Address]struct{}{},
 		events:                []*types.TxEvent{},
 		contractStakeCache:    map[common.Address]*big.Int{},
+		contractCodeCache:     map[common.Hash][]byte{},
 		statsCollector:        statsCollector,
 	}
 }
 
 func (e *EnvImp) Epoch() uint16 {
-	e.gasCounter.AddGas(10)
+	e.gasCounter.AddGas(e.gasCounter.Table().EpochRead)
 	return e.state.State.Epoch()
 }
 
@@ -113,8 +131,9 @@ func (e *EnvImp) Send(ctx CallContext, dest common.Address, amount *big.Int) err
 	}
 	e.subBalance(ctx.ContractAddr(), amount)
 	e.addBalance(dest, amount)
+	e.recipients = append(e.recipients, dest)
 
-	e.gasCounter.AddGas(30)
+	e.gasCounter.AddGas(e.gasCounter.Table().Send)
 	return nil
 }
 
@@ -126,16 +145,41 @@ func (e *EnvImp) Deploy(ctx CallContext) {
 		CodeHash: ctx.CodeHash(),
 	}
 	collector.AddContractStake(e.statsCollector, stake)
-	e.gasCounter.AddGas(200)
+	e.gasCounter.AddGas(e.gasCounter.Table().Deploy)
+}
+
+// SetContractCode stores the code of a wasm contract, the code is shared by all contracts with the same code hash
+func (e *EnvImp) SetContractCode(codeHash common.Hash, code []byte) {
+	e.contractCodeCache[codeHash] = code
+	e.gasCounter.AddGas(e.gasCounter.Table().CodeWriteByte * len(code))
+}
+
+func (e *EnvImp) ContractCode(codeHash common.Hash) []byte {
+	if code, ok := e.contractCodeCache[codeHash]; ok {
+		return code
+	}
+	code := e.state.State.GetContractCode(codeHash)
+	e.gasCounter.AddGas(e.gasCounter.Table().CodeReadByte * len(code))
+	return code
+}
+
+// UpgradeContract changes the code hash of the deployed contract, the storage and the stake are kept
+func (e *EnvImp) UpgradeContract(ctx CallContext, codeHash common.Hash) {
+	contractAddr := ctx.ContractAddr()
+	e.deployedContractCache[contractAddr] = &state.ContractData{
+		Stake:    e.contractStake(contractAddr),
+		CodeHash: codeHash,
+	}
+	e.gasCounter.AddGas(e.gasCounter.Table().Deploy)
 }
 
 func (e *EnvImp) BlockTimeStamp() int64 {
-	e.gasCounter.AddGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().ChainRead)
 	return e.block.Time()
 }
 
 func (e *EnvImp) BlockNumber() uint64 {
-	e.gasCounter.AddGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().ChainRead)
 	return e.block.Height()
 }
 
@@ -154,7 +198,7 @@ func (e *EnvImp) SetValue(ctx CallContext, key []byte, value []byte) {
 		value:   value,
 		removed: false,
 	}
-	e.gasCounter.AddWrittenBytesAsGas(10 * (len(key) + len(value)))
+	e.gasCounter.AddGas(e.gasCounter.Table().StorageWriteByte * (len(key) + len(value)))
 }
 
 func (e *EnvImp) GetValue(ctx CallContext, key []byte) []byte {
@@ -170,73 +214,96 @@ func (e *EnvImp) RemoveValue(ctx CallContext, key []byte) {
 		e.contractStoreCache[addr] = cache
 	}
 	cache[string(key)] = &contractValue{removed: true}
-	e.gasCounter.AddGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().StorageRemove)
 }
 
 func (e *EnvImp) MinFeePerGas() *big.Int {
-	e.gasCounter.AddGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().ChainRead)
 	return e.state.State.FeePerGas()
 }
 
 func (e *EnvImp) Balance(address common.Address) *big.Int {
-	e.gasCounter.AddReadBytesAsGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().BalanceRead)
 	return e.getBalance(address)
 }
 
 func (e *EnvImp) BlockSeed() []byte {
-	e.gasCounter.AddReadBytesAsGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().ChainRead)
 	return e.block.Seed().Bytes()
 }
 
 func (e *EnvImp) NetworkSize() int {
-	e.gasCounter.AddReadBytesAsGas(5)
+	e.gasCounter.AddGas(e.gasCounter.Table().ChainRead)
 	return e.state.ValidatorsCache.NetworkSize()
 }
 
 func (e *EnvImp) State(sender common.Address) state.IdentityState {
-	e.gasCounter.AddReadBytesAsGas(1)
+	e.gasCounter.AddGas(e.gasCounter.Table().IdentityStateRead)
 	return e.state.State.GetIdentityState(sender)
 }
 
 func (e *EnvImp) PubKey(addr common.Address) []byte {
-	e.gasCounter.AddReadBytesAsGas(10)
+	e.gasCounter.AddGas(e.gasCounter.Table().IdentityRead)
 	return e.state.State.GetIdentity(addr).PubKey
 }
 
 func (e *EnvImp) Delegatee(addr common.Address) *common.Address {
-	e.gasCounter.AddReadBytesAsGas(10)
+	e.gasCounter.AddGas(e.gasCounter.Table().IdentityRead)
 	return e.state.State.Delegatee(addr)
 }
 
+// Iterate iterates over the contract store in the key order, the cached values are merged with the values of the state.
+// The cached values are taken before the iteration, so the values written by f don't affect the iteration.
 func (e *EnvImp) Iterate(ctx CallContext, minKey []byte, maxKey []byte, f func(key []byte, value []byte) (stopped bool)) {
 	addr := ctx.ContractAddr()
 
-	iteratedKeys := make(map[string]struct{})
-
-	if cache, ok := e.contractStoreCache[addr]; ok {
-		for key, value := range cache {
-			keyBytes := []byte(key)
-			if (bytes.Compare(keyBytes, minKey) >= 0 || minKey == nil) && (bytes.Compare(keyBytes, maxKey) <= 0 || maxKey == nil) {
-				iteratedKeys[key] = struct{}{}
-				e.gasCounter.AddReadBytesAsGas(10 * len(value.value))
-				if !value.removed && f(keyBytes, value.value) {
-					return
-				}
-			}
+	var cachedKeys []string
+	cache := e.contractStoreCache[addr]
+	for key := range cache {
+		keyBytes := []byte(key)
+		if (bytes.Compare(keyBytes, minKey) >= 0 || minKey == nil) && (bytes.Compare(keyBytes, maxKey) <= 0 || maxKey == nil) {
+			cachedKeys = append(cachedKeys, key)
 		}
 	}
+	sort.Strings(cachedKeys)
+	cachedValues := make([]*contractValue, len(cachedKeys))
+	for i, key := range cachedKeys {
+		cachedValues[i] = cache[key]
+	}
 
+	next := 0
+	iterateCached := func(value *contractValue, key string) bool {
+		next++
+		e.chargeIteratedItem(len(value.value))
+		return !value.removed && f([]byte(key), value.value)
+	}
+	stopped := false
 	e.state.State.IterateContractStore(addr, minKey, maxKey, func(key []byte, value []byte) bool {
-		if _, ok := iteratedKeys[string(key)]; ok {
-			return false
+		for next < len(cachedKeys) && cachedKeys[next] <= string(key) {
+			cachedKey := cachedKeys[next]
+			if stopped = iterateCached(cachedValues[next], cachedKey); stopped {
+				return true
+			}
+			if cachedKey == string(key) {
+				return false
+			}
 		}
-		e.gasCounter.AddReadBytesAsGas(10 * len(value))
-		return f(key, value)
+		e.chargeIteratedItem(len(value))
+		stopped = f(key, value)
+		return stopped
 	})
+	for !stopped && next < len(cachedKeys) {
+		stopped = iterateCached(cachedValues[next], cachedKeys[next])
+	}
+}
+
+func (e *EnvImp) chargeIteratedItem(valueSize int) {
+	table := e.gasCounter.Table()
+	e.gasCounter.AddGas(table.IterateItem + table.StorageReadByte*valueSize)
 }
 
 func (e *EnvImp) BurnAll(ctx CallContext) {
-	e.gasCounter.AddReadBytesAsGas(10)
+	e.gasCounter.AddGas(e.gasCounter.Table().BurnAll)
 	address := ctx.ContractAddr()
 	collector.AddContractBurntCoins(e.statsCollector, address, e.getBalance)
 	e.setBalance(address, common.Big0)
@@ -248,12 +315,12 @@ func (e *EnvImp) ReadContractData(contractAddr common.Address, key []byte) []byt
 			if value.removed {
 				return nil
 			}
-			e.gasCounter.AddReadBytesAsGas(10 * len(value.value))
+			e.gasCounter.AddGas(e.gasCounter.Table().StorageReadByte * len(value.value))
 			return value.value
 		}
 	}
 	value := e.state.State.GetContractValue(contractAddr, key)
-	e.gasCounter.AddReadBytesAsGas(10 * len(value))
+	e.gasCounter.AddGas(e.gasCounter.Table().StorageReadByte * len(value))
 	return value
 }
 
@@ -296,10 +363,132 @@ func (e *EnvImp) Commit() []*types.TxEvent {
 	for contract, stake := range e.contractStakeCache {
 		e.state.State.SetContractStake(contract, stake)
 	}
+	for codeHash, code := range e.contractCodeCache {
+		e.state.State.SetContractCode(codeHash, code)
+	}
 
 	return e.events
 }
 
+// Recipients returns unique recipients of coins sent by contracts during the tx
+func (e *EnvImp) Recipients() []common.Address {
+	var result []common.Address
+	unique := make(map[common.Address]struct{}, len(e.recipients))
+	for _, addr := range e.recipients {
+		if _, ok := unique[addr]; ok {
+			continue
+		}
+		unique[addr] = struct{}{}
+		result = append(result, addr)
+	}
+	return result
+}
+
+// SetContractCaller enables nested contract calls
+func (e *EnvImp) SetContractCaller(caller ContractCaller) {
+	e.contractCaller = caller
+}
+
+// Call runs the method of another contract within the same tx. The nested call shares the gas counter and
+// its sender is the calling contract. Changes made by a failed nested call are reverted.
+func (e *EnvImp) Call(ctx CallContext, contract common.Address, method string, args ...[]byte) error {
+	e.gasCounter.AddGas(e.gasCounter.Table().Call)
+	if e.contractCaller == nil {
+		return errors.New("contract calls are not supported")
+	}
+	if len(e.callStack) >= MaxCallDepth {
+		return errors.New("max call depth exceeded")
+	}
+	codeHash := e.codeHash(contract)
+	if codeHash == nil {
+		return errors.New("destination is not a contract")
+	}
+	snapshot := e.snapshot()
+	e.callStack = append(e.callStack, contract)
+	defer func() {
+		e.callStack = e.callStack[:len(e.callStack)-1]
+	}()
+	if err := e.contractCaller(NewNestedCallContext(ctx, contract, *codeHash), method, args...); err != nil {
+		e.revert(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (e *EnvImp) codeHash(contract common.Address) *common.Hash {
+	if data, ok := e.deployedContractCache[contract]; ok {
+		return &data.CodeHash
+	}
+	if _, ok := e.droppedContracts[contract]; ok {
+		return nil
+	}
+	return e.state.State.GetCodeHash(contract)
+}
+
+// envSnapshot is a copy of the changes made by the tx, it is used to revert a failed nested call
+type envSnapshot struct {
+	contractStoreCache    map[common.Address]map[string]*contractValue
+	balancesCache         map[common.Address]*big.Int
+	deployedContractCache map[common.Address]state.ContractData
+	droppedContracts      map[common.Address]struct{}
+	contractStakeCache    map[common.Address]*big.Int
+	contractCodeCache     map[common.Hash][]byte
+	events                int
+	recipients            int
+}
+
+func (e *EnvImp) snapshot() *envSnapshot {
+	s := &envSnapshot{
+		contractStoreCache:    make(map[common.Address]map[string]*contractValue, len(e.contractStoreCache)),
+		balancesCache:         make(map[common.Address]*big.Int, len(e.balancesCache)),
+		deployedContractCache: make(map[common.Address]state.ContractData, len(e.deployedContractCache)),
+		droppedContracts:      make(map[common.Address]struct{}, len(e.droppedContracts)),
+		contractStakeCache:    make(map[common.Address]*big.Int, len(e.contractStakeCache)),
+		contractCodeCache:     make(map[common.Hash][]byte, len(e.contractCodeCache)),
+		events:                len(e.events),
+		recipients:            len(e.recipients),
+	}
+	for contract, cache := range e.contractStoreCache {
+		copied := make(map[string]*contractValue, len(cache))
+		for k, v := range cache {
+			copied[k] = v
+		}
+		s.contractStoreCache[contract] = copied
+	}
+	// balances and stakes are copied since the returned values can be changed in place by the callers
+	for addr, balance := range e.balancesCache {
+		s.balancesCache[addr] = new(big.Int).Set(balance)
+	}
+	for contract, data := range e.deployedContractCache {
+		s.deployedContractCache[contract] = *data
+	}
+	for contract := range e.droppedContracts {
+		s.droppedContracts[contract] = struct{}{}
+	}
+	for contract, stake := range e.contractStakeCache {
+		s.contractStakeCache[contract] = new(big.Int).Set(stake)
+	}
+	for codeHash, code := range e.contractCodeCache {
+		s.contractCodeCache[codeHash] = code
+	}
+	return s
+}
+
+func (e *EnvImp) revert(s *envSnapshot) {
+	e.contractStoreCache = s.contractStoreCache
+	e.balancesCache = s.balancesCache
+	e.deployedContractCache = make(map[common.Address]*state.ContractData, len(s.deployedContractCache))
+	for contract, data := range s.deployedContractCache {
+		data := data
+		e.deployedContractCache[contract] = &data
+	}
+	e.droppedContracts = s.droppedContracts
+	e.contractStakeCache = s.contractStakeCache
+	e.contractCodeCache = s.contractCodeCache
+	e.events = e.events[:s.events]
+	e.recipients = e.recipients[:s.recipients]
+}
+
 func (e *EnvImp) Event(name string, args ...[]byte) {
 	if !eventRegexp.MatchString(name) {
 		panic("event name should contain only ASCII characters. Length should be 1-32")
@@ -308,10 +497,15 @@ func (e *EnvImp) Event(name string, args ...[]byte) {
 	for _, a := range args {
 		size += len(a)
 	}
-	e.gasCounter.AddGas(100 + 10*size)
-	e.events = append(e.events, &types.TxEvent{
+	e.gasCounter.AddGas(e.gasCounter.Table().Event + e.gasCounter.Table().EventByte*size)
+	event := &types.TxEvent{
 		EventName: name, Data: args,
-	})
+	}
+	// the contract of the top level call is set by the VM
+	if len(e.callStack) > 0 {
+		event.Contract = e.callStack[len(e.callStack)-1]
+	}
+	e.events = append(e.events, event)
 }
 
 func (e *EnvImp) contractStake(contract common.Address) *big.Int {
@@ -325,7 +519,7 @@ func (e *EnvImp) contractStake(contract common.Address) *big.Int {
 }
 
 func (e *EnvImp) ContractStake(contract common.Address) *big.Int {
-	e.gasCounter.AddGas(10)
+	e.gasCounter.AddGas(e.gasCounter.Table().ContractStakeRead)
 	return e.contractStake(contract)
 }
 
@@ -355,7 +549,10 @@ func (e *EnvImp) Reset() {
 	e.deployedContractCache = map[common.Address]*state.ContractData{}
 	e.droppedContracts = map[common.Address]struct{}{}
 	e.contractStakeCache = map[common.Address]*big.Int{}
+	e.contractCodeCache = map[common.Hash][]byte{}
 	e.events = []*types.TxEvent{}
+	e.recipients = nil
+	e.callStack = nil
 }
 
 type CallContext interface {
@@ -438,6 +635,47 @@ func (d *DeployContextImpl) ContractAddr() common.Address {
 	return result
 }
 
+// NestedCallContext is the context of a contract called by another contract, the sender is the calling contract
+type NestedCallContext struct {
+	parent   CallContext
+	contract common.Address
+	codeHash common.Hash
+}
+
+func NewNestedCallContext(parent CallContext, contract common.Address, codeHash common.Hash) *NestedCallContext {
+	return &NestedCallContext{parent: parent, contract: contract, codeHash: codeHash}
+}
+
+// Parent returns the context of the calling contract
+func (n *NestedCallContext) Parent() CallContext {
+	return n.parent
+}
+
+func (n *NestedCallContext) Sender() common.Address {
+	return n.parent.ContractAddr()
+}
+
+func (n *NestedCallContext) ContractAddr() common.Address {
+	return n.contract
+}
+
+func (n *NestedCallContext) Epoch() uint16 {
+	return n.parent.Epoch()
+}
+
+func (n *NestedCallContext) Nonce() uint32 {
+	return n.parent.Nonce()
+}
+
+// PayAmount is zero, coins are transferred to the called contract by Send
+func (n *NestedCallContext) PayAmount() *big.Int {
+	return big.NewInt(0)
+}
+
+func (n *NestedCallContext) CodeHash() common.Hash {
+	return n.codeHash
+}
+
 type ReadContextImpl struct {
 	Contract common.Address
 	Hash     common.Hash
@@ -7,6 +7,7 @@ import (
 	"github.com/idena-network/idena-go/common/eventbus"
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/crypto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	db2 "github.com/tendermint/tm-db"
 	"math/big"
@@ -152,3 +153,123 @@ func TestEnvImp_basicMethods(t *testing.T) {
 	})
 	require.Equal(t, 0, cnt)
 }
+
+func TestEnvImp_Call(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	key, _ := crypto.GenerateKeyFromSeed(rnd)
+	callee := common.Address{0x2}
+	tx := &types.Transaction{
+		AccountNonce: 1,
+		To:           &common.Address{0x1},
+		Type:         types.CallContractTx,
+	}
+	tx, _ = types.SignTx(tx, key)
+	ctx := NewCallContextImpl(tx, common.Hash{0x1})
+
+	appState, _ := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	appState.State.DeployContract(callee, common.Hash{0x2}, common.DnaBase)
+	appState.State.SetBalance(*tx.To, big.NewInt(10))
+
+	env := NewEnvImp(appState, &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2}}, &GasCounter{gasLimit: -1}, nil)
+	require.Error(t, env.Call(ctx, callee, "inc"))
+
+	var depth int
+	env.SetContractCaller(func(nestedCtx CallContext, method string, args ...[]byte) error {
+		require.Equal(t, nestedCtx.(*NestedCallContext).Parent().ContractAddr(), nestedCtx.Sender())
+		require.Equal(t, callee, nestedCtx.ContractAddr())
+		require.Equal(t, common.Hash{0x2}, nestedCtx.CodeHash())
+		env.SetValue(nestedCtx, []byte("n"), args[0])
+		env.Event("inc", args[0])
+		switch method {
+		case "fail":
+			require.NoError(t, env.Send(nestedCtx, common.Address{0x5}, big.NewInt(1)))
+			return errors.New("failed")
+		case "mutate":
+			env.Balance(nestedCtx.Sender()).SetInt64(0)
+			return errors.New("failed")
+		case "recursive":
+			depth++
+			return env.Call(nestedCtx, callee, method, args...)
+		}
+		return nil
+	})
+
+	require.NoError(t, env.Send(ctx, callee, big.NewInt(3)))
+	require.NoError(t, env.Call(ctx, callee, "inc", []byte{0x1}))
+	require.EqualError(t, env.Call(ctx, callee, "fail", []byte{0x2}), "failed")
+	// the caller's balance changed in place by the reverted nested call is restored
+	require.EqualError(t, env.Call(ctx, callee, "mutate", []byte{0x2}), "failed")
+	require.Equal(t, big.NewInt(7), env.Balance(*tx.To))
+	require.EqualError(t, env.Call(ctx, callee, "recursive", []byte{0x3}), "max call depth exceeded")
+	require.Equal(t, MaxCallDepth, depth)
+	require.EqualError(t, env.Call(ctx, common.Address{0x3}, "inc"), "destination is not a contract")
+
+	require.Equal(t, []byte{0x1}, env.ReadContractData(callee, []byte("n")))
+	require.Equal(t, big.NewInt(3), env.Balance(callee))
+	// coins sent by the failed nested call are not transferred
+	require.Equal(t, []common.Address{callee}, env.Recipients())
+
+	events := env.Commit()
+	require.Len(t, events, 1)
+	require.Equal(t, callee, events[0].Contract)
+	require.Equal(t, []byte{0x1}, appState.State.GetContractValue(callee, []byte("n")))
+}
+
+func TestEnvImp_IterateOrder(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tx, _ := types.SignTx(&types.Transaction{Type: types.CallContractTx, To: &common.Address{0x1}}, key)
+	ctx := NewCallContextImpl(tx, common.Hash{0x1})
+	contract := ctx.ContractAddr()
+
+	appState, _ := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	for i := byte(0); i < 20; i += 2 {
+		appState.State.SetContractValue(contract, []byte{i}, []byte{i})
+	}
+	appState.Commit(nil)
+	// uncommitted values of the state
+	appState.State.SetContractValue(contract, []byte{3}, []byte{3})
+	appState.State.SetContractValue(contract, []byte{4}, []byte{0x44})
+	appState.State.RemoveContractValue(contract, []byte{6})
+
+	type item struct {
+		key, value []byte
+	}
+	iterate := func(minKey, maxKey []byte, limit int) ([]item, int) {
+		gas := &GasCounter{gasLimit: -1}
+		env := NewEnvImp(appState, &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2}}, gas, nil)
+		for i := byte(1); i < 20; i += 4 {
+			env.SetValue(ctx, []byte{i}, []byte{i, i})
+		}
+		env.SetValue(ctx, []byte{8}, []byte{0x88})
+		env.RemoveValue(ctx, []byte{10})
+		gas.Reset(-1)
+		var items []item
+		env.Iterate(ctx, minKey, maxKey, func(key []byte, value []byte) bool {
+			items = append(items, item{key, value})
+			// values written during the iteration are not iterated
+			env.SetValue(ctx, []byte{key[0] + 1}, []byte{0xff})
+			env.RemoveValue(ctx, []byte{key[0] + 2})
+			return len(items) == limit
+		})
+		return items, gas.UsedGas
+	}
+
+	expected := []item{
+		{[]byte{0}, []byte{0}}, {[]byte{1}, []byte{1, 1}}, {[]byte{2}, []byte{2}}, {[]byte{3}, []byte{3}},
+		{[]byte{4}, []byte{0x44}}, {[]byte{5}, []byte{5, 5}}, {[]byte{8}, []byte{0x88}}, {[]byte{9}, []byte{9, 9}},
+		{[]byte{12}, []byte{12}}, {[]byte{13}, []byte{13, 13}}, {[]byte{14}, []byte{14}}, {[]byte{16}, []byte{16}},
+		{[]byte{17}, []byte{17, 17}}, {[]byte{18}, []byte{18}},
+	}
+	items, gas := iterate(nil, nil, 0)
+	require.Equal(t, expected, items)
+	for i := 0; i < 20; i++ {
+		repeated, repeatedGas := iterate(nil, nil, 0)
+		require.Equal(t, items, repeated)
+		require.Equal(t, gas, repeatedGas)
+	}
+
+	for i := 0; i < 20; i++ {
+		items, _ = iterate([]byte{3}, []byte{13}, 4)
+		require.Equal(t, expected[3:7], items)
+	}
+}
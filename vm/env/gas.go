@@ -1,8 +1,16 @@
 package env
 
+import "github.com/idena-network/idena-go/config"
+
 type GasCounter struct {
 	UsedGas  int
 	gasLimit int
+	table    *config.GasTable
+}
+
+// NewGasCounter creates a gas counter which charges operations according to the table
+func NewGasCounter(table *config.GasTable) *GasCounter {
+	return &GasCounter{table: table}
 }
 
 func (g *GasCounter) AddGas(gas int) {
@@ -12,12 +20,12 @@ func (g *GasCounter) AddGas(gas int) {
 	}
 }
 
-func (g *GasCounter) AddWrittenBytesAsGas(size int) {
-	g.AddGas(size * 2)
-}
-
-func (g *GasCounter) AddReadBytesAsGas(size int) {
-	g.AddGas(size)
+// Table returns the gas costs of the operations, the table of the default consensus version is used if it is not set
+func (g *GasCounter) Table() *config.GasTable {
+	if g.table == nil {
+		return &config.GetDefaultConsensusConfig().GasTable
+	}
+	return g.table
 }
 
 func (g *GasCounter) Reset(gasLimit int) {
@@ -0,0 +1,51 @@
+package env
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	db2 "github.com/tendermint/tm-db"
+	"math/big"
+	"testing"
+)
+
+func TestGasCounter_Table(t *testing.T) {
+	require.Equal(t, config.GetDefaultConsensusConfig().GasTable, *new(GasCounter).Table())
+
+	table := config.GetDefaultConsensusConfig().GasTable
+	table.Send = 1
+	table.StorageWriteByte = 3
+	table.IterateItem = 7
+	table.Event = 11
+	table.EventByte = 2
+
+	key, _ := crypto.GenerateKey()
+	tx, _ := types.SignTx(&types.Transaction{Type: types.CallContractTx, To: &common.Address{0x1}}, key)
+	ctx := NewCallContextImpl(tx, common.Hash{0x1})
+
+	appState, _ := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	appState.State.AddBalance(ctx.ContractAddr(), big.NewInt(10))
+
+	gas := NewGasCounter(&table)
+	gas.Reset(-1)
+	env := NewEnvImp(appState, &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2}}, gas, nil)
+
+	require.NoError(t, env.Send(ctx, common.Address{0x2}, big.NewInt(1)))
+	require.Equal(t, 1, gas.UsedGas)
+
+	env.SetValue(ctx, []byte{0x1}, []byte{0x1, 0x2})
+	require.Equal(t, 1+3*3, gas.UsedGas)
+
+	env.Iterate(ctx, nil, nil, func(key []byte, value []byte) bool {
+		return false
+	})
+	require.Equal(t, 1+3*3+7+table.StorageReadByte*2, gas.UsedGas)
+
+	gas.Reset(-1)
+	env.Event("test", []byte{0x1, 0x2, 0x3})
+	require.Equal(t, 11+2*3, gas.UsedGas)
+}
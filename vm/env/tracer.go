@@ -0,0 +1,324 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/state"
+	"math/big"
+	"sort"
+)
+
+// Tracer collects Env calls made by a contract and the state changes produced by the execution
+type Tracer struct {
+	Steps     []*TraceStep
+	StateDiff *StateDiff
+}
+
+type TraceStep struct {
+	Method   string
+	Contract *common.Address
+	Args     []interface{}
+	Result   interface{}
+	Error    string
+	// Gas is the gas charged during the call including the gas of nested calls
+	Gas int
+	// UsedGas is the total gas used by the execution after the call
+	UsedGas int
+	// Depth is greater than zero for calls made inside Iterate callbacks and nested contract calls
+	Depth int
+}
+
+type StateDiff struct {
+	Balances            []*ValueChange
+	ContractStakes      []*ValueChange
+	Storage             []*StorageChange
+	DeployedContracts   []common.Address
+	TerminatedContracts []common.Address
+}
+
+type ValueChange struct {
+	Address common.Address
+	Before  *big.Int
+	After   *big.Int
+}
+
+type StorageChange struct {
+	Contract common.Address
+	Key      []byte
+	Before   []byte
+	After    []byte
+	Removed  bool
+}
+
+// VmEnv is the Env extended with the methods called by the VM itself
+type VmEnv interface {
+	Env
+	Deploy(ctx CallContext)
+	Terminate(ctx CallContext, dest common.Address)
+	UpgradeContract(ctx CallContext, codeHash common.Hash)
+}
+
+type tracingEnv struct {
+	*EnvImp
+	tracer *Tracer
+	depth  int
+}
+
+// NewTracingEnv wraps the env to record each call into the tracer
+func NewTracingEnv(e *EnvImp, tracer *Tracer) VmEnv {
+	return &tracingEnv{EnvImp: e, tracer: tracer}
+}
+
+func (e *tracingEnv) begin(method string, ctx CallContext, args ...interface{}) *TraceStep {
+	step := &TraceStep{
+		Method:  method,
+		Args:    args,
+		Depth:   e.depth,
+		UsedGas: e.gasCounter.UsedGas,
+	}
+	if ctx != nil {
+		contract := ctx.ContractAddr()
+		step.Contract = &contract
+	}
+	e.tracer.Steps = append(e.tracer.Steps, step)
+	return step
+}
+
+// end completes the step, r is a recovered panic which is re-raised after recording
+func (e *tracingEnv) end(step *TraceStep, result interface{}, err error, r interface{}) {
+	step.Gas = e.gasCounter.UsedGas - step.UsedGas
+	step.UsedGas = e.gasCounter.UsedGas
+	step.Result = result
+	if err != nil {
+		step.Error = err.Error()
+	}
+	if r != nil {
+		step.Error = fmt.Sprint(r)
+		panic(r)
+	}
+}
+
+func (e *tracingEnv) BlockNumber() (res uint64) {
+	step := e.begin("BlockNumber", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.BlockNumber()
+}
+
+func (e *tracingEnv) BlockTimeStamp() (res int64) {
+	step := e.begin("BlockTimeStamp", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.BlockTimeStamp()
+}
+
+func (e *tracingEnv) SetValue(ctx CallContext, key []byte, value []byte) {
+	step := e.begin("SetValue", ctx, key, value)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.SetValue(ctx, key, value)
+}
+
+func (e *tracingEnv) GetValue(ctx CallContext, key []byte) (res []byte) {
+	step := e.begin("GetValue", ctx, key)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.GetValue(ctx, key)
+}
+
+func (e *tracingEnv) RemoveValue(ctx CallContext, key []byte) {
+	step := e.begin("RemoveValue", ctx, key)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.RemoveValue(ctx, key)
+}
+
+func (e *tracingEnv) Send(ctx CallContext, dest common.Address, amount *big.Int) (err error) {
+	step := e.begin("Send", ctx, dest, amount)
+	defer func() { e.end(step, nil, err, recover()) }()
+	return e.EnvImp.Send(ctx, dest, amount)
+}
+
+func (e *tracingEnv) MinFeePerGas() (res *big.Int) {
+	step := e.begin("MinFeePerGas", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.MinFeePerGas()
+}
+
+func (e *tracingEnv) Balance(address common.Address) (res *big.Int) {
+	step := e.begin("Balance", nil, address)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.Balance(address)
+}
+
+func (e *tracingEnv) BlockSeed() (res []byte) {
+	step := e.begin("BlockSeed", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.BlockSeed()
+}
+
+func (e *tracingEnv) NetworkSize() (res int) {
+	step := e.begin("NetworkSize", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.NetworkSize()
+}
+
+func (e *tracingEnv) State(sender common.Address) (res state.IdentityState) {
+	step := e.begin("State", nil, sender)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.State(sender)
+}
+
+func (e *tracingEnv) PubKey(addr common.Address) (res []byte) {
+	step := e.begin("PubKey", nil, addr)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.PubKey(addr)
+}
+
+func (e *tracingEnv) Iterate(ctx CallContext, minKey []byte, maxKey []byte, f func(key []byte, value []byte) bool) {
+	step := e.begin("Iterate", ctx, minKey, maxKey)
+	e.depth++
+	defer func() {
+		e.depth--
+		e.end(step, nil, nil, recover())
+	}()
+	e.EnvImp.Iterate(ctx, minKey, maxKey, f)
+}
+
+func (e *tracingEnv) BurnAll(ctx CallContext) {
+	step := e.begin("BurnAll", ctx)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.BurnAll(ctx)
+}
+
+func (e *tracingEnv) ReadContractData(contractAddr common.Address, key []byte) (res []byte) {
+	step := e.begin("ReadContractData", nil, contractAddr, key)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.ReadContractData(contractAddr, key)
+}
+
+func (e *tracingEnv) Event(name string, args ...[]byte) {
+	stepArgs := []interface{}{name}
+	for _, arg := range args {
+		stepArgs = append(stepArgs, arg)
+	}
+	step := e.begin("Event", nil, stepArgs...)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.Event(name, args...)
+}
+
+func (e *tracingEnv) Epoch() (res uint16) {
+	step := e.begin("Epoch", nil)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.Epoch()
+}
+
+func (e *tracingEnv) ContractStake(contract common.Address) (res *big.Int) {
+	step := e.begin("ContractStake", nil, contract)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.ContractStake(contract)
+}
+
+func (e *tracingEnv) MoveToStake(ctx CallContext, amount *big.Int) (err error) {
+	step := e.begin("MoveToStake", ctx, amount)
+	defer func() { e.end(step, nil, err, recover()) }()
+	return e.EnvImp.MoveToStake(ctx, amount)
+}
+
+func (e *tracingEnv) Delegatee(addr common.Address) (res *common.Address) {
+	step := e.begin("Delegatee", nil, addr)
+	defer func() { e.end(step, res, nil, recover()) }()
+	return e.EnvImp.Delegatee(addr)
+}
+
+func (e *tracingEnv) Call(ctx CallContext, contract common.Address, method string, args ...[]byte) (err error) {
+	stepArgs := []interface{}{contract, method}
+	for _, arg := range args {
+		stepArgs = append(stepArgs, arg)
+	}
+	step := e.begin("Call", ctx, stepArgs...)
+	e.depth++
+	defer func() {
+		e.depth--
+		e.end(step, nil, err, recover())
+	}()
+	return e.EnvImp.Call(ctx, contract, method, args...)
+}
+
+// Deploy and Terminate are called by the VM itself, they are traced to account for the whole used gas
+func (e *tracingEnv) Deploy(ctx CallContext) {
+	step := e.begin("Deploy", ctx, ctx.PayAmount())
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.Deploy(ctx)
+}
+
+func (e *tracingEnv) Terminate(ctx CallContext, dest common.Address) {
+	step := e.begin("Terminate", ctx, dest)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.Terminate(ctx, dest)
+}
+
+// StateDiff returns the changes which will be applied to the state by Commit
+func (e *EnvImp) StateDiff() *StateDiff {
+	diff := &StateDiff{}
+	for addr, balance := range e.balancesCache {
+		diff.Balances = append(diff.Balances, &ValueChange{
+			Address: addr,
+			Before:  e.state.State.GetBalance(addr),
+			After:   balance,
+		})
+	}
+	for contract, stake := range e.contractStakeCache {
+		diff.ContractStakes = append(diff.ContractStakes, &ValueChange{
+			Address: contract,
+			Before:  e.state.State.GetContractStake(contract),
+			After:   stake,
+		})
+	}
+	for contract, data := range e.deployedContractCache {
+		diff.DeployedContracts = append(diff.DeployedContracts, contract)
+		diff.ContractStakes = append(diff.ContractStakes, &ValueChange{
+			Address: contract,
+			Before:  big.NewInt(0),
+			After:   data.Stake,
+		})
+	}
+	for contract := range e.droppedContracts {
+		diff.TerminatedContracts = append(diff.TerminatedContracts, contract)
+	}
+	for contract, cache := range e.contractStoreCache {
+		for key, value := range cache {
+			diff.Storage = append(diff.Storage, &StorageChange{
+				Contract: contract,
+				Key:      []byte(key),
+				Before:   e.state.State.GetContractValue(contract, []byte(key)),
+				After:    value.value,
+				Removed:  value.removed,
+			})
+		}
+	}
+
+	sortChanges := func(changes []*ValueChange) {
+		sort.Slice(changes, func(i, j int) bool {
+			return bytes.Compare(changes[i].Address.Bytes(), changes[j].Address.Bytes()) < 0
+		})
+	}
+	sortAddresses := func(addresses []common.Address) {
+		sort.Slice(addresses, func(i, j int) bool {
+			return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+		})
+	}
+	sortChanges(diff.Balances)
+	sortChanges(diff.ContractStakes)
+	sortAddresses(diff.DeployedContracts)
+	sortAddresses(diff.TerminatedContracts)
+	sort.Slice(diff.Storage, func(i, j int) bool {
+		if c := bytes.Compare(diff.Storage[i].Contract.Bytes(), diff.Storage[j].Contract.Bytes()); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(diff.Storage[i].Key, diff.Storage[j].Key) < 0
+	})
+	return diff
+}
+
+func (e *tracingEnv) UpgradeContract(ctx CallContext, codeHash common.Hash) {
+	step := e.begin("UpgradeContract", ctx, codeHash)
+	defer func() { e.end(step, nil, nil, recover()) }()
+	e.EnvImp.UpgradeContract(ctx, codeHash)
+}
@@ -0,0 +1,81 @@
+package env
+
+import (
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/stretchr/testify/require"
+	db2 "github.com/tendermint/tm-db"
+	"math/big"
+	"testing"
+)
+
+func TestTracingEnv(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	attachment := attachments.CreateDeployContractAttachment(common.Hash{0x1})
+	payload, _ := attachment.ToBytes()
+	tx, _ := types.SignTx(&types.Transaction{
+		AccountNonce: 1,
+		Type:         types.DeployContractTx,
+		Amount:       common.DnaBase,
+		Payload:      payload,
+	}, key)
+	ctx := NewDeployContextImpl(tx, attachment.CodeHash)
+
+	appState, _ := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	appState.State.AddBalance(ctx.ContractAddr(), big.NewInt(100))
+	appState.State.SetContractValue(ctx.ContractAddr(), []byte{0x1}, []byte{0x1})
+
+	gas := &GasCounter{gasLimit: 1000}
+	tracer := &Tracer{}
+	imp := NewEnvImp(appState, &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2}}, gas, nil)
+	env := NewTracingEnv(imp, tracer)
+
+	require.Equal(t, []byte{0x1}, env.GetValue(ctx, []byte{0x1}))
+	env.SetValue(ctx, []byte{0x2}, []byte{0x2})
+	require.Error(t, env.Send(ctx, common.Address{0x2}, big.NewInt(200)))
+	require.NoError(t, env.Send(ctx, common.Address{0x2}, big.NewInt(10)))
+	env.Iterate(ctx, nil, nil, func(key []byte, value []byte) bool {
+		env.RemoveValue(ctx, key)
+		return false
+	})
+	require.Panics(t, func() {
+		env.Event("big", make([]byte, 100))
+	})
+
+	steps := tracer.Steps
+	require.Len(t, steps, 8)
+	require.Equal(t, "GetValue", steps[0].Method)
+	require.Equal(t, ctx.ContractAddr(), *steps[0].Contract)
+	require.Equal(t, []byte{0x1}, steps[0].Result)
+	require.Equal(t, 10, steps[0].Gas)
+	require.Equal(t, "SetValue", steps[1].Method)
+	require.Equal(t, 40, steps[1].Gas)
+	require.Equal(t, "insufficient funds", steps[2].Error)
+	require.Equal(t, 30, steps[3].Gas)
+	require.Equal(t, "Iterate", steps[4].Method)
+	require.Equal(t, "RemoveValue", steps[5].Method)
+	require.Equal(t, 1, steps[5].Depth)
+	require.Equal(t, steps[6].UsedGas, steps[4].UsedGas)
+	require.Equal(t, "Event", steps[7].Method)
+	require.Equal(t, "not enough gas", steps[7].Error)
+	require.Equal(t, gas.UsedGas, steps[7].UsedGas)
+
+	diff := imp.StateDiff()
+	require.Len(t, diff.Balances, 2)
+	for _, change := range diff.Balances {
+		if change.Address == ctx.ContractAddr() {
+			require.Equal(t, 0, big.NewInt(100).Cmp(change.Before))
+			require.Equal(t, 0, big.NewInt(90).Cmp(change.After))
+		} else {
+			require.Equal(t, common.Address{0x2}, change.Address)
+			require.Equal(t, 0, big.NewInt(10).Cmp(change.After))
+		}
+	}
+	require.Len(t, diff.Storage, 2)
+	require.True(t, diff.Storage[0].Removed)
+	require.Equal(t, []byte{0x1}, diff.Storage[0].Before)
+}
@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"context"
 	"fmt"
 	"github.com/idena-network/idena-go/blockchain/attachments"
 	"github.com/idena-network/idena-go/blockchain/types"
@@ -8,12 +9,19 @@ import (
 	"github.com/idena-network/idena-go/common/math"
 	"github.com/idena-network/idena-go/config"
 	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/stats/collector"
+	"github.com/idena-network/idena-go/vm/abi"
 	"github.com/idena-network/idena-go/vm/embedded"
 	env2 "github.com/idena-network/idena-go/vm/env"
+	"github.com/idena-network/idena-go/vm/wasm"
 	"github.com/pkg/errors"
 )
 
+// ReadGasLimit caps the gas of reads and estimations which aren't limited by the max fee of a mined tx,
+// so an endless contract loop can't hang the caller
+const ReadGasLimit = 10000000
+
 var (
 	UnexpectedTx = errors.New("unexpected tx type")
 )
@@ -21,42 +29,85 @@ var (
 type VM interface {
 	Run(tx *types.Transaction, gasLimit int64) *types.TxReceipt
 	Read(contractAddr common.Address, method string, args ...[]byte) ([]byte, error)
+	Abi(codeHash common.Hash) (*abi.Abi, error)
 }
 
 type VmImpl struct {
 	env            *env2.EnvImp
+	contractEnv    env2.VmEnv
+	tracer         *env2.Tracer
 	appState       *appstate.AppState
 	gasCounter     *env2.GasCounter
 	statsCollector collector.StatsCollector
 	cfg            *config.Config
+	interrupt      context.Context
 }
 
 type VmCreator = func(appState *appstate.AppState, block *types.Header, statsCollector collector.StatsCollector, cfg *config.Config) VM
 
 func NewVmImpl(appState *appstate.AppState, block *types.Header, statsCollector collector.StatsCollector, cfg *config.Config) VM {
-	gasCounter := new(env2.GasCounter)
-	return &VmImpl{env: env2.NewEnvImp(appState, block, gasCounter, statsCollector), appState: appState, gasCounter: gasCounter,
+	gasCounter := env2.NewGasCounter(&cfg.Consensus.GasTable)
+	e := env2.NewEnvImp(appState, block, gasCounter, statsCollector)
+	vm := &VmImpl{env: e, contractEnv: e, appState: appState, gasCounter: gasCounter,
 		statsCollector: statsCollector, cfg: cfg}
+	e.SetContractCaller(vm.callContract)
+	return vm
+}
+
+// NewTracingVmImpl creates VM which records Env calls, charged gas and state changes of each run into the tracer
+func NewTracingVmImpl(appState *appstate.AppState, block *types.Header, statsCollector collector.StatsCollector, cfg *config.Config, tracer *env2.Tracer) VM {
+	vm := NewVmImpl(appState, block, statsCollector, cfg).(*VmImpl)
+	vm.contractEnv = env2.NewTracingEnv(vm.env, tracer)
+	vm.tracer = tracer
+	return vm
+}
+
+// NewInterruptibleVmImpl creates VM for reads and estimations requested by the API, the execution of contract code
+// is interrupted once ctx is done
+func NewInterruptibleVmImpl(ctx context.Context, appState *appstate.AppState, block *types.Header, cfg *config.Config) VM {
+	vm := NewVmImpl(appState, block, nil, cfg).(*VmImpl)
+	vm.interrupt = ctx
+	return vm
 }
 
 func (vm *VmImpl) createContract(ctx env2.CallContext) embedded.Contract {
 	switch ctx.CodeHash() {
 	case embedded.TimeLockContract:
-		return embedded.NewTimeLock(ctx, vm.env, vm.statsCollector)
+		return embedded.NewTimeLock(ctx, vm.contractEnv, vm.statsCollector)
 	case embedded.OracleVotingContract:
 		if vm.cfg.Consensus.EnableUpgrade7 {
-			return embedded.NewOracleVotingContract4(ctx, vm.env, vm.statsCollector)
+			return embedded.NewOracleVotingContract4(ctx, vm.contractEnv, vm.statsCollector)
 		}
-		return embedded.NewOracleVotingContract3(ctx, vm.env, vm.statsCollector)
+		return embedded.NewOracleVotingContract3(ctx, vm.contractEnv, vm.statsCollector)
 	case embedded.OracleLockContract:
-		return embedded.NewOracleLock2(ctx, vm.env, vm.statsCollector)
+		return embedded.NewOracleLock2(ctx, vm.contractEnv, vm.statsCollector)
 	case embedded.RefundableOracleLockContract:
-		return embedded.NewRefundableOracleLock2(ctx, vm.env, vm.statsCollector)
+		return embedded.NewRefundableOracleLock2(ctx, vm.contractEnv, vm.statsCollector)
 	case embedded.MultisigContract:
-		return embedded.NewMultisig(ctx, vm.env, vm.statsCollector)
+		return embedded.NewMultisig(ctx, vm.contractEnv, vm.statsCollector)
+	case embedded.PaymentScheduleContract:
+		if !vm.cfg.Consensus.EnablePaymentSchedules {
+			return nil
+		}
+		return embedded.NewPaymentSchedule(ctx, vm.contractEnv, vm.statsCollector)
 	default:
+		if !vm.cfg.Consensus.EnableWasmContracts {
+			return nil
+		}
+		return vm.createWasmContract(ctx, vm.env.ContractCode(ctx.CodeHash()))
+	}
+}
+
+// createWasmContract returns nil if the code is not a valid module
+func (vm *VmImpl) createWasmContract(ctx env2.CallContext, code []byte) embedded.Contract {
+	if len(code) == 0 {
+		return nil
+	}
+	module, err := wasm.ParseCached(ctx.CodeHash(), code)
+	if err != nil {
 		return nil
 	}
+	return wasm.NewContract(ctx, vm.contractEnv, vm.gasCounter, vm.interrupt, module)
 }
 
 func (vm *VmImpl) deploy(tx *types.Transaction) (addr common.Address, err error) {
@@ -66,7 +117,16 @@ func (vm *VmImpl) deploy(tx *types.Transaction) (addr common.Address, err error)
 	if attach == nil {
 		return addr, errors.New("can't parse attachment")
 	}
-	contract := vm.createContract(ctx)
+	args := attach.Args
+	var code []byte
+	var contract embedded.Contract
+	if _, ok := embedded.AvailableContracts[attach.CodeHash]; ok {
+		contract = vm.createContract(ctx)
+	} else if vm.cfg.Consensus.EnableWasmContracts && len(args) > 0 && crypto.Hash(args[0]) == attach.CodeHash {
+		// the code of a wasm contract is the first arg, the rest args are passed to the deploy method
+		code, args = args[0], args[1:]
+		contract = vm.createWasmContract(ctx, code)
+	}
 	if contract == nil {
 		return addr, errors.New("unknown contract")
 	}
@@ -75,8 +135,11 @@ func (vm *VmImpl) deploy(tx *types.Transaction) (addr common.Address, err error)
 			err = errors.New(fmt.Sprint(r))
 		}
 	}()
-	err = contract.Deploy(attach.Args...)
-	vm.env.Deploy(ctx)
+	if code != nil {
+		vm.env.SetContractCode(attach.CodeHash, code)
+	}
+	err = contract.Deploy(args...)
+	vm.contractEnv.Deploy(ctx)
 	return addr, err
 }
 
@@ -91,41 +154,98 @@ func (vm *VmImpl) call(tx *types.Transaction) (addr common.Address, method strin
 	if attach == nil {
 		return ctx.ContractAddr(), "", errors.New("can't parse attachment")
 	}
-	contract := vm.createContract(ctx)
 	addr = ctx.ContractAddr()
-	if contract == nil {
-		return addr, "", errors.New("unknown contract")
-	}
 
 	defer func() {
 		if r := recover(); r != nil {
 			err = errors.New(fmt.Sprint(r))
 		}
 	}()
+	if ctx, err = vm.applyForcedUpgrade(tx, ctx); err != nil {
+		return addr, attach.Method, err
+	}
+	if attach.Method == embedded.UpgradeMethod {
+		if upgrade := vm.contractUpgrade(ctx.CodeHash()); upgrade != nil {
+			if !embedded.IsContractOwner(ctx, vm.contractEnv) {
+				return addr, attach.Method, errors.New("sender is not an owner")
+			}
+			return addr, attach.Method, vm.upgradeContract(ctx, upgrade)
+		}
+	}
+	contract := vm.createContract(ctx)
+	if contract == nil {
+		return addr, "", errors.New("unknown contract")
+	}
 	err = contract.Call(attach.Method, attach.Args...)
 	return addr, attach.Method, err
 }
 
+// contractUpgrade returns the upgrade of the contracts with the code hash or nil if upgrades are disabled
+func (vm *VmImpl) contractUpgrade(codeHash common.Hash) *embedded.ContractUpgrade {
+	if !vm.cfg.Consensus.EnableContractUpgrades {
+		return nil
+	}
+	return embedded.GetContractUpgrade(codeHash)
+}
+
+// applyForcedUpgrade upgrades the contract if its code hash is forced to upgrade by consensus and returns the context
+// with the new code hash
+func (vm *VmImpl) applyForcedUpgrade(tx *types.Transaction, ctx *env2.CallContextImpl) (*env2.CallContextImpl, error) {
+	upgrade := vm.contractUpgrade(ctx.CodeHash())
+	if upgrade == nil {
+		return ctx, nil
+	}
+	for _, codeHash := range vm.cfg.Consensus.ForcedContractUpgrades {
+		if codeHash == upgrade.From {
+			if err := vm.upgradeContract(ctx, upgrade); err != nil {
+				return ctx, err
+			}
+			return env2.NewCallContextImpl(tx, upgrade.To), nil
+		}
+	}
+	return ctx, nil
+}
+
+func (vm *VmImpl) upgradeContract(ctx env2.CallContext, upgrade *embedded.ContractUpgrade) error {
+	if err := upgrade.Apply(ctx, vm.contractEnv); err != nil {
+		return err
+	}
+	vm.contractEnv.UpgradeContract(ctx, upgrade.To)
+	return nil
+}
+
+// callContract runs the nested call made by Env.Call
+func (vm *VmImpl) callContract(ctx env2.CallContext, method string, args ...[]byte) error {
+	contract := vm.createContract(ctx)
+	if contract == nil {
+		return errors.New("unknown contract")
+	}
+	return contract.Call(method, args...)
+}
+
 func (vm *VmImpl) terminate(tx *types.Transaction) (addr common.Address, err error) {
 	ctx := env2.NewCallContextImpl(tx, *vm.appState.State.GetCodeHash(*tx.To))
 	attach := attachments.ParseTerminateContractAttachment(tx)
 	if attach == nil {
 		return ctx.ContractAddr(), errors.New("can't parse attachment")
 	}
-	contract := vm.createContract(ctx)
 	addr = ctx.ContractAddr()
-	if contract == nil {
-		return addr, errors.New("unknown contract")
-	}
 	defer func() {
 		if r := recover(); r != nil {
 			err = errors.New(fmt.Sprint(r))
 		}
 	}()
+	if ctx, err = vm.applyForcedUpgrade(tx, ctx); err != nil {
+		return addr, err
+	}
+	contract := vm.createContract(ctx)
+	if contract == nil {
+		return addr, errors.New("unknown contract")
+	}
 	var stakeDest common.Address
 	stakeDest, err = contract.Terminate(attach.Args...)
 	if err == nil {
-		vm.env.Terminate(ctx, stakeDest)
+		vm.contractEnv.Terminate(ctx, stakeDest)
 	}
 	return addr, err
 }
@@ -153,8 +273,18 @@ func (vm *VmImpl) Run(tx *types.Transaction, gasLimit int64) *types.TxReceipt {
 	}
 
 	var events []*types.TxEvent
+	var recipients []common.Address
 	if err == nil {
+		recipients = vm.env.Recipients()
+		if vm.tracer != nil {
+			vm.tracer.StateDiff = vm.env.StateDiff()
+		}
 		events = vm.env.Commit()
+		for _, event := range events {
+			if event.Contract.IsEmpty() {
+				event.Contract = contractAddr
+			}
+		}
 	}
 
 	sender, _ := types.Sender(tx)
@@ -173,22 +303,34 @@ func (vm *VmImpl) Run(tx *types.Transaction, gasLimit int64) *types.TxReceipt {
 		ContractAddress: contractAddr,
 		Events:          events,
 		Method:          method,
+		Recipients:      recipients,
 	}
 }
 
-func (vm *VmImpl) Read(contractAddr common.Address, method string, args ...[]byte) ([]byte, error) {
-	var data []byte
-	var err error
+func (vm *VmImpl) Read(contractAddr common.Address, method string, args ...[]byte) (data []byte, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = errors.New(fmt.Sprint(r))
 		}
 	}()
-	contract := vm.createContract(&env2.ReadContextImpl{Contract: contractAddr, Hash: *vm.appState.State.GetCodeHash(contractAddr)})
+	vm.gasCounter.Reset(ReadGasLimit)
+	codeHash := vm.appState.State.GetCodeHash(contractAddr)
+	if codeHash == nil {
+		return nil, errors.New("destination is not a contract")
+	}
+	contract := vm.createContract(&env2.ReadContextImpl{Contract: contractAddr, Hash: *codeHash})
+	if contract == nil {
+		return nil, errors.New("unknown contract")
+	}
+	return contract.Read(method, args...)
+}
+
+// Abi returns the ABI of the contract code, it is nil if the contract doesn't publish the ABI
+func (vm *VmImpl) Abi(codeHash common.Hash) (*abi.Abi, error) {
+	vm.gasCounter.Reset(ReadGasLimit)
+	contract := vm.createContract(&env2.ReadContextImpl{Hash: codeHash})
 	if contract == nil {
 		return nil, errors.New("unknown contract")
 	}
-	vm.gasCounter.Reset(-1)
-	data, err = contract.Read(method, args...)
-	return data, err
+	return contract.Abi(), nil
 }
@@ -0,0 +1,320 @@
+package wasm
+
+type instruction struct {
+	op uint16
+	// number of values returned by block, loop and if
+	arity int
+	// index, constant, memory offset or label depth
+	imm uint64
+	// positions of the matching end and else of block, loop and if, else is 0 if missing
+	end, els int
+	// br_table label depths, the last one is the default
+	labels []uint32
+}
+
+type ctrlFrame struct {
+	op          uint16
+	results     []valueType
+	height      int
+	unreachable bool
+	// position of the instruction opened the block, -1 for the function body
+	pc int
+}
+
+// compiler validates the function body and converts it to the instruction list with resolved block boundaries
+type compiler struct {
+	module *Module
+	f      *function
+	r      *reader
+	locals []valueType
+	vals   []valueType
+	ctrls  []ctrlFrame
+	code   []instruction
+}
+
+func newCompiler(module *Module, f *function, r *reader) *compiler {
+	locals := make([]valueType, 0, len(f.typ.params)+len(f.locals))
+	locals = append(append(locals, f.typ.params...), f.locals...)
+	return &compiler{module: module, f: f, r: r, locals: locals}
+}
+
+func (c *compiler) compile() {
+	c.ctrls = append(c.ctrls, ctrlFrame{op: opBlock, results: c.f.typ.results, pc: -1})
+	for len(c.ctrls) > 0 {
+		c.next()
+	}
+	if !c.r.eof() {
+		fail("function body size mismatch")
+	}
+	c.f.code = c.code
+}
+
+func (c *compiler) next() {
+	op := uint16(c.r.byte())
+	ins := instruction{op: op}
+	switch op {
+	case opUnreachable:
+		c.markUnreachable()
+	case opNop:
+	case opBlock, opLoop, opIf:
+		results := c.blockType()
+		if op == opIf {
+			c.popVal(i32)
+		}
+		if len(c.ctrls) >= maxBlockDepth {
+			fail("too deep nesting")
+		}
+		ins.arity = len(results)
+		c.ctrls = append(c.ctrls, ctrlFrame{op: op, results: results, height: len(c.vals), pc: len(c.code)})
+	case opElse:
+		frame := c.popCtrl()
+		if frame.op != opIf {
+			fail("else without if")
+		}
+		c.code[frame.pc].els = len(c.code)
+		c.ctrls = append(c.ctrls, ctrlFrame{op: opElse, results: frame.results, height: len(c.vals), pc: frame.pc})
+	case opEnd:
+		frame := c.popCtrl()
+		if frame.op == opIf && len(frame.results) > 0 {
+			fail("if without else can not return values")
+		}
+		if frame.pc >= 0 {
+			c.code[frame.pc].end = len(c.code)
+		}
+		c.pushVals(frame.results)
+	case opBr:
+		depth := c.labelDepth()
+		c.popVals(c.labelTypes(depth))
+		c.markUnreachable()
+		ins.imm = uint64(depth)
+	case opBrIf:
+		depth := c.labelDepth()
+		c.popVal(i32)
+		types := c.labelTypes(depth)
+		c.popVals(types)
+		c.pushVals(types)
+		ins.imm = uint64(depth)
+	case opBrTable:
+		n := c.r.u32()
+		if n > maxBrTableSize {
+			fail("too many br_table labels")
+		}
+		ins.labels = make([]uint32, n+1)
+		for i := range ins.labels {
+			ins.labels[i] = c.labelDepth()
+		}
+		c.popVal(i32)
+		arity := len(c.labelTypes(ins.labels[n]))
+		for _, depth := range ins.labels {
+			types := c.labelTypes(depth)
+			if len(types) != arity {
+				fail("br_table labels have inconsistent types")
+			}
+			c.popVals(types)
+			c.pushVals(types)
+		}
+		c.popVals(c.labelTypes(ins.labels[n]))
+		c.markUnreachable()
+	case opReturn:
+		c.popVals(c.f.typ.results)
+		c.markUnreachable()
+	case opCall:
+		idx := c.r.u32()
+		if int(idx) >= len(c.module.functions) {
+			fail("unknown function %v", idx)
+		}
+		t := c.module.functions[idx].typ
+		c.popVals(t.params)
+		c.pushVals(t.results)
+		ins.imm = uint64(idx)
+	case opCallIndirect:
+		idx := c.module.typeIndex(c.r.u32())
+		if c.r.byte() != 0 || !c.module.hasTable {
+			fail("unknown table")
+		}
+		c.popVal(i32)
+		t := c.module.types[idx]
+		c.popVals(t.params)
+		c.pushVals(t.results)
+		ins.imm = uint64(idx)
+	case opDrop:
+		c.popVal(unknown)
+	case opSelect:
+		c.popVal(i32)
+		t := c.popVal(unknown)
+		c.pushVal(c.popVal(t))
+	case opLocalGet, opLocalSet, opLocalTee:
+		idx := c.r.u32()
+		if int(idx) >= len(c.locals) {
+			fail("unknown local %v", idx)
+		}
+		t := c.locals[idx]
+		switch op {
+		case opLocalGet:
+			c.pushVal(t)
+		case opLocalSet:
+			c.popVal(t)
+		case opLocalTee:
+			c.pushVal(c.popVal(t))
+		}
+		ins.imm = uint64(idx)
+	case opGlobalGet, opGlobalSet:
+		idx := c.r.u32()
+		if int(idx) >= len(c.module.globals) {
+			fail("unknown global %v", idx)
+		}
+		g := c.module.globals[idx]
+		if op == opGlobalGet {
+			c.pushVal(g.typ)
+		} else {
+			if !g.mutable {
+				fail("global %v is immutable", idx)
+			}
+			c.popVal(g.typ)
+		}
+		ins.imm = uint64(idx)
+	case opMemorySize:
+		c.requireMemory()
+		c.reserved()
+		c.pushVal(i32)
+	case opMemoryGrow:
+		c.requireMemory()
+		c.reserved()
+		c.pushVal(c.popVal(i32))
+	case opI32Const:
+		ins.imm = uint64(uint32(c.r.s32()))
+		c.pushVal(i32)
+	case opI64Const:
+		ins.imm = uint64(c.r.s64())
+		c.pushVal(i64)
+	case opPrefixMisc:
+		sub := c.r.u32()
+		if sub > 0xff {
+			fail("unsupported instruction 0xfc %v", sub)
+		}
+		ins.op = opPrefixMisc<<8 | uint16(sub)
+		switch ins.op {
+		case opMemoryCopy:
+			c.requireMemory()
+			c.reserved()
+			c.reserved()
+		case opMemoryFill:
+			c.requireMemory()
+			c.reserved()
+		default:
+			fail("unsupported instruction 0xfc %v", sub)
+		}
+		c.popVals([]valueType{i32, i32, i32})
+	default:
+		sig, ok := numericSignatures[op]
+		if !ok {
+			fail("unsupported instruction 0x%x", op)
+		}
+		if size, ok := memoryAccessSize[op]; ok {
+			c.requireMemory()
+			if align := c.r.u32(); align >= 32 || 1<<align > size {
+				fail("alignment must not be larger than natural")
+			}
+			ins.imm = uint64(c.r.u32())
+		}
+		c.popVals(sig.params)
+		c.pushVals(sig.results)
+	}
+	c.code = append(c.code, ins)
+	if len(c.vals) > c.f.maxHeight {
+		c.f.maxHeight = len(c.vals)
+	}
+}
+
+func (c *compiler) blockType() []valueType {
+	switch t := c.r.byte(); valueType(t) {
+	case emptyBlock:
+		return nil
+	case i32, i64:
+		return []valueType{valueType(t)}
+	default:
+		fail("unsupported block type 0x%x", t)
+		return nil
+	}
+}
+
+func (c *compiler) labelDepth() uint32 {
+	depth := c.r.u32()
+	if int(depth) >= len(c.ctrls) {
+		fail("unknown label %v", depth)
+	}
+	return depth
+}
+
+// labelTypes returns types of the values passed by a branch to the label, branches to a loop pass no values
+func (c *compiler) labelTypes(depth uint32) []valueType {
+	frame := c.ctrls[len(c.ctrls)-1-int(depth)]
+	if frame.op == opLoop {
+		return nil
+	}
+	return frame.results
+}
+
+func (c *compiler) requireMemory() {
+	if c.module.memory == nil {
+		fail("unknown memory")
+	}
+}
+
+func (c *compiler) reserved() {
+	if c.r.byte() != 0 {
+		fail("zero byte expected")
+	}
+}
+
+func (c *compiler) pushVal(t valueType) {
+	c.vals = append(c.vals, t)
+}
+
+func (c *compiler) pushVals(types []valueType) {
+	for _, t := range types {
+		c.pushVal(t)
+	}
+}
+
+// popVal pops the value of the expected type, the unknown type matches any type
+func (c *compiler) popVal(expected valueType) valueType {
+	frame := &c.ctrls[len(c.ctrls)-1]
+	if len(c.vals) == frame.height {
+		if frame.unreachable {
+			return expected
+		}
+		fail("type mismatch: not enough values on the stack")
+	}
+	actual := c.vals[len(c.vals)-1]
+	c.vals = c.vals[:len(c.vals)-1]
+	if actual == unknown {
+		return expected
+	}
+	if expected != unknown && actual != expected {
+		fail("type mismatch")
+	}
+	return actual
+}
+
+func (c *compiler) popVals(types []valueType) {
+	for i := len(types) - 1; i >= 0; i-- {
+		c.popVal(types[i])
+	}
+}
+
+func (c *compiler) popCtrl() ctrlFrame {
+	frame := c.ctrls[len(c.ctrls)-1]
+	c.popVals(frame.results)
+	if len(c.vals) != frame.height {
+		fail("type mismatch: values remain on the stack at the end of the block")
+	}
+	c.ctrls = c.ctrls[:len(c.ctrls)-1]
+	return frame
+}
+
+func (c *compiler) markUnreachable() {
+	frame := &c.ctrls[len(c.ctrls)-1]
+	c.vals = c.vals[:frame.height]
+	frame.unreachable = true
+}
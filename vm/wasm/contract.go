@@ -0,0 +1,97 @@
+package wasm
+
+import (
+	"context"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DeployMethod is the exported function called once when the contract is deployed, it is optional
+	DeployMethod = "deploy"
+	// TerminateMethod is the exported function called on termination,
+	// it should set the 20-byte address receiving the refund of the stake as the return data
+	TerminateMethod = "terminate"
+)
+
+// Contract runs methods of a WebAssembly module. Methods are exported functions without params and results,
+// they get args and return data through the host functions.
+type Contract struct {
+	ctx       env.CallContext
+	env       env.Env
+	gas       *env.GasCounter
+	interrupt context.Context
+	module    *Module
+}
+
+// NewContract creates a contract of the module, the execution is interrupted when the interrupt context is done,
+// it may be nil if the execution is limited by gas only
+func NewContract(ctx env.CallContext, e env.Env, gas *env.GasCounter, interrupt context.Context, module *Module) *Contract {
+	return &Contract{ctx: ctx, env: e, gas: gas, interrupt: interrupt, module: module}
+}
+
+func (c *Contract) Deploy(args ...[]byte) error {
+	if _, ok := c.module.exports[DeployMethod]; !ok {
+		return nil
+	}
+	_, err := c.run(DeployMethod, args)
+	return err
+}
+
+func (c *Contract) Call(method string, args ...[]byte) error {
+	if method == DeployMethod || method == TerminateMethod {
+		return errors.New("unknown method")
+	}
+	_, err := c.run(method, args)
+	return err
+}
+
+func (c *Contract) Read(method string, args ...[]byte) ([]byte, error) {
+	if method == DeployMethod || method == TerminateMethod {
+		return nil, errors.New("unknown method")
+	}
+	return c.run(method, args)
+}
+
+func (c *Contract) Abi() *abi.Abi {
+	return c.module.abi
+}
+
+func (c *Contract) Terminate(args ...[]byte) (common.Address, error) {
+	ret, err := c.run(TerminateMethod, args)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(ret) != common.AddressLength {
+		return common.Address{}, errors.New("terminate should return stake destination")
+	}
+	var dest common.Address
+	dest.SetBytes(ret)
+	return dest, nil
+}
+
+// run instantiates the module and calls the method. Traps are converted to errors,
+// other panics (e.g. lack of gas) are left to the caller.
+func (c *Contract) run(method string, args [][]byte) (ret []byte, err error) {
+	idx, ok := c.module.exports[method]
+	if !ok {
+		return nil, errors.New("unknown method")
+	}
+	if t := c.module.functions[idx].typ; len(t.params) > 0 || len(t.results) > 0 {
+		return nil, errors.New("unknown method")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if t, ok := r.(trap); ok {
+				ret, err = nil, t
+				return
+			}
+			panic(r)
+		}
+	}()
+	host := &hostContext{ctx: c.ctx, env: c.env, args: args}
+	newInstance(c.module, c.gas, c.interrupt, host).call(idx)
+	return host.ret, nil
+}
@@ -0,0 +1,4 @@
+package wasm
+
+// CounterModule is used by the tests of the VM running wasm contracts
+var CounterModule = counterModule
@@ -0,0 +1,292 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/vm/env"
+	"math/big"
+)
+
+const (
+	hostModule = "env"
+
+	// amounts are passed as 32-byte big-endian unsigned integers
+	amountSize   = 32
+	maxEventArgs = 16
+	maxCallArgs  = 16
+)
+
+// hostContext is the state of the contract call available to the host functions
+type hostContext struct {
+	ctx env.CallContext
+	env env.Env
+	// args of the called method
+	args [][]byte
+	// data set by the contract as the result of the method
+	ret []byte
+}
+
+type hostFunc struct {
+	typ  *funcType
+	call func(in *instance, args []uint64) uint64
+}
+
+func fn(params []valueType, results []valueType, call func(in *instance, args []uint64) uint64) *hostFunc {
+	return &hostFunc{typ: &funcType{params: params, results: results}, call: call}
+}
+
+func params(types ...valueType) []valueType {
+	return types
+}
+
+var (
+	noResult  []valueType
+	i32Result = []valueType{i32}
+	i64Result = []valueType{i64}
+)
+
+// hostFunctions are the functions of the env module available to contracts, pointers and lengths refer to the contract memory
+var hostFunctions = map[string]*hostFunc{
+	// args_count() i32 returns the number of the method args
+	"args_count": fn(params(), i32Result, func(in *instance, args []uint64) uint64 {
+		return uint64(len(in.host.args))
+	}),
+	// arg_len(index) i32 returns the length of the arg or -1 if the arg is missing
+	"arg_len": fn(params(i32), i32Result, func(in *instance, args []uint64) uint64 {
+		idx := uint32(args[0])
+		if int(idx) >= len(in.host.args) {
+			return uint64(uint32(0xffffffff))
+		}
+		return uint64(len(in.host.args[idx]))
+	}),
+	// arg_read(index, ptr) copies the arg to the memory
+	"arg_read": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		idx := uint32(args[0])
+		if int(idx) >= len(in.host.args) {
+			panic(trap("arg index out of range"))
+		}
+		in.write(args[1], in.host.args[idx])
+		return 0
+	}),
+	// set_return(ptr, len) sets the result of the method
+	"set_return": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.host.ret = in.read(args[0], args[1])
+		return 0
+	}),
+	// abort(ptr, len) terminates the execution with the error message
+	"abort": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		panic(trap(in.read(args[0], args[1])))
+	}),
+	// caller(ptr) writes the 20-byte address of the tx sender
+	"caller": fn(params(i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.write(args[0], in.host.ctx.Sender().Bytes())
+		return 0
+	}),
+	// contract_address(ptr) writes the 20-byte address of the contract
+	"contract_address": fn(params(i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.write(args[0], in.host.ctx.ContractAddr().Bytes())
+		return 0
+	}),
+	// pay_amount(ptr) writes the amount sent to the contract
+	"pay_amount": fn(params(i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.writeAmount(args[0], in.host.ctx.PayAmount())
+		return 0
+	}),
+	// balance(addrPtr, ptr) writes the balance of the address
+	"balance": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.writeAmount(args[1], in.host.env.Balance(in.readAddress(args[0])))
+		return 0
+	}),
+	// contract_stake(addrPtr, ptr) writes the stake of the contract
+	"contract_stake": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.writeAmount(args[1], in.host.env.ContractStake(in.readAddress(args[0])))
+		return 0
+	}),
+	// min_fee_per_gas(ptr) writes the current fee per gas
+	"min_fee_per_gas": fn(params(i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.writeAmount(args[0], in.host.env.MinFeePerGas())
+		return 0
+	}),
+	"block_number": fn(params(), i64Result, func(in *instance, args []uint64) uint64 {
+		return in.host.env.BlockNumber()
+	}),
+	"block_timestamp": fn(params(), i64Result, func(in *instance, args []uint64) uint64 {
+		return uint64(in.host.env.BlockTimeStamp())
+	}),
+	// block_seed(ptr) i32 writes the seed of the block and returns its length
+	"block_seed": fn(params(i32), i32Result, func(in *instance, args []uint64) uint64 {
+		seed := in.host.env.BlockSeed()
+		in.write(args[0], seed)
+		return uint64(len(seed))
+	}),
+	"epoch": fn(params(), i32Result, func(in *instance, args []uint64) uint64 {
+		return uint64(in.host.env.Epoch())
+	}),
+	"network_size": fn(params(), i32Result, func(in *instance, args []uint64) uint64 {
+		return uint64(in.host.env.NetworkSize())
+	}),
+	// identity_state(addrPtr) i32 returns the identity state of the address
+	"identity_state": fn(params(i32), i32Result, func(in *instance, args []uint64) uint64 {
+		return uint64(in.host.env.State(in.readAddress(args[0])))
+	}),
+	// set_value(keyPtr, keyLen, valuePtr, valueLen) sets the value of the contract store
+	"set_value": fn(params(i32, i32, i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.host.env.SetValue(in.host.ctx, in.read(args[0], args[1]), in.read(args[2], args[3]))
+		return 0
+	}),
+	// get_value(keyPtr, keyLen, ptr, cap) i32 copies at most cap bytes of the value to the memory,
+	// it returns the full length of the value or -1 if the value is missing
+	"get_value": fn(params(i32, i32, i32, i32), i32Result, func(in *instance, args []uint64) uint64 {
+		value := in.host.env.GetValue(in.host.ctx, in.read(args[0], args[1]))
+		return in.writeValue(args[2], args[3], value)
+	}),
+	// read_contract_data(addrPtr, keyPtr, keyLen, ptr, cap) i32 is get_value for the store of another contract
+	"read_contract_data": fn(params(i32, i32, i32, i32, i32), i32Result, func(in *instance, args []uint64) uint64 {
+		value := in.host.env.ReadContractData(in.readAddress(args[0]), in.read(args[1], args[2]))
+		return in.writeValue(args[3], args[4], value)
+	}),
+	// remove_value(keyPtr, keyLen) removes the value of the contract store
+	"remove_value": fn(params(i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		in.host.env.RemoveValue(in.host.ctx, in.read(args[0], args[1]))
+		return 0
+	}),
+	// iterate(minPtr, minLen, maxPtr, maxLen, bufPtr, bufCap, callback) iterates over the contract store in the key range,
+	// an empty bound means no bound. The key followed by the value is copied to the buffer and the callback
+	// is called by its table index with the key and value lengths, the callback returns non-zero to stop the iteration.
+	"iterate": fn(params(i32, i32, i32, i32, i32, i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		var minKey, maxKey []byte
+		if uint32(args[1]) > 0 {
+			minKey = in.read(args[0], args[1])
+		}
+		if uint32(args[3]) > 0 {
+			maxKey = in.read(args[2], args[3])
+		}
+		buf := in.slice(uint32(args[4]), uint32(args[5]))
+		callback := in.callback(uint32(args[6]))
+		in.host.env.Iterate(in.host.ctx, minKey, maxKey, func(key []byte, value []byte) bool {
+			if len(key)+len(value) > len(buf) {
+				panic(trap("iterate buffer is too small"))
+			}
+			copy(buf, key)
+			copy(buf[len(key):], value)
+			in.push(uint64(len(key)))
+			in.push(uint64(len(value)))
+			in.call(callback)
+			return uint32(in.pop()) != 0
+		})
+		return 0
+	}),
+	// send(addrPtr, amountPtr) i32 sends coins from the contract, it returns 0 on success
+	"send": fn(params(i32, i32), i32Result, func(in *instance, args []uint64) uint64 {
+		if err := in.host.env.Send(in.host.ctx, in.readAddress(args[0]), in.readAmount(args[1])); err != nil {
+			return 1
+		}
+		return 0
+	}),
+	// move_to_stake(amountPtr) i32 moves coins from the contract balance to its stake, it returns 0 on success
+	"move_to_stake": fn(params(i32), i32Result, func(in *instance, args []uint64) uint64 {
+		if err := in.host.env.MoveToStake(in.host.ctx, in.readAmount(args[0])); err != nil {
+			return 1
+		}
+		return 0
+	}),
+	// burn_all() burns the contract balance
+	"burn_all": fn(params(), noResult, func(in *instance, args []uint64) uint64 {
+		in.host.env.BurnAll(in.host.ctx)
+		return 0
+	}),
+	// event(namePtr, nameLen, argsPtr, argsCount) emits the event, args are argsCount pairs of 32-bit pointer and length
+	"event": fn(params(i32, i32, i32, i32), noResult, func(in *instance, args []uint64) uint64 {
+		name := string(in.read(args[0], args[1]))
+		if uint32(args[3]) > maxEventArgs {
+			panic(trap("too many event args"))
+		}
+		in.host.env.Event(name, in.readRefs(args[2], args[3])...)
+		return 0
+	}),
+	// call(addrPtr, methodPtr, methodLen, argsPtr, argsCount) i32 calls the method of another contract,
+	// args are passed like event args. It returns 0 on success, changes of a failed call are reverted.
+	"call": fn(params(i32, i32, i32, i32, i32), i32Result, func(in *instance, args []uint64) uint64 {
+		method := string(in.read(args[1], args[2]))
+		if uint32(args[4]) > maxCallArgs {
+			panic(trap("too many call args"))
+		}
+		if err := in.host.env.Call(in.host.ctx, in.readAddress(args[0]), method, in.readRefs(args[3], args[4])...); err != nil {
+			return 1
+		}
+		return 0
+	}),
+}
+
+// readRefs reads count memory regions referenced by pairs of 32-bit pointer and length
+func (in *instance) readRefs(ptr uint64, count uint64) [][]byte {
+	refs := in.slice(uint32(ptr), uint32(count)*8)
+	result := make([][]byte, uint32(count))
+	for i := range result {
+		p := binary.LittleEndian.Uint32(refs[i*8:])
+		length := binary.LittleEndian.Uint32(refs[i*8+4:])
+		result[i] = in.read(uint64(p), uint64(length))
+	}
+	return result
+}
+
+// read returns the copy of the memory region
+func (in *instance) read(ptr uint64, length uint64) []byte {
+	data := in.slice(uint32(ptr), uint32(length))
+	result := make([]byte, len(data))
+	copy(result, data)
+	return result
+}
+
+func (in *instance) write(ptr uint64, data []byte) {
+	copy(in.slice(uint32(ptr), uint32(len(data))), data)
+}
+
+// writeValue copies at most cap bytes of the value and returns its length or -1 if the value is nil
+func (in *instance) writeValue(ptr uint64, cap uint64, value []byte) uint64 {
+	if value == nil {
+		return uint64(uint32(0xffffffff))
+	}
+	n := len(value)
+	if uint32(cap) < uint32(n) {
+		n = int(uint32(cap))
+	}
+	in.write(ptr, value[:n])
+	return uint64(len(value))
+}
+
+func (in *instance) readAddress(ptr uint64) common.Address {
+	var addr common.Address
+	addr.SetBytes(in.read(ptr, common.AddressLength))
+	return addr
+}
+
+func (in *instance) readAmount(ptr uint64) *big.Int {
+	return new(big.Int).SetBytes(in.read(ptr, amountSize))
+}
+
+func (in *instance) writeAmount(ptr uint64, amount *big.Int) {
+	if amount == nil {
+		amount = common.Big0
+	}
+	if amount.Sign() < 0 || amount.BitLen() > amountSize*8 {
+		panic(trap("amount is out of range"))
+	}
+	var data [amountSize]byte
+	amount.FillBytes(data[:])
+	in.write(ptr, data[:])
+}
+
+// callback returns the function index by the table index, the function should have (i32, i32) -> i32 type
+func (in *instance) callback(tableIdx uint32) uint32 {
+	if tableIdx >= uint32(len(in.table)) || in.table[tableIdx] < 0 {
+		panic(trap("undefined element"))
+	}
+	idx := uint32(in.table[tableIdx])
+	if !in.module.functions[idx].typ.equal(iterateCallbackType) {
+		panic(trap("invalid callback type"))
+	}
+	return idx
+}
+
+var iterateCallbackType = &funcType{params: []valueType{i32, i32}, results: []valueType{i32}}
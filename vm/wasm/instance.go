@@ -0,0 +1,516 @@
+package wasm
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/vm/env"
+	"math/bits"
+)
+
+const (
+	maxCallDepth = 256
+	maxStackSize = 64 * 1024
+	// the interruption is checked once per the number of executed instructions
+	interruptCheckInterval = 1024
+	// copied or filled bytes charged per one gas unit
+	bytesPerGas = 32
+)
+
+// trap is the error terminating the execution of the contract
+type trap string
+
+func (t trap) Error() string {
+	return string(t)
+}
+
+type label struct {
+	height int
+	arity  int
+	cont   int
+	loop   bool
+}
+
+type instance struct {
+	module    *Module
+	gas       *env.GasCounter
+	gasTable  *config.GasTable
+	interrupt context.Context
+	steps     int
+	memory    []byte
+	globals   []uint64
+	table     []int64
+	stack     []uint64
+	depth     int
+	host      *hostContext
+}
+
+func newInstance(module *Module, gas *env.GasCounter, interrupt context.Context, host *hostContext) *instance {
+	in := &instance{
+		module:    module,
+		gas:       gas,
+		gasTable:  gas.Table(),
+		interrupt: interrupt,
+		globals:   make([]uint64, len(module.globals)),
+		table:     make([]int64, module.tableSize),
+		stack:     make([]uint64, 0, 256),
+		host:      host,
+	}
+	for i, g := range module.globals {
+		in.globals[i] = g.init
+	}
+	for i := range in.table {
+		in.table[i] = -1
+	}
+	for _, s := range module.elements {
+		for i, idx := range s.funcs {
+			in.table[int(s.offset)+i] = int64(idx)
+		}
+	}
+	if module.memory != nil {
+		in.gas.AddGas(in.gasTable.WasmMemoryPage * int(module.memory.min))
+		in.memory = make([]byte, int(module.memory.min)*pageSize)
+		for _, s := range module.data {
+			copy(in.memory[s.offset:], s.data)
+		}
+	}
+	return in
+}
+
+// call invokes the function taking the arguments from the stack and pushing the results back
+func (in *instance) call(idx uint32) {
+	f := in.module.functions[idx]
+	in.gas.AddGas(in.gasTable.WasmCall)
+	if f.host != nil {
+		params := len(f.typ.params)
+		args := make([]uint64, params)
+		copy(args, in.stack[len(in.stack)-params:])
+		in.stack = in.stack[:len(in.stack)-params]
+		result := f.host.call(in, args)
+		if len(f.typ.results) > 0 {
+			in.push(result)
+		}
+		return
+	}
+	if in.depth >= maxCallDepth {
+		panic(trap("call stack exhausted"))
+	}
+	if len(in.stack)+f.maxHeight > maxStackSize {
+		panic(trap("value stack exhausted"))
+	}
+	in.depth++
+	in.execute(f)
+	in.depth--
+}
+
+// checkInterrupt stops the execution once the context of the caller is done
+func (in *instance) checkInterrupt() {
+	in.steps++
+	if in.interrupt == nil || in.steps%interruptCheckInterval != 0 {
+		return
+	}
+	if in.interrupt.Err() != nil {
+		panic(trap("execution is interrupted"))
+	}
+}
+
+func (in *instance) execute(f *function) {
+	params := len(f.typ.params)
+	in.gas.AddGas(len(f.locals))
+	locals := make([]uint64, params+len(f.locals))
+	copy(locals, in.stack[len(in.stack)-params:])
+	in.stack = in.stack[:len(in.stack)-params]
+
+	code := f.code
+	labels := make([]label, 1, 8)
+	labels[0] = label{height: len(in.stack), arity: len(f.typ.results), cont: len(code)}
+
+	for pc := 0; pc < len(code); {
+		ins := &code[pc]
+		in.gas.AddGas(in.gasTable.WasmInstruction)
+		in.checkInterrupt()
+		switch ins.op {
+		case opUnreachable:
+			panic(trap("unreachable"))
+		case opNop:
+		case opBlock:
+			labels = append(labels, label{height: len(in.stack), arity: ins.arity, cont: ins.end + 1})
+		case opLoop:
+			labels = append(labels, label{height: len(in.stack), cont: pc + 1, loop: true})
+		case opIf:
+			if uint32(in.pop()) != 0 {
+				labels = append(labels, label{height: len(in.stack), arity: ins.arity, cont: ins.end + 1})
+			} else if ins.els > 0 {
+				labels = append(labels, label{height: len(in.stack), arity: ins.arity, cont: ins.end + 1})
+				pc = ins.els + 1
+				continue
+			} else {
+				pc = ins.end + 1
+				continue
+			}
+		case opElse:
+			// the end of the then branch, the rest of the block is skipped
+			pc = labels[len(labels)-1].cont
+			labels = labels[:len(labels)-1]
+			continue
+		case opEnd:
+			labels = labels[:len(labels)-1]
+		case opBr:
+			labels, pc = in.branch(labels, uint32(ins.imm))
+			continue
+		case opBrIf:
+			if uint32(in.pop()) != 0 {
+				labels, pc = in.branch(labels, uint32(ins.imm))
+				continue
+			}
+		case opBrTable:
+			i := uint32(in.pop())
+			if i >= uint32(len(ins.labels)-1) {
+				i = uint32(len(ins.labels) - 1)
+			}
+			labels, pc = in.branch(labels, ins.labels[i])
+			continue
+		case opReturn:
+			labels, pc = in.branch(labels, uint32(len(labels)-1))
+			continue
+		case opCall:
+			in.call(uint32(ins.imm))
+		case opCallIndirect:
+			i := uint32(in.pop())
+			if i >= uint32(len(in.table)) || in.table[i] < 0 {
+				panic(trap("undefined element"))
+			}
+			idx := uint32(in.table[i])
+			if !in.module.functions[idx].typ.equal(in.module.types[ins.imm]) {
+				panic(trap("indirect call type mismatch"))
+			}
+			in.call(idx)
+		case opDrop:
+			in.pop()
+		case opSelect:
+			cond := uint32(in.pop())
+			b, a := in.pop(), in.pop()
+			if cond != 0 {
+				in.push(a)
+			} else {
+				in.push(b)
+			}
+		case opLocalGet:
+			in.push(locals[ins.imm])
+		case opLocalSet:
+			locals[ins.imm] = in.pop()
+		case opLocalTee:
+			locals[ins.imm] = in.stack[len(in.stack)-1]
+		case opGlobalGet:
+			in.push(in.globals[ins.imm])
+		case opGlobalSet:
+			in.globals[ins.imm] = in.pop()
+		case opMemorySize:
+			in.push(uint64(len(in.memory) / pageSize))
+		case opMemoryGrow:
+			in.push(uint64(uint32(in.grow(uint32(in.pop())))))
+		case opI32Const, opI64Const:
+			in.push(ins.imm)
+		case opMemoryCopy:
+			n, src, dst := uint32(in.pop()), uint32(in.pop()), uint32(in.pop())
+			in.gas.AddGas(int(n / bytesPerGas))
+			copy(in.slice(dst, n), in.slice(src, n))
+		case opMemoryFill:
+			n, value, dst := uint32(in.pop()), byte(in.pop()), uint32(in.pop())
+			in.gas.AddGas(int(n / bytesPerGas))
+			data := in.slice(dst, n)
+			for i := range data {
+				data[i] = value
+			}
+		default:
+			if _, ok := memoryAccessSize[ins.op]; ok {
+				in.memoryAccess(ins)
+			} else {
+				in.numeric(ins.op)
+			}
+		}
+		pc++
+	}
+}
+
+// branch unwinds the stack to the label at the given depth and returns the remaining labels and the next position
+func (in *instance) branch(labels []label, depth uint32) ([]label, int) {
+	l := labels[len(labels)-1-int(depth)]
+	arity := l.arity
+	if l.loop {
+		arity = 0
+	}
+	copy(in.stack[l.height:], in.stack[len(in.stack)-arity:])
+	in.stack = in.stack[:l.height+arity]
+	if l.loop {
+		return labels[:len(labels)-int(depth)], l.cont
+	}
+	return labels[:len(labels)-1-int(depth)], l.cont
+}
+
+func (in *instance) push(v uint64) {
+	in.stack = append(in.stack, v)
+}
+
+func (in *instance) pop() uint64 {
+	v := in.stack[len(in.stack)-1]
+	in.stack = in.stack[:len(in.stack)-1]
+	return v
+}
+
+func (in *instance) grow(delta uint32) int32 {
+	pages := uint32(len(in.memory) / pageSize)
+	if uint64(pages)+uint64(delta) > uint64(in.module.memory.max) {
+		return -1
+	}
+	in.gas.AddGas(in.gasTable.WasmMemoryPage * int(delta))
+	in.memory = append(in.memory, make([]byte, int(delta)*pageSize)...)
+	return int32(pages)
+}
+
+// slice returns the memory region, it traps if the region is out of bounds
+func (in *instance) slice(offset uint32, size uint32) []byte {
+	if uint64(offset)+uint64(size) > uint64(len(in.memory)) {
+		panic(trap("out of bounds memory access"))
+	}
+	return in.memory[offset : offset+size]
+}
+
+func (in *instance) memoryAccess(ins *instruction) {
+	size := memoryAccessSize[ins.op]
+	var value uint64
+	isStore := len(numericSignatures[ins.op].results) == 0
+	if isStore {
+		value = in.pop()
+	}
+	addr := uint64(uint32(in.pop())) + ins.imm
+	if addr+uint64(size) > uint64(len(in.memory)) {
+		panic(trap("out of bounds memory access"))
+	}
+	data := in.memory[addr : addr+uint64(size)]
+	if isStore {
+		switch size {
+		case 1:
+			data[0] = byte(value)
+		case 2:
+			binary.LittleEndian.PutUint16(data, uint16(value))
+		case 4:
+			binary.LittleEndian.PutUint32(data, uint32(value))
+		case 8:
+			binary.LittleEndian.PutUint64(data, value)
+		}
+		return
+	}
+	switch ins.op {
+	case opI32Load, opI64Load32U:
+		value = uint64(binary.LittleEndian.Uint32(data))
+	case opI64Load:
+		value = binary.LittleEndian.Uint64(data)
+	case opI32Load8S:
+		value = uint64(uint32(int32(int8(data[0]))))
+	case opI32Load8U, opI64Load8U:
+		value = uint64(data[0])
+	case opI32Load16S:
+		value = uint64(uint32(int32(int16(binary.LittleEndian.Uint16(data)))))
+	case opI32Load16U, opI64Load16U:
+		value = uint64(binary.LittleEndian.Uint16(data))
+	case opI64Load8S:
+		value = uint64(int64(int8(data[0])))
+	case opI64Load16S:
+		value = uint64(int64(int16(binary.LittleEndian.Uint16(data))))
+	case opI64Load32S:
+		value = uint64(int64(int32(binary.LittleEndian.Uint32(data))))
+	}
+	in.push(value)
+}
+
+func (in *instance) numeric(op uint16) {
+	sig := numericSignatures[op]
+	var a, b uint64
+	if len(sig.params) == 2 {
+		b = in.pop()
+	}
+	a = in.pop()
+	x, y := uint32(a), uint32(b)
+	var r uint64
+	switch op {
+	case opI32Eqz:
+		r = boolToUint(x == 0)
+	case opI32Eq:
+		r = boolToUint(x == y)
+	case opI32Ne:
+		r = boolToUint(x != y)
+	case opI32LtS:
+		r = boolToUint(int32(x) < int32(y))
+	case opI32LtU:
+		r = boolToUint(x < y)
+	case opI32GtS:
+		r = boolToUint(int32(x) > int32(y))
+	case opI32GtU:
+		r = boolToUint(x > y)
+	case opI32LeS:
+		r = boolToUint(int32(x) <= int32(y))
+	case opI32LeU:
+		r = boolToUint(x <= y)
+	case opI32GeS:
+		r = boolToUint(int32(x) >= int32(y))
+	case opI32GeU:
+		r = boolToUint(x >= y)
+
+	case opI64Eqz:
+		r = boolToUint(a == 0)
+	case opI64Eq:
+		r = boolToUint(a == b)
+	case opI64Ne:
+		r = boolToUint(a != b)
+	case opI64LtS:
+		r = boolToUint(int64(a) < int64(b))
+	case opI64LtU:
+		r = boolToUint(a < b)
+	case opI64GtS:
+		r = boolToUint(int64(a) > int64(b))
+	case opI64GtU:
+		r = boolToUint(a > b)
+	case opI64LeS:
+		r = boolToUint(int64(a) <= int64(b))
+	case opI64LeU:
+		r = boolToUint(a <= b)
+	case opI64GeS:
+		r = boolToUint(int64(a) >= int64(b))
+	case opI64GeU:
+		r = boolToUint(a >= b)
+
+	case opI32Clz:
+		r = uint64(bits.LeadingZeros32(x))
+	case opI32Ctz:
+		r = uint64(bits.TrailingZeros32(x))
+	case opI32Popcnt:
+		r = uint64(bits.OnesCount32(x))
+	case opI32Add:
+		r = uint64(x + y)
+	case opI32Sub:
+		r = uint64(x - y)
+	case opI32Mul:
+		r = uint64(x * y)
+	case opI32DivS:
+		if y == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		if int32(x) == -1<<31 && int32(y) == -1 {
+			panic(trap("integer overflow"))
+		}
+		r = uint64(uint32(int32(x) / int32(y)))
+	case opI32DivU:
+		if y == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		r = uint64(x / y)
+	case opI32RemS:
+		if y == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		if int32(y) != -1 {
+			r = uint64(uint32(int32(x) % int32(y)))
+		}
+	case opI32RemU:
+		if y == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		r = uint64(x % y)
+	case opI32And:
+		r = uint64(x & y)
+	case opI32Or:
+		r = uint64(x | y)
+	case opI32Xor:
+		r = uint64(x ^ y)
+	case opI32Shl:
+		r = uint64(x << (y & 31))
+	case opI32ShrS:
+		r = uint64(uint32(int32(x) >> (y & 31)))
+	case opI32ShrU:
+		r = uint64(x >> (y & 31))
+	case opI32Rotl:
+		r = uint64(bits.RotateLeft32(x, int(y&31)))
+	case opI32Rotr:
+		r = uint64(bits.RotateLeft32(x, -int(y&31)))
+
+	case opI64Clz:
+		r = uint64(bits.LeadingZeros64(a))
+	case opI64Ctz:
+		r = uint64(bits.TrailingZeros64(a))
+	case opI64Popcnt:
+		r = uint64(bits.OnesCount64(a))
+	case opI64Add:
+		r = a + b
+	case opI64Sub:
+		r = a - b
+	case opI64Mul:
+		r = a * b
+	case opI64DivS:
+		if b == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		if int64(a) == -1<<63 && int64(b) == -1 {
+			panic(trap("integer overflow"))
+		}
+		r = uint64(int64(a) / int64(b))
+	case opI64DivU:
+		if b == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		r = a / b
+	case opI64RemS:
+		if b == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		if int64(b) != -1 {
+			r = uint64(int64(a) % int64(b))
+		}
+	case opI64RemU:
+		if b == 0 {
+			panic(trap("integer divide by zero"))
+		}
+		r = a % b
+	case opI64And:
+		r = a & b
+	case opI64Or:
+		r = a | b
+	case opI64Xor:
+		r = a ^ b
+	case opI64Shl:
+		r = a << (b & 63)
+	case opI64ShrS:
+		r = uint64(int64(a) >> (b & 63))
+	case opI64ShrU:
+		r = a >> (b & 63)
+	case opI64Rotl:
+		r = bits.RotateLeft64(a, int(b&63))
+	case opI64Rotr:
+		r = bits.RotateLeft64(a, -int(b&63))
+
+	case opI32WrapI64:
+		r = uint64(x)
+	case opI64ExtendI32S:
+		r = uint64(int64(int32(x)))
+	case opI64ExtendI32U:
+		r = uint64(x)
+	case opI32Extend8S:
+		r = uint64(uint32(int32(int8(x))))
+	case opI32Extend16S:
+		r = uint64(uint32(int32(int16(x))))
+	case opI64Extend8S:
+		r = uint64(int64(int8(a)))
+	case opI64Extend16S:
+		r = uint64(int64(int16(a)))
+	case opI64Extend32S:
+		r = uint64(int64(int32(a)))
+	}
+	in.push(r)
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
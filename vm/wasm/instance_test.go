@@ -0,0 +1,155 @@
+package wasm
+
+import (
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// run executes the function "run" of the module returning i64 with unlimited gas and returns the result,
+// the used gas and the trap
+func run(t *testing.T, code []byte) (result uint64, usedGas int, err error) {
+	m, err := Parse(code)
+	require.NoError(t, err)
+	gas := new(env.GasCounter)
+	gas.Reset(-1)
+	defer func() {
+		if r := recover(); r != nil {
+			result, usedGas, err = 0, gas.UsedGas, r.(trap)
+		}
+	}()
+	in := newInstance(m, gas, nil, &hostContext{})
+	in.call(m.exports["run"])
+	return in.pop(), gas.UsedGas, nil
+}
+
+func runI64(t *testing.T, sections [][]byte, code ...[]byte) uint64 {
+	result, _, err := run(t, funcModule([]byte{byte(i64)}, sections, []byte{0}, code...))
+	require.NoError(t, err)
+	return result
+}
+
+func runTrap(t *testing.T, sections [][]byte, code ...[]byte) error {
+	_, _, err := run(t, funcModule([]byte{byte(i64)}, sections, []byte{0}, code...))
+	require.Error(t, err)
+	return err
+}
+
+func TestInstance_ControlFlow(t *testing.T) {
+	brTable := func(idx uint32) uint64 {
+		return runI64(t, nil,
+			[]byte{opBlock, emptyBlock, opBlock, emptyBlock, opBlock, emptyBlock},
+			i32Const(idx), []byte{opBrTable, 2, 0, 1, 2},
+			[]byte{opEnd, opI64Const, 10, opReturn},
+			[]byte{opEnd, opI64Const, 11, opReturn},
+			[]byte{opEnd, opI64Const, 12},
+		)
+	}
+	require.Equal(t, uint64(10), brTable(0))
+	require.Equal(t, uint64(11), brTable(1))
+	require.Equal(t, uint64(12), brTable(2))
+	// the default label is taken for out of range indexes
+	require.Equal(t, uint64(12), brTable(40))
+
+	ifElse := func(cond uint32) uint64 {
+		return runI64(t, nil, i32Const(cond), []byte{opIf, byte(i64), opI64Const, 1, opElse, opI64Const, 2, opEnd})
+	}
+	require.Equal(t, uint64(1), ifElse(1))
+	require.Equal(t, uint64(2), ifElse(0))
+
+	// if without else is skipped
+	require.Equal(t, uint64(5), runI64(t, nil, i32Const(0), []byte{opIf, emptyBlock, opUnreachable, opEnd, opI64Const, 5}))
+
+	// the branch keeps the result of the block and drops the rest of its values
+	require.Equal(t, uint64(8), runI64(t, nil, []byte{opBlock, byte(i64), opI64Const, 7, opI64Const, 8, opBr, 0, opEnd}))
+
+	// return leaves nested blocks and loops
+	require.Equal(t, uint64(42), runI64(t, nil,
+		[]byte{opBlock, emptyBlock, opBlock, emptyBlock, opLoop, emptyBlock, opI64Const, 42, opReturn, opEnd, opEnd, opEnd},
+		[]byte{opI64Const, 0},
+	))
+
+	// br_if to the outer block from the nested loop: sum of 1..10 computed with the local counter
+	sum, _, err := run(t, funcModule([]byte{byte(i64)}, nil, concat([]byte{1}, leb(2), []byte{byte(i64)}),
+		[]byte{opI64Const, 10, opLocalSet, 0},
+		[]byte{opBlock, emptyBlock, opLoop, emptyBlock},
+		[]byte{opBlock, emptyBlock, opLocalGet, 0, opI64Eqz, opBrIf, 2, opEnd},
+		[]byte{opLocalGet, 1, opLocalGet, 0, opI64Add, opLocalSet, 1},
+		[]byte{opLocalGet, 0, opI64Const, 1, opI64Sub, opLocalSet, 0},
+		[]byte{opBr, 0, opEnd, opEnd},
+		[]byte{opLocalGet, 1},
+	))
+	require.NoError(t, err)
+	require.Equal(t, uint64(55), sum)
+}
+
+func TestInstance_Memory(t *testing.T) {
+	memory := [][]byte{section(sectionMemory, []byte{1, 1, 2})}
+
+	require.Equal(t, uint64(0x123456789), runI64(t, memory,
+		i32Const(8), []byte{opI64Const, 0x89, 0xcf, 0x95, 0x9a, 0x12, opI64Store, 3, 0},
+		i32Const(0), []byte{opI64Load, 3, 8},
+	))
+	// the last bytes of the memory are accessible
+	require.Equal(t, uint64(0), runI64(t, memory, []byte{opI32Const, 0xf8, 0xff, 0x03, opI64Load, 3, 0}))
+
+	require.EqualError(t, runTrap(t, memory, []byte{opI32Const, 0xf9, 0xff, 0x03, opI64Load, 3, 0}), "out of bounds memory access")
+	require.EqualError(t, runTrap(t, memory, i32Const(0), []byte{opI64Load, 3, 0x80, 0x80, 0x04}), "out of bounds memory access")
+	// the address and the offset don't overflow
+	require.EqualError(t, runTrap(t, memory, []byte{opI32Const, 0x7f, opI64Load, 3, 1}), "out of bounds memory access")
+	require.EqualError(t, runTrap(t, memory,
+		[]byte{opI32Const, 0xfd, 0xff, 0x03, opI32Const, 1, opI32Store, 2, 0, opI64Const, 0},
+	), "out of bounds memory access")
+	require.EqualError(t, runTrap(t, memory,
+		i32Const(0), i32Const(0), []byte{opI32Const, 0x81, 0x80, 0x04, opPrefixMisc, 11, 0, opI64Const, 0},
+	), "out of bounds memory access")
+
+	grow := []byte{opMemoryGrow, 0}
+	require.Equal(t, uint64(1), runI64(t, memory, i32Const(1), grow, []byte{opI64ExtendI32S}))
+	// the grown memory is accessible
+	require.Equal(t, uint64(2), runI64(t, memory,
+		i32Const(1), grow, []byte{opDrop},
+		[]byte{opI32Const, 0xf8, 0xff, 0x07, opI64Const, 2, opI64Store, 3, 0},
+		[]byte{opI32Const, 0xf8, 0xff, 0x07, opI64Load, 3, 0},
+	))
+	// the memory can't grow over the max
+	require.Equal(t, uint64(1<<64-1), runI64(t, memory, i32Const(2), grow, []byte{opI64ExtendI32S}))
+	require.Equal(t, uint64(2), runI64(t, memory,
+		i32Const(1), grow, []byte{opDrop}, i32Const(1), grow, []byte{opDrop},
+		[]byte{opMemorySize, 0, opI64ExtendI32U},
+	))
+	// the max is MaxMemoryPages if it is not set, so 127 pages can be added to the first one and 128 pages can not
+	unlimited := [][]byte{section(sectionMemory, []byte{0, 1})}
+	require.Equal(t, uint64(1), runI64(t, unlimited, []byte{opI32Const, 0xff, 0x00}, grow, []byte{opI64ExtendI32S}))
+	require.Equal(t, uint64(1<<64-1), runI64(t, unlimited, []byte{opI32Const, 0x80, 0x01}, grow, []byte{opI64ExtendI32S}))
+
+	// the grown pages are charged
+	_, gas, err := run(t, funcModule([]byte{byte(i64)}, memory, []byte{0}, i32Const(0), grow, []byte{opI64ExtendI32S}))
+	require.NoError(t, err)
+	_, grownGas, err := run(t, funcModule([]byte{byte(i64)}, memory, []byte{0}, i32Const(1), grow, []byte{opI64ExtendI32S}))
+	require.NoError(t, err)
+	require.Equal(t, new(env.GasCounter).Table().WasmMemoryPage, grownGas-gas)
+}
+
+func TestInstance_Traps(t *testing.T) {
+	require.EqualError(t, runTrap(t, nil, []byte{opUnreachable}), "unreachable")
+	require.EqualError(t, runTrap(t, nil,
+		[]byte{opI32Const, 0x80, 0x80, 0x80, 0x80, 0x78, opI32Const, 0x7f, opI32DivS, opDrop, opI64Const, 0},
+	), "integer overflow")
+
+	// endless recursion exhausts the call stack
+	require.EqualError(t, runTrap(t, nil, call(0)), "call stack exhausted")
+
+	// recursion of the function keeping many values on the stack exhausts the value stack before the call stack
+	values := make([]byte, 0, 600)
+	drops := make([]byte, 0, 300)
+	for i := 0; i < 300; i++ {
+		values = append(values, opI64Const, 0)
+		drops = append(drops, opDrop)
+	}
+	require.EqualError(t, runTrap(t, nil, values, call(0), drops), "value stack exhausted")
+
+	table := [][]byte{section(sectionTable, []byte{funcRefType, 0, 1})}
+	require.EqualError(t, runTrap(t, table, i32Const(0), []byte{opCallIndirect, 0, 0}), "undefined element")
+	require.EqualError(t, runTrap(t, table, i32Const(1), []byte{opCallIndirect, 0, 0}), "undefined element")
+}
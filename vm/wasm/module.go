@@ -0,0 +1,569 @@
+package wasm
+
+import (
+	"bytes"
+	"encoding/json"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/vm/abi"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const (
+	// MaxCodeSize is the max size of a contract module in bytes
+	MaxCodeSize = 64 * 1024
+	// MaxMemoryPages is the max size of the contract memory in 64KiB pages
+	MaxMemoryPages = 128
+	// AbiSection is the name of the custom section containing the JSON ABI of the contract
+	AbiSection = "abi"
+
+	pageSize        = 64 * 1024
+	maxTableSize    = 64 * 1024
+	maxFunctionSize = 64 * 1024
+	maxLocals       = 4096
+	maxGlobals      = 1024
+	maxBlockDepth   = 1024
+	maxBrTableSize  = 64 * 1024
+	moduleCacheSize = 256
+
+	magic   = "\x00asm"
+	version = "\x01\x00\x00\x00"
+)
+
+const (
+	sectionCustom   = 0
+	sectionType     = 1
+	sectionImport   = 2
+	sectionFunction = 3
+	sectionTable    = 4
+	sectionMemory   = 5
+	sectionGlobal   = 6
+	sectionExport   = 7
+	sectionStart    = 8
+	sectionElement  = 9
+	sectionCode     = 10
+	sectionData     = 11
+)
+
+const (
+	externalFunction = 0x0
+	externalTable    = 0x1
+	externalMemory   = 0x2
+	externalGlobal   = 0x3
+
+	funcRefType = 0x70
+	funcTypeTag = 0x60
+	emptyBlock  = 0x40
+)
+
+type valueType byte
+
+const (
+	// unknown stands for any type of an unreachable code during validation
+	unknown valueType = 0
+	i32     valueType = 0x7f
+	i64     valueType = 0x7e
+)
+
+type funcType struct {
+	params  []valueType
+	results []valueType
+}
+
+func (t *funcType) equal(other *funcType) bool {
+	return bytes.Equal(valueTypesBytes(t.params), valueTypesBytes(other.params)) &&
+		bytes.Equal(valueTypesBytes(t.results), valueTypesBytes(other.results))
+}
+
+func valueTypesBytes(types []valueType) []byte {
+	result := make([]byte, len(types))
+	for i, t := range types {
+		result[i] = byte(t)
+	}
+	return result
+}
+
+type function struct {
+	typ  *funcType
+	host *hostFunc
+	// locals declared by the function body, params are not included
+	locals    []valueType
+	code      []instruction
+	maxHeight int
+}
+
+type global struct {
+	typ     valueType
+	mutable bool
+	init    uint64
+}
+
+type segment struct {
+	offset uint32
+	data   []byte
+	funcs  []uint32
+}
+
+// Module is a decoded and validated WebAssembly module. Only integer instructions of the MVP with sign extension
+// and bulk memory copy/fill are supported, so the execution is deterministic.
+type Module struct {
+	types     []*funcType
+	functions []*function
+	imports   int
+	tableSize uint32
+	hasTable  bool
+	memory    *limits
+	globals   []global
+	exports   map[string]uint32
+	elements  []segment
+	data      []segment
+	abi       *abi.Abi
+}
+
+type limits struct {
+	min uint32
+	max uint32
+}
+
+// Parse decodes and validates the module
+func Parse(code []byte) (module *Module, err error) {
+	if len(code) > MaxCodeSize {
+		return nil, errors.Errorf("code size exceeds %v bytes", MaxCodeSize)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(decodeError); ok {
+				module, err = nil, e
+				return
+			}
+			panic(r)
+		}
+	}()
+	module = &Module{exports: make(map[string]uint32)}
+	module.decode(&reader{data: code})
+	return module, nil
+}
+
+// modules are read-only once they are parsed, so they are shared by instances of the contracts with the same code
+var moduleCache, _ = lru.New(moduleCacheSize)
+
+// ParseCached returns the cached module of the code with the hash or parses the code and caches the module
+func ParseCached(codeHash common.Hash, code []byte) (*Module, error) {
+	if module, ok := moduleCache.Get(codeHash); ok {
+		return module.(*Module), nil
+	}
+	module, err := Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	moduleCache.Add(codeHash, module)
+	return module, nil
+}
+
+// Validate checks whether the code is a valid contract module
+func Validate(code []byte) error {
+	_, err := Parse(code)
+	return err
+}
+
+type decodeError struct {
+	error
+}
+
+func fail(format string, args ...interface{}) {
+	panic(decodeError{errors.Errorf(format, args...)})
+}
+
+func (m *Module) decode(r *reader) {
+	if string(r.bytes(4)) != magic {
+		fail("invalid magic number")
+	}
+	if string(r.bytes(4)) != version {
+		fail("unsupported version")
+	}
+	var funcTypes []uint32
+	lastSection := byte(0)
+	for !r.eof() {
+		id := r.byte()
+		section := &reader{data: r.bytes(int(r.u32()))}
+		if id != sectionCustom {
+			if id <= lastSection {
+				fail("unexpected section %v", id)
+			}
+			lastSection = id
+		}
+		switch id {
+		case sectionCustom:
+			m.decodeCustom(section)
+			continue
+		case sectionType:
+			m.decodeTypes(section)
+		case sectionImport:
+			m.decodeImports(section)
+		case sectionFunction:
+			for i, n := uint32(0), section.u32(); i < n; i++ {
+				funcTypes = append(funcTypes, m.typeIndex(section.u32()))
+			}
+		case sectionTable:
+			m.decodeTable(section)
+		case sectionMemory:
+			m.decodeMemory(section)
+		case sectionGlobal:
+			m.decodeGlobals(section)
+		case sectionExport:
+			m.decodeExports(section, len(funcTypes))
+		case sectionStart:
+			fail("start function is not supported")
+		case sectionElement:
+			m.decodeElements(section, len(funcTypes))
+		case sectionCode:
+			m.decodeCode(section, funcTypes)
+		case sectionData:
+			m.decodeData(section)
+		default:
+			fail("unsupported section %v", id)
+		}
+		if !section.eof() {
+			fail("section %v size mismatch", id)
+		}
+	}
+	if len(m.functions)-m.imports != len(funcTypes) {
+		fail("function and code section have inconsistent lengths")
+	}
+}
+
+// decodeCustom reads the ABI section, other custom sections are ignored
+func (m *Module) decodeCustom(r *reader) {
+	if r.name() != AbiSection {
+		return
+	}
+	if m.abi != nil {
+		fail("duplicate abi section")
+	}
+	a := new(abi.Abi)
+	if err := json.Unmarshal(r.bytes(len(r.data)-r.pos), a); err != nil {
+		fail("invalid abi: %v", err)
+	}
+	if err := a.Validate(); err != nil {
+		fail("invalid abi: %v", err)
+	}
+	m.abi = a
+}
+
+// Abi returns the ABI of the module or nil if the module has no ABI section
+func (m *Module) Abi() *abi.Abi {
+	return m.abi
+}
+
+func (m *Module) typeIndex(idx uint32) uint32 {
+	if int(idx) >= len(m.types) {
+		fail("unknown type %v", idx)
+	}
+	return idx
+}
+
+func (m *Module) decodeTypes(r *reader) {
+	for i, n := uint32(0), r.u32(); i < n; i++ {
+		if r.byte() != funcTypeTag {
+			fail("invalid function type")
+		}
+		t := &funcType{}
+		for j, cnt := uint32(0), r.u32(); j < cnt; j++ {
+			t.params = append(t.params, r.valueType())
+		}
+		for j, cnt := uint32(0), r.u32(); j < cnt; j++ {
+			t.results = append(t.results, r.valueType())
+		}
+		if len(t.results) > 1 {
+			fail("multiple results are not supported")
+		}
+		m.types = append(m.types, t)
+	}
+}
+
+func (m *Module) decodeImports(r *reader) {
+	for i, n := uint32(0), r.u32(); i < n; i++ {
+		module, name := r.name(), r.name()
+		if r.byte() != externalFunction {
+			fail("only function imports are supported")
+		}
+		t := m.types[m.typeIndex(r.u32())]
+		host, ok := hostFunctions[name]
+		if module != hostModule || !ok {
+			fail("unknown import %v.%v", module, name)
+		}
+		if !host.typ.equal(t) {
+			fail("invalid signature of import %v.%v", module, name)
+		}
+		m.functions = append(m.functions, &function{typ: t, host: host})
+		m.imports++
+	}
+}
+
+func (m *Module) decodeTable(r *reader) {
+	if n := r.u32(); n > 1 {
+		fail("multiple tables are not supported")
+	} else if n == 0 {
+		return
+	}
+	if r.byte() != funcRefType {
+		fail("invalid table element type")
+	}
+	l := r.limits()
+	if l.min > maxTableSize {
+		fail("table size exceeds %v", maxTableSize)
+	}
+	m.tableSize = l.min
+	m.hasTable = true
+}
+
+func (m *Module) decodeMemory(r *reader) {
+	if n := r.u32(); n > 1 {
+		fail("multiple memories are not supported")
+	} else if n == 0 {
+		return
+	}
+	l := r.limits()
+	if l.min > MaxMemoryPages {
+		fail("memory size exceeds %v pages", MaxMemoryPages)
+	}
+	if l.max > MaxMemoryPages {
+		l.max = MaxMemoryPages
+	}
+	m.memory = &l
+}
+
+func (m *Module) decodeGlobals(r *reader) {
+	n := r.u32()
+	if n > maxGlobals {
+		fail("too many globals")
+	}
+	for i := uint32(0); i < n; i++ {
+		g := global{typ: r.valueType()}
+		switch r.byte() {
+		case 0:
+		case 1:
+			g.mutable = true
+		default:
+			fail("invalid global mutability")
+		}
+		g.init = r.constExpr(g.typ)
+		m.globals = append(m.globals, g)
+	}
+}
+
+func (m *Module) decodeExports(r *reader, declared int) {
+	for i, n := uint32(0), r.u32(); i < n; i++ {
+		name := r.name()
+		kind, idx := r.byte(), r.u32()
+		if _, ok := m.exports[name]; ok {
+			fail("duplicated export %v", name)
+		}
+		switch kind {
+		case externalFunction:
+			if int(idx) >= m.imports+declared {
+				fail("unknown function %v", idx)
+			}
+			// exported functions are the methods of the contract
+			if strings.HasPrefix(name, abi.ReservedPrefix) {
+				fail("reserved export name %v", name)
+			}
+			m.exports[name] = idx
+		case externalTable, externalMemory, externalGlobal:
+		default:
+			fail("invalid export kind")
+		}
+	}
+}
+
+func (m *Module) decodeElements(r *reader, declared int) {
+	for i, n := uint32(0), r.u32(); i < n; i++ {
+		if r.u32() != 0 {
+			fail("only active element segments are supported")
+		}
+		if !m.hasTable {
+			fail("unknown table")
+		}
+		s := segment{offset: uint32(r.constExpr(i32))}
+		for j, cnt := uint32(0), r.u32(); j < cnt; j++ {
+			idx := r.u32()
+			if int(idx) >= m.imports+declared {
+				fail("unknown function %v", idx)
+			}
+			s.funcs = append(s.funcs, idx)
+		}
+		if uint64(s.offset)+uint64(len(s.funcs)) > uint64(m.tableSize) {
+			fail("element segment does not fit the table")
+		}
+		m.elements = append(m.elements, s)
+	}
+}
+
+func (m *Module) decodeData(r *reader) {
+	for i, n := uint32(0), r.u32(); i < n; i++ {
+		if r.u32() != 0 {
+			fail("only active data segments are supported")
+		}
+		if m.memory == nil {
+			fail("unknown memory")
+		}
+		s := segment{offset: uint32(r.constExpr(i32))}
+		s.data = r.bytes(int(r.u32()))
+		if uint64(s.offset)+uint64(len(s.data)) > uint64(m.memory.min)*pageSize {
+			fail("data segment does not fit the memory")
+		}
+		m.data = append(m.data, s)
+	}
+}
+
+func (m *Module) decodeCode(r *reader, funcTypes []uint32) {
+	n := r.u32()
+	if int(n) != len(funcTypes) {
+		fail("function and code section have inconsistent lengths")
+	}
+	// all functions should be known before bodies are validated since they may call each other
+	for _, t := range funcTypes {
+		m.functions = append(m.functions, &function{typ: m.types[t]})
+	}
+	for i := uint32(0); i < n; i++ {
+		size := r.u32()
+		if size > maxFunctionSize {
+			fail("function size exceeds %v bytes", maxFunctionSize)
+		}
+		body := &reader{data: r.bytes(int(size))}
+		f := m.functions[m.imports+int(i)]
+		total := uint64(len(f.typ.params))
+		for j, cnt := uint32(0), body.u32(); j < cnt; j++ {
+			num := body.u32()
+			total += uint64(num)
+			if total > maxLocals {
+				fail("too many locals")
+			}
+			t := body.valueType()
+			for k := uint32(0); k < num; k++ {
+				f.locals = append(f.locals, t)
+			}
+		}
+		newCompiler(m, f, body).compile()
+	}
+}
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) eof() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() byte {
+	if r.eof() {
+		fail("unexpected end")
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *reader) bytes(n int) []byte {
+	if n < 0 || len(r.data)-r.pos < n {
+		fail("unexpected end")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *reader) valueType() valueType {
+	switch t := valueType(r.byte()); t {
+	case i32, i64:
+		return t
+	default:
+		fail("unsupported value type 0x%x", byte(t))
+		return unknown
+	}
+}
+
+func (r *reader) limits() limits {
+	switch r.byte() {
+	case 0:
+		return limits{min: r.u32(), max: MaxMemoryPages}
+	case 1:
+		l := limits{min: r.u32(), max: r.u32()}
+		if l.max < l.min {
+			fail("invalid limits")
+		}
+		return l
+	default:
+		fail("invalid limits")
+		return limits{}
+	}
+}
+
+// constExpr reads an initializer expression, only constants are supported since globals can not be imported
+func (r *reader) constExpr(t valueType) uint64 {
+	var value uint64
+	switch op := r.byte(); {
+	case op == opI32Const && t == i32:
+		value = uint64(uint32(r.s32()))
+	case op == opI64Const && t == i64:
+		value = uint64(r.s64())
+	default:
+		fail("unsupported initializer expression")
+	}
+	if r.byte() != opEnd {
+		fail("unsupported initializer expression")
+	}
+	return value
+}
+
+func (r *reader) u32() uint32 {
+	var result uint32
+	for shift := uint(0); ; shift += 7 {
+		b := r.byte()
+		if shift == 28 && b&0xf0 != 0 {
+			fail("integer representation too long")
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result
+		}
+	}
+}
+
+func (r *reader) s32() int32 {
+	return int32(r.signed(32))
+}
+
+func (r *reader) s64() int64 {
+	return r.signed(64)
+}
+
+func (r *reader) signed(size uint) int64 {
+	var result int64
+	var shift uint
+	for {
+		b := r.byte()
+		if shift+7 >= size {
+			// the unused bits of the last byte should be the sign extension of the value
+			rest := int8(b<<1) >> (size - shift)
+			if b&0x80 != 0 || rest != 0 && rest != -1 {
+				fail("integer representation too long")
+			}
+		}
+		result |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				result |= -1 << shift
+			}
+			return result
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package wasm
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParse_InvalidModules(t *testing.T) {
+	i64Result := []byte{byte(i64)}
+	memory := section(sectionMemory, []byte{0, 1})
+	cases := []struct {
+		name string
+		code []byte
+		err  string
+	}{
+		{"bad magic", []byte("\x00asn\x01\x00\x00\x00"), "invalid magic number"},
+		{"bad version", []byte("\x00asm\x02\x00\x00\x00"), "unsupported version"},
+		{"truncated header", []byte("\x00as"), "unexpected end"},
+		{"truncated section", concat([]byte(magic), []byte(version), []byte{sectionType, 5, 1, funcTypeTag}), "unexpected end"},
+		{"section size mismatch", module(concat([]byte{sectionType, 5}, vec(typeEntry(nil, nil)), []byte{0})), "section 1 size mismatch"},
+		{"duplicated section", module(section(sectionType), section(sectionType)), "unexpected section 1"},
+		{"unsupported section", module([]byte{12, 0}), "unsupported section 12"},
+		{"start section", module(section(sectionType, typeEntry(nil, nil)), []byte{sectionStart, 1, 0}), "start function is not supported"},
+		{"too long leb128", concat([]byte(magic), []byte(version), []byte{sectionType, 0x80, 0x80, 0x80, 0x80, 0x10}), "integer representation too long"},
+		{"unterminated leb128", concat([]byte(magic), []byte(version), []byte{sectionType, 0x80, 0x80}), "unexpected end"},
+		{"too long signed leb128", funcModule(nil, nil, []byte{0}, []byte{opI32Const, 0x80, 0x80, 0x80, 0x80, 0x10, opDrop}), "integer representation too long"},
+		{"unsupported value type", module(section(sectionType, typeEntry([]byte{0x7d}, nil))), "unsupported value type 0x7d"},
+		{"multiple results", module(section(sectionType, typeEntry(nil, []byte{byte(i32), byte(i32)}))), "multiple results are not supported"},
+		{"unknown type", module(section(sectionType, typeEntry(nil, nil)), section(sectionFunction, []byte{1})), "unknown type 1"},
+		{"missing code", module(section(sectionType, typeEntry(nil, nil)), section(sectionFunction, []byte{0})), "function and code section have inconsistent lengths"},
+		{"unknown import", module(section(sectionType, typeEntry(nil, nil)), section(sectionImport, concat(str("env"), str("foo"), []byte{externalFunction, 0}))), "unknown import env.foo"},
+		{"import signature", module(section(sectionType, typeEntry(nil, nil)), section(sectionImport, concat(str("env"), str("abort"), []byte{externalFunction, 0}))), "invalid signature of import env.abort"},
+		{"unknown exported function", module(section(sectionType, typeEntry(nil, nil)), section(sectionExport, concat(str("run"), []byte{externalFunction, 5}))), "unknown function 5"},
+		{"reserved export name", module(section(sectionType, typeEntry(nil, nil)), section(sectionFunction, []byte{0}), section(sectionExport, concat(str("@upgrade"), []byte{externalFunction, 0}))), "reserved export name @upgrade"},
+		{"memory too large", module(section(sectionMemory, concat([]byte{0}, leb(MaxMemoryPages+1)))), "memory size exceeds 128 pages"},
+		{"invalid limits", module(section(sectionMemory, []byte{1, 2, 1})), "invalid limits"},
+		{"data out of memory", module(memory, section(sectionData, concat([]byte{0}, []byte{opI32Const, 0xff, 0xff, 0x03, opEnd}, str("ab")))), "data segment does not fit the memory"},
+		{"unknown function", funcModule(i64Result, nil, []byte{0}, call(3)), "unknown function 3"},
+		{"unknown local", funcModule(i64Result, nil, []byte{0}, []byte{opLocalGet, 2}), "unknown local 2"},
+		{"unknown global", funcModule(i64Result, nil, []byte{0}, []byte{opGlobalGet, 0}), "unknown global 0"},
+		{"immutable global", funcModule(nil, [][]byte{section(sectionGlobal, []byte{byte(i64), 0, opI64Const, 1, opEnd})}, []byte{0}, []byte{opI64Const, 1, opGlobalSet, 0}), "global 0 is immutable"},
+		{"unknown label", funcModule(nil, nil, []byte{0}, []byte{opBr, 1}), "unknown label 1"},
+		{"unknown memory", funcModule(i64Result, nil, []byte{0}, i32Const(0), []byte{opI64Load, 3, 0}), "unknown memory"},
+		{"unknown table", funcModule(nil, nil, []byte{0}, i32Const(0), []byte{opCallIndirect, 0, 0}), "unknown table"},
+		{"alignment", funcModule(i64Result, [][]byte{memory}, []byte{0}, i32Const(0), []byte{opI64Load, 4, 0}), "alignment must not be larger than natural"},
+		{"unsupported instruction", funcModule(nil, nil, []byte{0}, []byte{0x92}), "unsupported instruction 0x92"},
+		{"result type mismatch", funcModule(i64Result, nil, []byte{0}, i32Const(1)), "type mismatch"},
+		{"operand type mismatch", funcModule(i64Result, nil, []byte{0}, i32Const(1), []byte{opI64Const, 1, opI64Add}), "type mismatch"},
+		{"missing operand", funcModule(nil, nil, []byte{0}, i32Const(1), []byte{opI32Add, opDrop}), "type mismatch: not enough values on the stack"},
+		{"remaining values", funcModule(i64Result, nil, []byte{0}, []byte{opI64Const, 1, opI64Const, 2}), "type mismatch: values remain on the stack at the end of the block"},
+		{"if without else", funcModule(i64Result, nil, []byte{0}, i32Const(1), []byte{opIf, byte(i64), opI64Const, 1, opEnd}), "if without else can not return values"},
+		{"else without if", funcModule(nil, nil, []byte{0}, []byte{opBlock, emptyBlock, opElse, opEnd}), "else without if"},
+		{"br_table types", funcModule(nil, nil, []byte{0}, []byte{opBlock, emptyBlock, opBlock, byte(i64), opI64Const, 1}, i32Const(0), []byte{opBrTable, 1, 0, 1, opEnd, opDrop, opEnd}), "br_table labels have inconsistent types"},
+		{"body size mismatch", module(section(sectionType, typeEntry(nil, nil)), section(sectionFunction, []byte{0}), section(sectionCode, []byte{3, 0, opEnd, opNop})), "function body size mismatch"},
+	}
+	for _, c := range cases {
+		_, err := Parse(c.code)
+		require.Error(t, err, c.name)
+		require.Equal(t, c.err, err.Error(), c.name)
+	}
+
+	_, err := Parse(make([]byte, MaxCodeSize+1))
+	require.EqualError(t, err, "code size exceeds 65536 bytes")
+}
+
+func TestParse_ValidModule(t *testing.T) {
+	m, err := Parse(counterModule())
+	require.NoError(t, err)
+	require.Equal(t, 5, m.imports)
+	require.Len(t, m.functions, 11)
+	require.Equal(t, uint32(fnInc), m.exports["inc"])
+	require.Nil(t, m.Abi())
+	require.NoError(t, Validate(counterModule()))
+}
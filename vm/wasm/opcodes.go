@@ -0,0 +1,188 @@
+package wasm
+
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+
+	opDrop   = 0x1a
+	opSelect = 0x1b
+
+	opLocalGet  = 0x20
+	opLocalSet  = 0x21
+	opLocalTee  = 0x22
+	opGlobalGet = 0x23
+	opGlobalSet = 0x24
+
+	opI32Load    = 0x28
+	opI64Load    = 0x29
+	opI32Load8S  = 0x2c
+	opI32Load8U  = 0x2d
+	opI32Load16S = 0x2e
+	opI32Load16U = 0x2f
+	opI64Load8S  = 0x30
+	opI64Load8U  = 0x31
+	opI64Load16S = 0x32
+	opI64Load16U = 0x33
+	opI64Load32S = 0x34
+	opI64Load32U = 0x35
+	opI32Store   = 0x36
+	opI64Store   = 0x37
+	opI32Store8  = 0x3a
+	opI32Store16 = 0x3b
+	opI64Store8  = 0x3c
+	opI64Store16 = 0x3d
+	opI64Store32 = 0x3e
+	opMemorySize = 0x3f
+	opMemoryGrow = 0x40
+
+	opI32Const = 0x41
+	opI64Const = 0x42
+
+	opI32Eqz = 0x45
+	opI32Eq  = 0x46
+	opI32Ne  = 0x47
+	opI32LtS = 0x48
+	opI32LtU = 0x49
+	opI32GtS = 0x4a
+	opI32GtU = 0x4b
+	opI32LeS = 0x4c
+	opI32LeU = 0x4d
+	opI32GeS = 0x4e
+	opI32GeU = 0x4f
+
+	opI64Eqz = 0x50
+	opI64Eq  = 0x51
+	opI64Ne  = 0x52
+	opI64LtS = 0x53
+	opI64LtU = 0x54
+	opI64GtS = 0x55
+	opI64GtU = 0x56
+	opI64LeS = 0x57
+	opI64LeU = 0x58
+	opI64GeS = 0x59
+	opI64GeU = 0x5a
+
+	opI32Clz    = 0x67
+	opI32Ctz    = 0x68
+	opI32Popcnt = 0x69
+	opI32Add    = 0x6a
+	opI32Sub    = 0x6b
+	opI32Mul    = 0x6c
+	opI32DivS   = 0x6d
+	opI32DivU   = 0x6e
+	opI32RemS   = 0x6f
+	opI32RemU   = 0x70
+	opI32And    = 0x71
+	opI32Or     = 0x72
+	opI32Xor    = 0x73
+	opI32Shl    = 0x74
+	opI32ShrS   = 0x75
+	opI32ShrU   = 0x76
+	opI32Rotl   = 0x77
+	opI32Rotr   = 0x78
+
+	opI64Clz    = 0x79
+	opI64Ctz    = 0x7a
+	opI64Popcnt = 0x7b
+	opI64Add    = 0x7c
+	opI64Sub    = 0x7d
+	opI64Mul    = 0x7e
+	opI64DivS   = 0x7f
+	opI64DivU   = 0x80
+	opI64RemS   = 0x81
+	opI64RemU   = 0x82
+	opI64And    = 0x83
+	opI64Or     = 0x84
+	opI64Xor    = 0x85
+	opI64Shl    = 0x86
+	opI64ShrS   = 0x87
+	opI64ShrU   = 0x88
+	opI64Rotl   = 0x89
+	opI64Rotr   = 0x8a
+
+	opI32WrapI64    = 0xa7
+	opI64ExtendI32S = 0xac
+	opI64ExtendI32U = 0xad
+	opI32Extend8S   = 0xc0
+	opI32Extend16S  = 0xc1
+	opI64Extend8S   = 0xc2
+	opI64Extend16S  = 0xc3
+	opI64Extend32S  = 0xc4
+
+	// prefixed instructions are encoded as prefix<<8 | sub opcode
+	opPrefixMisc = 0xfc
+	opMemoryCopy = opPrefixMisc<<8 | 10
+	opMemoryFill = opPrefixMisc<<8 | 11
+)
+
+type signature struct {
+	params  []valueType
+	results []valueType
+}
+
+var (
+	// signatures of the instructions without immediates which take values from the stack and push the result back
+	numericSignatures = map[uint16]signature{}
+	// memory access size of load and store instructions
+	memoryAccessSize = map[uint16]uint32{}
+)
+
+func init() {
+	add := func(from, to uint16, params []valueType, results ...valueType) {
+		for op := from; op <= to; op++ {
+			numericSignatures[op] = signature{params: params, results: results}
+		}
+	}
+	add(opI32Eqz, opI32Eqz, []valueType{i32}, i32)
+	add(opI32Eq, opI32GeU, []valueType{i32, i32}, i32)
+	add(opI64Eqz, opI64Eqz, []valueType{i64}, i32)
+	add(opI64Eq, opI64GeU, []valueType{i64, i64}, i32)
+	add(opI32Clz, opI32Popcnt, []valueType{i32}, i32)
+	add(opI32Add, opI32Rotr, []valueType{i32, i32}, i32)
+	add(opI64Clz, opI64Popcnt, []valueType{i64}, i64)
+	add(opI64Add, opI64Rotr, []valueType{i64, i64}, i64)
+	add(opI32WrapI64, opI32WrapI64, []valueType{i64}, i32)
+	add(opI64ExtendI32S, opI64ExtendI32U, []valueType{i32}, i64)
+	add(opI32Extend8S, opI32Extend16S, []valueType{i32}, i32)
+	add(opI64Extend8S, opI64Extend32S, []valueType{i64}, i64)
+
+	load := func(op uint16, size uint32, t valueType) {
+		numericSignatures[op] = signature{params: []valueType{i32}, results: []valueType{t}}
+		memoryAccessSize[op] = size
+	}
+	load(opI32Load, 4, i32)
+	load(opI64Load, 8, i64)
+	load(opI32Load8S, 1, i32)
+	load(opI32Load8U, 1, i32)
+	load(opI32Load16S, 2, i32)
+	load(opI32Load16U, 2, i32)
+	load(opI64Load8S, 1, i64)
+	load(opI64Load8U, 1, i64)
+	load(opI64Load16S, 2, i64)
+	load(opI64Load16U, 2, i64)
+	load(opI64Load32S, 4, i64)
+	load(opI64Load32U, 4, i64)
+
+	store := func(op uint16, size uint32, t valueType) {
+		numericSignatures[op] = signature{params: []valueType{i32, t}}
+		memoryAccessSize[op] = size
+	}
+	store(opI32Store, 4, i32)
+	store(opI64Store, 8, i64)
+	store(opI32Store8, 1, i32)
+	store(opI32Store16, 2, i32)
+	store(opI64Store8, 1, i64)
+	store(opI64Store16, 2, i64)
+	store(opI64Store32, 4, i64)
+}
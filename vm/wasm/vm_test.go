@@ -0,0 +1,94 @@
+package wasm_test
+
+import (
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/vm"
+	"github.com/idena-network/idena-go/vm/wasm"
+	"github.com/stretchr/testify/require"
+	db2 "github.com/tendermint/tm-db"
+	"testing"
+)
+
+func TestVmImpl_WasmContract(t *testing.T) {
+	code := wasm.CounterModule()
+	codeHash := crypto.Hash(code)
+	key, _ := crypto.GenerateKey()
+	appState, _ := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	header := &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2, Time: 10}}
+	consensus := *config.ConsensusVersions[config.ConsensusV8]
+	cfg := &config.Config{Consensus: &consensus}
+	table := consensus.GasTable
+
+	nonce := uint32(0)
+	signTx := func(key *ecdsa.PrivateKey, txType types.TxType, to *common.Address, payload []byte) *types.Transaction {
+		nonce++
+		tx, _ := types.SignTx(&types.Transaction{AccountNonce: nonce, Type: txType, To: to, Amount: common.DnaBase, Payload: payload}, key)
+		return tx
+	}
+	run := func(tx *types.Transaction, gasLimit int64) *types.TxReceipt {
+		return vm.NewVmImpl(appState, header, nil, cfg).Run(tx, gasLimit)
+	}
+	read := func(contract common.Address, method string) []byte {
+		data, err := vm.NewVmImpl(appState, header, nil, cfg).Read(contract, method)
+		require.NoError(t, err)
+		return data
+	}
+
+	payload, _ := attachments.CreateDeployContractAttachment(codeHash, code, common.ToBytes(uint64(41))).ToBytes()
+	deployTx := signTx(key, types.DeployContractTx, nil, payload)
+
+	// wasm contracts are not available before the consensus v8
+	v7 := *config.ConsensusVersions[config.ConsensusV7]
+	receipt := vm.NewVmImpl(appState, header, nil, &config.Config{Consensus: &v7}).Run(deployTx, -1)
+	require.False(t, receipt.Success)
+	require.EqualError(t, receipt.Error, "unknown contract")
+
+	receipt = run(deployTx, -1)
+	require.True(t, receipt.Success, receipt.Error)
+	contract := receipt.ContractAddress
+	require.Equal(t, common.Hash(codeHash), *appState.State.GetCodeHash(contract))
+	require.Equal(t, code, appState.State.GetContractCode(codeHash))
+	require.Equal(t, common.ToBytes(uint64(41)), read(contract, "get"))
+	// the code, the deployment, the memory of the instance and the stored value are charged besides the instructions
+	fixedGas := table.CodeWriteByte*len(code) + table.Deploy + table.WasmMemoryPage + table.StorageWriteByte*(1+8)
+	require.Greater(t, receipt.GasUsed, uint64(fixedGas))
+
+	callPayload := func(method string) []byte {
+		payload, _ := attachments.CreateCallContractAttachment(method).ToBytes()
+		return payload
+	}
+	receipt = run(signTx(key, types.CallContractTx, &contract, callPayload("inc")), -1)
+	require.True(t, receipt.Success, receipt.Error)
+	require.Equal(t, "inc", receipt.Method)
+	callGas := receipt.GasUsed
+	require.Equal(t, common.ToBytes(uint64(42)), read(contract, "get"))
+
+	// the same call is charged the same gas, it fails without changes if the gas is not enough
+	receipt = run(signTx(key, types.CallContractTx, &contract, callPayload("inc")), int64(callGas)-1)
+	require.False(t, receipt.Success)
+	require.EqualError(t, receipt.Error, "not enough gas")
+	require.Equal(t, callGas-1, receipt.GasUsed)
+	require.Equal(t, common.ToBytes(uint64(42)), read(contract, "get"))
+
+	receipt = run(signTx(key, types.CallContractTx, &contract, callPayload("inc")), int64(callGas))
+	require.True(t, receipt.Success, receipt.Error)
+	require.Equal(t, callGas, receipt.GasUsed)
+	require.Equal(t, common.ToBytes(uint64(43)), read(contract, "get"))
+
+	// the endless loop is stopped by the gas limit
+	receipt = run(signTx(key, types.CallContractTx, &contract, callPayload("spin")), 100000)
+	require.False(t, receipt.Success)
+	require.Equal(t, uint64(100000), receipt.GasUsed)
+
+	receipt = run(signTx(key, types.CallContractTx, &contract, callPayload("divide")), -1)
+	require.False(t, receipt.Success)
+	require.EqualError(t, receipt.Error, "integer divide by zero")
+	require.Equal(t, common.ToBytes(uint64(43)), read(contract, "get"))
+}
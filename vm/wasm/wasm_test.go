@@ -0,0 +1,281 @@
+package wasm
+
+import (
+	"context"
+	"github.com/idena-network/idena-go/blockchain/attachments"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/vm/env"
+	"github.com/stretchr/testify/require"
+	db2 "github.com/tendermint/tm-db"
+	"math/rand"
+	"testing"
+)
+
+// helpers to assemble modules in the binary format
+
+func leb(v uint32) []byte {
+	var result []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		result = append(result, b)
+		if v == 0 {
+			return result
+		}
+	}
+}
+
+func concat(items ...[]byte) []byte {
+	var result []byte
+	for _, item := range items {
+		result = append(result, item...)
+	}
+	return result
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(leb(uint32(len(items))), concat(items...))
+}
+
+func str(s string) []byte {
+	return concat(leb(uint32(len(s))), []byte(s))
+}
+
+func section(id byte, items ...[]byte) []byte {
+	content := vec(items...)
+	return concat([]byte{id}, leb(uint32(len(content))), content)
+}
+
+func typeEntry(params []byte, results []byte) []byte {
+	return concat([]byte{funcTypeTag}, leb(uint32(len(params))), params, leb(uint32(len(results))), results)
+}
+
+func body(locals []byte, code ...[]byte) []byte {
+	b := concat(locals, concat(code...), []byte{opEnd})
+	return concat(leb(uint32(len(b))), b)
+}
+
+func i32Const(v uint32) []byte {
+	// values below 64 have the same signed and unsigned encoding
+	return concat([]byte{opI32Const}, leb(v))
+}
+
+func call(idx uint32) []byte {
+	return concat([]byte{opCall}, leb(idx))
+}
+
+func module(sections ...[]byte) []byte {
+	return concat([]byte(magic), []byte(version), concat(sections...))
+}
+
+// funcModule assembles the module with the single exported function "run" without params,
+// sections are placed between the function and the export sections
+func funcModule(results []byte, sections [][]byte, locals []byte, code ...[]byte) []byte {
+	return module(
+		section(sectionType, typeEntry(nil, results)),
+		section(sectionFunction, []byte{0}),
+		concat(sections...),
+		section(sectionExport, concat(str("run"), []byte{externalFunction, 0})),
+		section(sectionCode, body(locals, code...)),
+	)
+}
+
+const (
+	fnArgRead = iota
+	fnSetValue
+	fnGetValue
+	fnSetReturn
+	fnAbort
+	fnDeploy
+	fnInc
+	fnGet
+	fnSum
+	fnSpin
+	fnDivide
+)
+
+// counterModule stores the counter under the "n" key, its memory layout is the key at 0 and the value at 8
+func counterModule() []byte {
+	i32x2, i32x4 := []byte{byte(i32), byte(i32)}, []byte{byte(i32), byte(i32), byte(i32), byte(i32)}
+	return module(
+		section(sectionType,
+			typeEntry(i32x2, nil),
+			typeEntry(i32x4, nil),
+			typeEntry(i32x4, []byte{byte(i32)}),
+			typeEntry(nil, nil),
+		),
+		section(sectionImport,
+			concat(str("env"), str("arg_read"), []byte{externalFunction, 0}),
+			concat(str("env"), str("set_value"), []byte{externalFunction, 1}),
+			concat(str("env"), str("get_value"), []byte{externalFunction, 2}),
+			concat(str("env"), str("set_return"), []byte{externalFunction, 0}),
+			concat(str("env"), str("abort"), []byte{externalFunction, 0}),
+		),
+		section(sectionFunction, []byte{3}, []byte{3}, []byte{3}, []byte{3}, []byte{3}, []byte{3}),
+		section(sectionMemory, []byte{0, 1}),
+		section(sectionExport,
+			concat(str("deploy"), []byte{externalFunction, fnDeploy}),
+			concat(str("inc"), []byte{externalFunction, fnInc}),
+			concat(str("get"), []byte{externalFunction, fnGet}),
+			concat(str("sum"), []byte{externalFunction, fnSum}),
+			concat(str("spin"), []byte{externalFunction, fnSpin}),
+			concat(str("divide"), []byte{externalFunction, fnDivide}),
+		),
+		section(sectionCode,
+			// deploy: n = args[0]
+			body([]byte{0},
+				i32Const(0), i32Const(8), call(fnArgRead),
+				i32Const(0), i32Const(1), i32Const(8), i32Const(8), call(fnSetValue),
+			),
+			// inc: n = n + 1, aborts if n is missing
+			body([]byte{0},
+				i32Const(0), i32Const(1), i32Const(8), i32Const(8), call(fnGetValue),
+				i32Const(8), []byte{opI32Ne, opIf, emptyBlock},
+				i32Const(0), i32Const(1), call(fnAbort),
+				[]byte{opEnd},
+				i32Const(8),
+				i32Const(8), []byte{opI64Load, 3, 0},
+				[]byte{opI64Const, 1, opI64Add},
+				[]byte{opI64Store, 3, 0},
+				i32Const(0), i32Const(1), i32Const(8), i32Const(8), call(fnSetValue),
+			),
+			// get: returns n
+			body([]byte{0},
+				i32Const(0), i32Const(1), i32Const(8), i32Const(8), call(fnGetValue),
+				[]byte{opDrop},
+				i32Const(8), i32Const(8), call(fnSetReturn),
+			),
+			// sum: returns 1 + 2 + ... + args[0]
+			body(concat([]byte{1}, leb(2), []byte{byte(i64)}),
+				i32Const(0), i32Const(16), call(fnArgRead),
+				i32Const(0), []byte{opI64Load, 3, 16, opLocalSet, 0},
+				[]byte{opBlock, emptyBlock, opLoop, emptyBlock},
+				[]byte{opLocalGet, 0, opI64Eqz, opBrIf, 1},
+				[]byte{opLocalGet, 1, opLocalGet, 0, opI64Add, opLocalSet, 1},
+				[]byte{opLocalGet, 0, opI64Const, 1, opI64Sub, opLocalSet, 0},
+				[]byte{opBr, 0, opEnd, opEnd},
+				i32Const(16), []byte{opLocalGet, 1, opI64Store, 3, 0},
+				i32Const(16), i32Const(8), call(fnSetReturn),
+			),
+			// spin: infinite loop
+			body([]byte{0}, []byte{opLoop, emptyBlock, opBr, 0, opEnd}),
+			// divide: traps on division by zero
+			body([]byte{0}, i32Const(1), i32Const(0), []byte{opI32DivU, opDrop}),
+		),
+		section(sectionData, concat([]byte{0}, i32Const(0), []byte{opEnd}, str("n"))),
+	)
+}
+
+func createTestEnv(t *testing.T) (env.CallContext, *env.EnvImp, *env.GasCounter) {
+	rnd := rand.New(rand.NewSource(1))
+	key, _ := crypto.GenerateKeyFromSeed(rnd)
+	attachment := attachments.CreateDeployContractAttachment(common.Hash{0x10})
+	payload, _ := attachment.ToBytes()
+	tx := &types.Transaction{
+		AccountNonce: 1,
+		Type:         types.DeployContractTx,
+		Amount:       common.DnaBase,
+		Payload:      payload,
+	}
+	tx, _ = types.SignTx(tx, key)
+
+	appState, err := appstate.NewAppState(db2.NewMemDB(), eventbus.New())
+	require.NoError(t, err)
+	header := &types.Header{ProposedHeader: &types.ProposedHeader{Height: 2, Time: 10}}
+	gas := new(env.GasCounter)
+	gas.Reset(-1)
+	return env.NewDeployContextImpl(tx, attachment.CodeHash), env.NewEnvImp(appState, header, gas, nil), gas
+}
+
+func TestContract(t *testing.T) {
+	m, err := Parse(counterModule())
+	require.NoError(t, err)
+
+	ctx, e, gas := createTestEnv(t)
+	contract := NewContract(ctx, e, gas, nil, m)
+
+	require.NoError(t, contract.Deploy(common.ToBytes(uint64(41))))
+	require.NoError(t, contract.Call("inc"))
+	data, err := contract.Read("get")
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(42)), data)
+	require.Equal(t, common.ToBytes(uint64(42)), e.GetValue(ctx, []byte("n")))
+
+	data, err = contract.Read("sum", common.ToBytes(uint64(100)))
+	require.NoError(t, err)
+	require.Equal(t, common.ToBytes(uint64(5050)), data)
+
+	require.EqualError(t, contract.Call("divide"), "integer divide by zero")
+	require.EqualError(t, contract.Call("unknown"), "unknown method")
+	require.EqualError(t, contract.Call("deploy"), "unknown method")
+	_, err = contract.Terminate()
+	require.EqualError(t, err, "unknown method")
+
+	e.RemoveValue(ctx, []byte("n"))
+	require.EqualError(t, contract.Call("inc"), "n")
+
+	gas.Reset(100000)
+	require.PanicsWithValue(t, "not enough gas", func() {
+		contract.Call("spin")
+	})
+}
+
+func TestContract_Interrupt(t *testing.T) {
+	m, err := Parse(counterModule())
+	require.NoError(t, err)
+
+	ctx, e, gas := createTestEnv(t)
+	interrupt, cancel := context.WithCancel(context.Background())
+	cancel()
+	contract := NewContract(ctx, e, gas, interrupt, m)
+
+	// the unlimited gas doesn't let the endless loop hang the caller
+	gas.Reset(-1)
+	require.EqualError(t, contract.Call("spin"), "execution is interrupted")
+}
+
+func TestContract_GasTable(t *testing.T) {
+	m, err := Parse(counterModule())
+	require.NoError(t, err)
+	ctx, e, gas := createTestEnv(t)
+
+	// storage operations are charged by the env counter, so separate counters measure the interpreter costs only
+	readGas := func(table config.GasTable) int {
+		counter := env.NewGasCounter(&table)
+		counter.Reset(-1)
+		_, err := NewContract(ctx, e, counter, nil, m).Read("sum", common.ToBytes(uint64(10)))
+		require.NoError(t, err)
+		return counter.UsedGas
+	}
+	table := *gas.Table()
+	defaultGas := readGas(table)
+	table.WasmInstruction *= 2
+	table.WasmCall *= 2
+	table.WasmMemoryPage *= 2
+	require.Greater(t, readGas(table), defaultGas)
+}
+
+func TestParseCached(t *testing.T) {
+	code := counterModule()
+	hash := crypto.Hash(code)
+	m, err := ParseCached(hash, code)
+	require.NoError(t, err)
+
+	// the module is not parsed again
+	cached, err := ParseCached(hash, nil)
+	require.NoError(t, err)
+	require.True(t, m == cached)
+
+	_, err = ParseCached(common.Hash{0x1}, []byte{0x1})
+	require.Error(t, err)
+	_, ok := moduleCache.Get(common.Hash{0x1})
+	require.False(t, ok)
+}